@@ -3,11 +3,13 @@ package discord
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/oklahomer/go-kasumi/logger"
 	"github.com/oklahomer/go-sarah/v4"
 )
 
@@ -23,8 +25,39 @@ type session interface {
 	AddHandler(handler interface{}) func()
 	Open() error
 	Close() error
+	CloseWithCode(closeCode int) error
 	ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
 	ChannelMessageSendComplex(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageEdit(channelID string, messageID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error)
+	GuildMember(guildID string, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error)
+	MessageReactionAdd(channelID string, messageID string, emojiID string, options ...discordgo.RequestOption) error
+	ChannelMessage(channelID string, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	GuildMemberDeleteWithReason(guildID string, userID string, reason string, options ...discordgo.RequestOption) error
+	GuildBanCreateWithReason(guildID string, userID string, reason string, days int, options ...discordgo.RequestOption) error
+	GuildMemberRoleAdd(guildID string, userID string, roleID string, options ...discordgo.RequestOption) error
+	GuildMemberRoleRemove(guildID string, userID string, roleID string, options ...discordgo.RequestOption) error
+	ChannelMessages(channelID string, limit int, beforeID string, afterID string, aroundID string, options ...discordgo.RequestOption) ([]*discordgo.Message, error)
+	ChannelMessagePin(channelID string, messageID string, options ...discordgo.RequestOption) error
+	ChannelMessageUnpin(channelID string, messageID string, options ...discordgo.RequestOption) error
+	ChannelMessagesBulkDelete(channelID string, messages []string, options ...discordgo.RequestOption) error
+	MessageThreadStartComplex(channelID string, messageID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ThreadStartComplex(channelID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ChannelEditComplex(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	GuildInvites(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Invite, error)
+	InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error
+	GuildEmojis(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Emoji, error)
+	ApplicationEmojis(appID string, options ...discordgo.RequestOption) ([]*discordgo.Emoji, error)
+	ChannelTyping(channelID string, options ...discordgo.RequestOption) error
+	ChannelMessageDelete(channelID string, messageID string, options ...discordgo.RequestOption) error
+	InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	UpdateStatusComplex(usd discordgo.UpdateStatusData) error
+	User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error)
+	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error)
+	ApplicationCommandCreate(appID string, guildID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error)
+	ApplicationCommandDelete(appID string, guildID string, cmdID string, options ...discordgo.RequestOption) error
 }
 
 // ChannelID represents a Discord channel as sarah.OutputDestination.
@@ -44,10 +77,62 @@ func WithSession(session *discordgo.Session) AdapterOption {
 	}
 }
 
+// WithIntents creates an AdapterOption that overrides Config.Intents with the given intents.
+// The intents are validated and applied to the session that NewAdapter creates,
+// while a session given via WithSession is used as it is.
+func WithIntents(intents discordgo.Intent) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.intents = &intents
+	}
+}
+
+// WithStateEnabled creates an AdapterOption that enables or disables discordgo's state cache of guilds, channels, members, and messages.
+// Disabling the cache saves memory on a bot in many or large guilds, but channels and members are then fetched via the REST API when needed,
+// e.g., to resolve permissions, at the cost of latency and rate limits.
+// The bot's own user is still tracked, so messages from the bot itself are ignored either way.
+// This applies to the session that NewAdapter creates as well as the one given via WithSession.
+func WithStateEnabled(enabled bool) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.stateEnabled = &enabled
+	}
+}
+
 // Adapter is a sarah.Adapter implementation for Discord.
 type Adapter struct {
-	config  *Config
-	session session
+	config            *Config
+	session           session
+	guildEnabledStore GuildEnabledStore
+	intents           *discordgo.Intent
+	stateEnabled      *bool
+	logger            Logger
+	stats             statsRecorder
+	dedup             sendDeduplicator
+	threads           threadRegistry
+	lastActivity      atomic.Int64 // Unix time in nanoseconds
+	helpMenus         helpMenuRegistry
+	senderQueues      senderQueues
+	channelQueues     channelQueues
+
+	autoResponseCooldown cooldownTracker
+	enqueueErrorHandler  func(sarah.Input, error)
+	metricsHook          MetricsHook
+	rawHandlers          []interface{}
+	guildCommands        guildCommandRegistry
+	ownDisconnects       atomic.Int32 // Disconnect events caused by the Adapter's own Close
+
+	emojis   emojiCache
+	channels channelCache
+	appID    atomic.Value
+	botID    atomic.Value
+
+	activityTurn atomic.Uint64
+
+	dmChannels      dmChannelRegistry
+	blockedUsers    idSet
+	allowedChannels idSet
+	blockedChannels idSet
+	sendLimiter     sendLimiter
+	sends           inFlightSends // In-flight sends to wait for on shutdown
 }
 
 var _ sarah.Adapter = (*Adapter)(nil)
@@ -62,19 +147,41 @@ func NewAdapter(config *Config, options ...AdapterOption) (*Adapter, error) {
 		opt(adapter)
 	}
 
-	if adapter.session == nil {
-		if config.Token == "" {
-			return nil, ErrEmptyToken
-		}
+	if adapter.session == nil && config.Token == "" {
+		return nil, ErrEmptyToken
+	}
 
+	// Validate a copy so the caller's Config is not modified by WithIntents.
+	effective := *config
+	if adapter.intents != nil {
+		effective.Intents = *adapter.intents
+	}
+	if err := effective.validate(false); err != nil {
+		return nil, err
+	}
+
+	if adapter.session == nil {
 		s, err := discordgo.New("Bot " + config.Token)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create Discord session: %w", err)
 		}
-		s.Identify.Intents = config.Intents
+		s.Identify.Intents = effective.Intents
+		if config.ShardCount > 0 {
+			s.ShardID = config.ShardID
+			s.ShardCount = config.ShardCount
+		}
 		adapter.session = s
 	}
 
+	// The flag is read as events arrive, so it is set here before Run opens the session.
+	if s, ok := adapter.session.(*discordgo.Session); ok && adapter.stateEnabled != nil {
+		s.StateEnabled = *adapter.stateEnabled
+	}
+
+	if config.EnableGuildToggle && adapter.guildEnabledStore == nil {
+		adapter.guildEnabledStore = NewInMemoryGuildEnabledStore()
+	}
+
 	return adapter, nil
 }
 
@@ -83,105 +190,590 @@ func (a *Adapter) BotType() sarah.BotType {
 	return DISCORD
 }
 
+// Session returns the underlying *discordgo.Session to call Discord APIs that the Adapter does not wrap, e.g., fetching guild members.
+// This returns nil when the Adapter works with another implementation of the session, e.g., in tests.
+// The session is safe for concurrent use, and its handlers run concurrently with the Adapter's.
+// Do not close or reopen the session yourself since the Adapter manages the connection in Run.
+func (a *Adapter) Session() *discordgo.Session {
+	s, ok := a.session.(*discordgo.Session)
+	if !ok {
+		return nil
+	}
+	return s
+}
+
 // Run establishes a connection with Discord and blocks until the context is canceled.
+// When Config.ReconnectOnInvalidSession is true, the session is reopened and resumed when the gateway connection is dropped.
+// When Config.ZombieTimeout is positive, the session is restarted when nothing is received for the duration.
 func (a *Adapter) Run(ctx context.Context, enqueueInput func(sarah.Input) error, notifyErr func(error)) {
 	a.session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
-		a.handleMessage(s, m, enqueueInput)
+		if !a.config.SerializePerSender || m.Author == nil {
+			a.handleMessage(ctx, s, m, enqueueInput)
+			return
+		}
+
+		// discordgo calls each handler in its own goroutine, so messages from the same sender may otherwise be handled out of order.
+		dispatched := a.senderQueues.dispatch(senderKeyOf(m.ChannelID, m.Author.ID), a.config.MaxSenderQueues, func() {
+			a.handleMessage(ctx, s, m, enqueueInput)
+		})
+		if !dispatched {
+			a.log().Warnf("Dropping message %s since %s has too many pending messages", m.ID, senderKeyOf(m.ChannelID, m.Author.ID))
+			a.messageReceived()
+			a.messageDropped(fmt.Errorf("too many pending messages from %s", senderKeyOf(m.ChannelID, m.Author.ID)))
+		}
+	})
+
+	if a.config.HandleEdits {
+		a.session.AddHandler(func(s *discordgo.Session, m *discordgo.MessageUpdate) {
+			a.handleMessageUpdate(ctx, s, m, enqueueInput)
+		})
+	}
+
+	if a.config.HandleDeletes {
+		a.session.AddHandler(func(_ *discordgo.Session, m *discordgo.MessageDelete) {
+			a.handleMessageDelete(ctx, m, enqueueInput)
+		})
+	}
+
+	if a.config.HandleReactions || len(a.config.ReactionCommands) > 0 {
+		a.session.AddHandler(func(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+			a.handleReactionAdd(ctx, s, r, enqueueInput)
+		})
+	}
+
+	if a.config.HandleMemberJoin {
+		a.session.AddHandler(func(_ *discordgo.Session, m *discordgo.GuildMemberAdd) {
+			a.handleMemberJoin(ctx, m, enqueueInput)
+		})
+	}
+
+	a.session.AddHandler(func(_ *discordgo.Session, g *discordgo.GuildDelete) {
+		a.handleGuildDelete(g)
 	})
 
-	err := a.session.Open()
+	a.session.AddHandler(func(_ *discordgo.Session, r *discordgo.Ready) {
+		a.handleReady(r)
+	})
+
+	a.session.AddHandler(func(_ *discordgo.Session, e *discordgo.GuildEmojisUpdate) {
+		a.emojis.forget(e.GuildID)
+	})
+
+	a.session.AddHandler(func(_ *discordgo.Session, c *discordgo.ChannelUpdate) {
+		a.channels.forget(c.ID)
+	})
+
+	a.session.AddHandler(func(_ *discordgo.Session, c *discordgo.ChannelDelete) {
+		a.channels.forget(c.ID)
+	})
+
+	if a.config.HelpAsSelectMenu {
+		a.session.AddHandler(func(_ *discordgo.Session, i *discordgo.InteractionCreate) {
+			a.handleHelpMenuSelection(ctx, i)
+		})
+	}
+
+	a.session.AddHandler(func(_ *discordgo.Session, i *discordgo.InteractionCreate) {
+		a.handleInteraction(ctx, i, enqueueInput)
+	})
+
+	for _, handler := range a.rawHandlers {
+		a.session.AddHandler(handler)
+	}
+
+	// A nil channel blocks forever, so disconnections are ignored unless the reconnection is enabled.
+	var disconnected <-chan struct{}
+	if a.config.ReconnectOnInvalidSession {
+		disconnected = a.watchDisconnection()
+	}
+
+	a.warnMissingMessageContent()
+
+	var watchdog <-chan time.Time
+	if a.config.ZombieTimeout > 0 {
+		var stop func()
+		watchdog, stop = a.watchActivity()
+		defer stop()
+	}
+
+	err := a.open(ctx)
 	if err != nil {
 		notifyErr(sarah.NewBotNonContinuableError(fmt.Sprintf("failed to open Discord session: %s", err.Error())))
 		return
 	}
+	a.touch()
+	a.fetchBotUser(ctx)
+	a.applyPresence()
+
+	if len(a.config.ActivityRotation) > 0 {
+		stop := a.rotateActivity(ctx)
+		defer stop()
+	}
 
-	// Block until the context is canceled.
-	<-ctx.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			a.drainSends()
+			if closeErr := a.session.Close(); closeErr != nil {
+				a.log().Errorf("Failed to close Discord session: %+v", closeErr)
+			}
+			return
 
-	if closeErr := a.session.Close(); closeErr != nil {
-		logger.Errorf("Failed to close Discord session: %+v", closeErr)
+		case <-disconnected:
+			// The gateway closed the connection or invalidated the session, so open a new connection.
+			a.log().Warnf("Discord session is disconnected. Reconnecting.")
+			err := a.reconnect(ctx)
+			if err != nil && ctx.Err() == nil {
+				notifyErr(sarah.NewBotNonContinuableError(fmt.Sprintf("failed to reopen Discord session: %s", err.Error())))
+				return
+			}
+
+		case now := <-watchdog:
+			if !a.isZombie(now) {
+				continue
+			}
+
+			// The connection looks open but receives nothing, so discordgo's heartbeat failed to detect the failure.
+			a.log().Warnf("Nothing is received from Discord since %s. Restarting the session.", a.lastActive())
+			err := a.restart(ctx)
+			if err != nil && ctx.Err() == nil {
+				notifyErr(sarah.NewBotNonContinuableError(fmt.Sprintf("failed to restart Discord session: %s", err.Error())))
+				return
+			}
+		}
 	}
 }
 
 // handleMessage processes an incoming Discord message and routes it to enqueueInput.
-func (a *Adapter) handleMessage(s *discordgo.Session, m *discordgo.MessageCreate, enqueueInput func(sarah.Input) error) {
+// Messages received after the given context is canceled are dropped since go-sarah is shutting down and no longer accepts inputs.
+func (a *Adapter) handleMessage(ctx context.Context, s *discordgo.Session, m *discordgo.MessageCreate, enqueueInput func(sarah.Input) error) {
+	a.messageReceived()
+
+	if ctx.Err() != nil {
+		a.log().Debugf("Skipping message %s received during shutdown", m.ID)
+		a.messageDropped(ctx.Err())
+		return
+	}
+
+	// Ignore old messages replayed after the gateway resumes so their commands are not executed again.
+	if a.config.MaxMessageAge > 0 && !m.Timestamp.IsZero() && time.Since(m.Timestamp) > a.config.MaxMessageAge {
+		a.log().Debugf("Skipping message %s sent at %s", m.ID, m.Timestamp)
+		a.messageDropped(nil)
+		return
+	}
+
 	input, err := MessageToInput(m)
 	if err != nil {
 		// MessageToInput returns ErrNoAuthor for system messages with no author.
-		logger.Debugf("Skipping message: %+v", err)
+		a.log().Debugf("Skipping message: %+v", err)
+		a.messageDropped(err)
 		return
 	}
+	input.adapter = a
+	input.correlationID = a.correlationID(m)
+	input.runCtx = ctx
 
 	// Ignore messages from the bot itself.
-	if s.State != nil && s.State.User != nil && m.Author.ID == s.State.User.ID {
+	botID := a.selfID(s)
+	if botID != "" && m.Author.ID == botID {
+		a.messageDropped(nil)
 		return
 	}
+	input.botID = botID
+	input.mentionsMe = slices.ContainsFunc(input.mentions, func(user *discordgo.User) bool {
+		return user != nil && botID != "" && user.ID == botID
+	})
 
-	var enqueueErr error
+	// Ignore messages from blocked users.
+	if a.userBlocked(m.Author.ID) {
+		a.log().Debugf("[%s] Ignoring message %s from blocked user %s", input.correlationID, m.ID, m.Author.ID)
+		a.messageDropped(nil)
+		return
+	}
+
+	// Ignore messages from other bots to prevent loops between bots.
+	if a.config.IgnoreBots && m.Author.Bot {
+		a.log().Debugf("[%s] Ignoring message %s from bot %s", input.correlationID, m.ID, m.Author.ID)
+		a.messageDropped(nil)
+		return
+	}
+
+	// Ignore messages in channels that are blocked or not allowed.
+	if !a.channelAllowed(m.ChannelID) {
+		a.log().Debugf("[%s] Ignoring message %s in channel %s", input.correlationID, m.ID, m.ChannelID)
+		a.messageDropped(nil)
+		return
+	}
+
+	// Ignore messages from guilds where the bot is disabled.
+	if !a.guildEnabled(input) {
+		a.messageDropped(nil)
+		return
+	}
+	a.hintEmptyContent(m)
+
+	// Strip the prefix or the bot mention to resolve the command text.
+	text, invoked := a.resolveInvocation(s, m)
+	if !invoked && a.config.RequireInvocation {
+		a.messageDropped(nil)
+		return
+	}
+	if a.config.SanitizeIncomingMentions {
+		text = SanitizeMentions(text)
+	}
+	input.text = text
+	input.invoked = invoked
+
+	// Help and abort commands are recognized either as they are or after the prefix or the mention.
+	// When CommandPrefix is set, they must be invoked like any other command in a guild, e.g., "!help" for HelpCommand "help",
+	// unless the command itself starts with the prefix.
 	trimmed := strings.TrimSpace(input.Message())
-	if a.config.HelpCommand != "" && trimmed == a.config.HelpCommand {
-		enqueueErr = enqueueInput(sarah.NewHelpInput(input))
-	} else if a.config.AbortCommand != "" && trimmed == a.config.AbortCommand {
-		enqueueErr = enqueueInput(sarah.NewAbortInput(input))
+	raw := strings.TrimSpace(m.Content)
+	matches := func(text string, command string) bool {
+		if a.config.CaseInsensitiveCommands {
+			return strings.EqualFold(text, command)
+		}
+		return text == command
+	}
+	isCommand := func(command string) bool {
+		if command == "" {
+			return false
+		}
+		if a.config.CommandPrefix == "" {
+			return matches(trimmed, command) || matches(raw, command)
+		}
+		return (invoked && matches(trimmed, command)) || (matches(raw, command) && strings.HasPrefix(command, a.config.CommandPrefix))
+	}
+	// A custom matcher replaces the comparison with the command string, but is still subject to the prefix.
+	isMatched := func(matcher func(string) bool, command string) bool {
+		if matcher == nil {
+			return isCommand(command)
+		}
+		if a.config.CommandPrefix == "" {
+			return matcher(trimmed) || matcher(raw)
+		}
+		return (invoked && matcher(trimmed)) || (matcher(raw) && strings.HasPrefix(raw, a.config.CommandPrefix))
+	}
+	isHelp := isMatched(a.config.HelpMatcher, a.config.HelpCommand)
+	isAbort := !isHelp && isMatched(a.config.AbortMatcher, a.config.AbortCommand)
+
+	// Keywords are answered by the adapter itself without involving go-sarah's command dispatch.
+	isBuiltIn := isHelp || isAbort
+	_, explicit := a.stripInvocation(s, m.Content)
+	if !isBuiltIn && (!explicit || a.config.AutoRespondToInvocations) && a.autoRespond(input, input.Message()) {
+		a.messageDropped(nil)
+		return
+	}
+
+	var enqueued sarah.Input = input
+	var enqueueErr error
+	if isHelp {
+		enqueued = sarah.NewHelpInput(input)
+		enqueueErr = enqueueInput(enqueued)
+	} else if isAbort {
+		enqueued = sarah.NewAbortInput(input)
+		enqueueErr = enqueueInput(enqueued)
+		if enqueueErr == nil {
+			// Aborting ends the conversation without any response, so archive the thread right away.
+			a.completeConversation(input)
+			a.archiveCompletedThread(input.Context(), string(input.channelID))
+		}
 	} else {
+		// Only explicit invocations are likely to be commands, so the bot does not look busy on every chat message.
+		if invoked {
+			a.showTyping(input)
+		}
 		enqueueErr = enqueueInput(input)
 	}
 	if enqueueErr != nil {
-		logger.Errorf("Failed to enqueue input: %+v", enqueueErr)
+		a.enqueueFailed(enqueued, input.correlationID, "input", enqueueErr)
+		a.messageDropped(enqueueErr)
+		return
 	}
+	a.log().Debugf("[%s] Enqueued message %s from %s", input.correlationID, m.ID, input.senderKey)
+	a.messageEnqueued()
 }
 
 // SendMessage sends the given message to Discord.
-func (a *Adapter) SendMessage(_ context.Context, output sarah.Output) {
+// Failures are logged since sarah.Adapter does not let this method return an error.
+// Use SendMessageWithError to observe the failure.
+// With Config.SerializePerChannel, the message is queued and sent by the channel's worker goroutine after the preceding messages to the channel,
+// so this returns without waiting for it. The message is still sent with the given context.
+func (a *Adapter) SendMessage(ctx context.Context, output sarah.Output) {
+	// An interaction is responded to only once, so there is nothing to keep in order.
+	destination := output.Destination()
+	if _, ok := destination.(*InteractionDestination); ok || !a.config.SerializePerChannel {
+		if err := a.SendMessageWithError(ctx, output); err != nil {
+			a.log().Errorf("Failed to send message: %+v", err)
+		}
+		return
+	}
+
+	// Count the send when it is queued so the shutdown also waits for the pending sends.
+	if !a.sends.begin() {
+		a.log().Warnf("Dropping message to %v since the Adapter is shutting down", destination)
+		a.messageSent(destination, ErrShuttingDown)
+		return
+	}
+
+	send := func() {
+		defer a.sends.end()
+		if err := a.sendMessage(ctx, output); err != nil {
+			a.log().Errorf("Failed to send message: %+v", err)
+		}
+	}
+	if !a.channelQueues.enqueue(fmt.Sprintf("%T:%v", destination, destination), send) {
+		a.sends.end()
+		a.log().Warnf("Dropping message to %v since too many messages are pending for the channel", destination)
+		a.messageSent(destination, ErrSendQueueFull)
+	}
+}
+
+// SendMessageWithError sends the given message to Discord and returns the error if the send fails.
+// A message suppressed as a duplicate by Config.SuppressDuplicateSends is not an error.
+// With Config.DryRun, the message is logged instead of being sent.
+// ErrShuttingDown is returned once the Adapter starts shutting down.
+func (a *Adapter) SendMessageWithError(ctx context.Context, output sarah.Output) error {
+	if !a.sends.begin() {
+		a.messageSent(output.Destination(), ErrShuttingDown)
+		return ErrShuttingDown
+	}
+	defer a.sends.end()
+
+	return a.sendMessage(ctx, output)
+}
+
+// sendMessage sends the given message as SendMessageWithError does, but is not counted as a separate in-flight send.
+// This is used for the messages that accompany a send already counted.
+func (a *Adapter) sendMessage(ctx context.Context, output sarah.Output) error {
+	payload, sendOptions := unwrapRequestOptions(output.Content())
+	payload, completes := unwrapCompletion(payload)
+
+	if a.config.DryRun {
+		a.log().Infof("[dry run] Message to %v: %+v", output.Destination(), payload)
+		a.messageSent(output.Destination(), nil)
+		return nil
+	}
+
+	if interaction, ok := output.Destination().(*InteractionDestination); ok {
+		err := a.respondInteraction(ctx, interaction, payload, nil, sendOptions...)
+		a.messageSent(output.Destination(), err)
+		return err
+	}
+
+	if userID, ok := output.Destination().(UserID); ok {
+		channelID, err := a.dmChannelID(ctx, userID)
+		if err != nil {
+			a.messageSent(output.Destination(), err)
+			return err
+		}
+		return a.sendMessage(ctx, sarah.NewOutputMessage(channelID, output.Content()))
+	}
+
 	destination, ok := output.Destination().(ChannelID)
 	if !ok {
-		logger.Errorf("Destination is not instance of ChannelID. %#v.", output.Destination())
-		return
+		err := fmt.Errorf("destination is not instance of ChannelID: %#v", output.Destination())
+		a.messageSent(output.Destination(), err)
+		return err
 	}
 
 	channelID := string(destination)
 
-	switch content := output.Content().(type) {
+	// Bound slow API calls so a stuck request does not block the caller, e.g., on shutdown.
+	if a.config.SendTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.config.SendTimeout)
+		defer cancel()
+	}
+
+	// Skip the send when identical content was just sent to the same channel.
+	var hash contentHash
+	dedup := false
+	if a.config.SuppressDuplicateSends {
+		hash, dedup = hashContent(payload)
+		if dedup && !a.dedup.reserve(channelID, hash, a.config.DuplicateSendWindow, time.Now()) {
+			a.log().Debugf("Suppressed duplicate message to %s", channelID)
+			return nil
+		}
+	}
+
+	// Options given via RespWithRequestOptions are applied after the defaults.
+	var err error
+
+	switch content := payload.(type) {
 	case string:
-		_, err := a.session.ChannelMessageSend(channelID, content)
+		if a.config.ResolveEmojiInContent {
+			content, err = a.resolveContentEmojis(channelID, content)
+			if err != nil {
+				err = fmt.Errorf("failed to resolve emoji in message to %s: %w", channelID, err)
+				a.messageSent(output.Destination(), err)
+				break
+			}
+		}
+
+		err = a.sendText(ctx, channelID, content, sendOptions...)
 		if err != nil {
-			logger.Errorf("Failed to send message to %s: %+v", channelID, err)
+			err = fmt.Errorf("failed to send message to %s: %w", channelID, err)
 		}
+		a.messageSent(output.Destination(), err)
 
 	case *discordgo.MessageSend:
-		_, err := a.session.ChannelMessageSendComplex(channelID, content)
+		if a.config.ResolveEmojiInContent && content.Content != "" {
+			var resolved string
+			resolved, err = a.resolveContentEmojis(channelID, content.Content)
+			if err != nil {
+				err = fmt.Errorf("failed to resolve emoji in message to %s: %w", channelID, err)
+				a.messageSent(output.Destination(), err)
+				break
+			}
+
+			// Copy the given content so the caller's value is not modified.
+			copied := *content
+			copied.Content = resolved
+			content = &copied
+		}
+
+		// Content and embeds exceeding the limits of a single message spill over to the following messages.
+		var messages []*discordgo.MessageSend
+		for _, data := range splitContent(content, a.config.MaxMessageLength) {
+			messages = append(messages, splitEmbeds(data, a.config.MaxEmbedsPerMessage)...)
+		}
+		for _, data := range messages {
+			err = a.sendComplex(ctx, channelID, data, sendOptions...)
+			if err != nil {
+				err = fmt.Errorf("failed to send complex message to %s: %w", channelID, err)
+				break
+			}
+		}
+		a.messageSent(output.Destination(), err)
+
+	case *reactionResponse:
+		err = a.AddReactions(ctx, destination, content.MessageID, content.Emoji)
 		if err != nil {
-			logger.Errorf("Failed to send complex message to %s: %+v", channelID, err)
+			err = fmt.Errorf("failed to add reaction to %s: %w", content.MessageID, err)
+		}
+		a.messageSent(output.Destination(), err)
+
+		// The reaction may accompany a reply.
+		if err == nil && content.Content != nil && content.Content != "" {
+			var reply any = content.Content
+			if len(sendOptions) > 0 {
+				reply = &requestOptionsResponse{Content: reply, Options: sendOptions}
+			}
+			err = a.sendMessage(ctx, sarah.NewOutputMessage(destination, reply))
 		}
 
 	case *sarah.CommandHelps:
-		lines := make([]string, 0, len(*content))
+		if a.config.HelpAsSelectMenu && len(*content) > 0 {
+			err = a.sendHelpMenus(ctx, channelID, *content, sendOptions...)
+			if err != nil {
+				err = fmt.Errorf("failed to send help menu to %s: %w", channelID, err)
+			}
+			a.messageSent(output.Destination(), err)
+			break
+		}
+
+		if a.config.HelpAsEmbed && len(*content) > 0 {
+			err = a.sendHelpEmbeds(ctx, channelID, *content, sendOptions...)
+			if err != nil {
+				err = fmt.Errorf("failed to send help embeds to %s: %w", channelID, err)
+			}
+			a.messageSent(output.Destination(), err)
+			break
+		}
+
+		lines := make([]string, 0, len(*content)+1)
+		if a.config.HelpHeader != "" {
+			lines = append(lines, a.config.HelpHeader)
+		}
 		for _, h := range *content {
-			lines = append(lines, fmt.Sprintf("**%s**: %s", h.Identifier, h.Instruction))
+			lines = append(lines, helpLine(h))
 		}
-		text := strings.Join(lines, "\n")
-		_, err := a.session.ChannelMessageSend(channelID, text)
+		err = a.sendText(ctx, channelID, strings.Join(lines, "\n"), sendOptions...)
 		if err != nil {
-			logger.Errorf("Failed to send help message to %s: %+v", channelID, err)
+			err = fmt.Errorf("failed to send help message to %s: %w", channelID, err)
 		}
+		a.messageSent(output.Destination(), err)
 
 	default:
-		logger.Warnf("Unexpected output %#v", output)
+		err = fmt.Errorf("unexpected content type: %T", content)
+		a.messageSent(output.Destination(), err)
+	}
+
+	// Let the same content be sent again when this send did not reach Discord.
+	if dedup && err != nil {
+		a.dedup.release(channelID, hash)
+	}
+
+	if err == nil {
+		if completes {
+			a.threads.complete(channelID)
+		}
+		a.archiveCompletedThread(ctx, channelID)
+	}
+
+	return err
+}
+
+// SendTo sends the given content to the given channel, e.g., to report a moderation command to a modlog channel
+// while the response to the command itself goes to the channel where it was invoked.
+// The content is any of those that a response may carry, e.g., a string or *discordgo.MessageSend, and the error is returned as SendMessageWithError does.
+func (a *Adapter) SendTo(ctx context.Context, channelID ChannelID, content any) error {
+	return a.SendMessageWithError(ctx, sarah.NewOutputMessage(channelID, content))
+}
+
+// requestOptions returns Config.DefaultRequestOptions followed by the given options.
+func (a *Adapter) requestOptions(options ...discordgo.RequestOption) []discordgo.RequestOption {
+	return mergeRequestOptions(a.config.DefaultRequestOptions, options...)
+}
+
+// mergeRequestOptions returns the default options followed by the given options.
+// The given options are applied later, so they take precedence over the defaults.
+func mergeRequestOptions(defaults []discordgo.RequestOption, options ...discordgo.RequestOption) []discordgo.RequestOption {
+	if len(defaults) == 0 {
+		return options
 	}
+
+	merged := make([]discordgo.RequestOption, 0, len(defaults)+len(options))
+	merged = append(merged, defaults...)
+	return append(merged, options...)
 }
 
 // Input is a sarah.Input implementation that represents a received Discord message.
 type Input struct {
-	Event     *discordgo.MessageCreate
-	senderKey string
-	text      string
-	sentAt    time.Time
-	channelID ChannelID
+	Event       *discordgo.MessageCreate
+	senderKey   string
+	text        string
+	sentAt      time.Time
+	channelID   ChannelID
+	guildID     string
+	messageID   string
+	invoked     bool
+	mentions    []*discordgo.User
+	attachments []*discordgo.MessageAttachment
+	displayName string
+	referenced  *discordgo.Message
+	flags       discordgo.MessageFlags
+	stickers    []*discordgo.StickerItem
+	botID       string
+	mentionsMe  bool
+
+	correlationID string
+
+	// runCtx is the context given to Run, which is kept to tell handlers the lifetime of the gateway connection.
+	runCtx context.Context
+
+	// adapter is used to look up the channel. This is nil when the Input is not created by the Adapter.
+	adapter          *Adapter
+	channelKindMutex sync.Mutex
+	channelKind      ChannelKind
 }
 
 var _ sarah.Input = (*Input)(nil)
 
 // SenderKey returns a unique key representing the sender in the channel.
+// The key does not include the guild ID since Discord's channel IDs are snowflakes that are globally unique,
+// so the key stays stable and never collides between guilds.
 func (i *Input) SenderKey() string {
 	return i.senderKey
 }
@@ -201,6 +793,92 @@ func (i *Input) ReplyTo() sarah.OutputDestination {
 	return i.channelID
 }
 
+// Invoked tells if the message explicitly invokes the bot with Config.CommandPrefix or the bot's mention, or is a direct message.
+// This is always false for an Input that is not created by the Adapter.
+func (i *Input) Invoked() bool {
+	return i.invoked
+}
+
+// MessageID returns the ID of the received message, which is used to edit, delete, or react to the message later.
+func (i *Input) MessageID() string {
+	return i.messageID
+}
+
+// Mentions returns the users mentioned in the message.
+// This is empty when no user is mentioned.
+func (i *Input) Mentions() []*discordgo.User {
+	return i.mentions
+}
+
+// MentionedUserIDs returns the IDs of the users mentioned in the message, so commands like ".kick @user" do not have to parse the mention syntax.
+func (i *Input) MentionedUserIDs() []string {
+	ids := make([]string, 0, len(i.mentions))
+	for _, user := range i.mentions {
+		if user != nil {
+			ids = append(ids, user.ID)
+		}
+	}
+	return ids
+}
+
+// MentionsMe tells if the bot is mentioned anywhere in the message.
+// This is always false when the Input is not created by the Adapter, since the bot's user ID is not known.
+func (i *Input) MentionsMe() bool {
+	return i.mentionsMe
+}
+
+// Attachments returns the files attached to the message.
+// Use their URL or ProxyURL to download the content. This is empty when nothing is attached.
+func (i *Input) Attachments() []*discordgo.MessageAttachment {
+	return i.attachments
+}
+
+// AuthorDisplayName returns the name the author is displayed with, e.g., to greet the author.
+// This is the author's nickname in the guild, or the global display name or the username when the nickname is not set or the message is a direct message.
+func (i *Input) AuthorDisplayName() string {
+	return i.displayName
+}
+
+// ReferencedMessage returns the message that the received message replies to, e.g., for ".translate" on a reply.
+// This returns nil when the message is not a reply, or when Discord did not resolve the referenced message, e.g., because it was deleted.
+func (i *Input) ReferencedMessage() *discordgo.Message {
+	return i.referenced
+}
+
+// Flags returns the flags of the message, e.g., to tell if its embeds are suppressed with discordgo.MessageFlagsSuppressEmbeds.
+func (i *Input) Flags() discordgo.MessageFlags {
+	return i.flags
+}
+
+// StickerItems returns the stickers sent with the message. This is empty when no sticker is sent.
+func (i *Input) StickerItems() []*discordgo.StickerItem {
+	return i.stickers
+}
+
+// Context returns the context given to Adapter.Run, e.g., to read the values set on it such as a trace ID.
+// A command receives its own context from go-sarah, while this one is canceled when the Adapter stops,
+// so a long-running task started by the command can observe the shutdown of the gateway connection.
+// This returns context.Background() for an Input that is not received by the Adapter.
+func (i *Input) Context() context.Context {
+	if i.runCtx == nil {
+		return context.Background()
+	}
+	return i.runCtx
+}
+
+// IsDirectMessage tells if the message was sent in a direct message, i.e., without a guild.
+// The bot receives direct messages only when Config.Intents includes discordgo.IntentsDirectMessages.
+// This is false for an Input without the original event since where it was sent cannot be determined.
+func (i *Input) IsDirectMessage() bool {
+	return i.Event != nil && i.guildID == ""
+}
+
+// GuildID returns the ID of the guild where the message was sent.
+// This is empty for a direct message.
+func (i *Input) GuildID() string {
+	return i.guildID
+}
+
 // MessageToInput converts a *discordgo.MessageCreate event to *Input.
 func MessageToInput(m *discordgo.MessageCreate) (*Input, error) {
 	if m.Author == nil {
@@ -208,14 +886,36 @@ func MessageToInput(m *discordgo.MessageCreate) (*Input, error) {
 	}
 
 	return &Input{
-		Event:     m,
-		senderKey: fmt.Sprintf("%s_%s", m.ChannelID, m.Author.ID),
-		text:      m.Content,
-		sentAt:    m.Timestamp,
-		channelID: ChannelID(m.ChannelID),
+		Event:       m,
+		senderKey:   senderKeyOf(m.ChannelID, m.Author.ID),
+		text:        m.Content,
+		sentAt:      m.Timestamp,
+		channelID:   ChannelID(m.ChannelID),
+		guildID:     m.GuildID,
+		messageID:   m.ID,
+		mentions:    append([]*discordgo.User{}, m.Mentions...),
+		attachments: append([]*discordgo.MessageAttachment{}, m.Attachments...),
+		displayName: displayName(m.Member, m.Author),
+		referenced:  m.ReferencedMessage,
+		flags:       m.Flags,
+		stickers:    append([]*discordgo.StickerItem{}, m.StickerItems...),
+
+		correlationID: newCorrelationID(),
 	}, nil
 }
 
+// displayName returns the member's nickname, falling back to the user's global display name and then the username.
+// The member is nil for direct messages.
+func displayName(member *discordgo.Member, user *discordgo.User) string {
+	if member != nil && member.Nick != "" {
+		return member.Nick
+	}
+	if user.GlobalName != "" {
+		return user.GlobalName
+	}
+	return user.Username
+}
+
 // ResponseContent constrains the content types accepted by NewResponse.
 // Valid types are string for plain text and *discordgo.MessageSend for rich content
 // such as embeds, components, and file attachments.
@@ -227,27 +927,101 @@ type ResponseContent interface {
 // The content parameter may be a string for plain text messages or a
 // *discordgo.MessageSend for rich content such as embeds and components.
 // Pass RespOption values to customize the response.
+// The input may also be an *InteractionInput, in which case the options that only apply to messages, i.e., RespWithReaction and RespWithReply, are ignored.
 func NewResponse[T ResponseContent](input sarah.Input, content T, options ...RespOption) (*sarah.CommandResponse, error) {
-	if _, ok := input.(*Input); !ok {
-		return nil, fmt.Errorf("%T is not a *discord.Input", input)
-	}
-
 	stash := &respOptions{}
 	for _, opt := range options {
 		opt(stash)
 	}
 
-	return &sarah.CommandResponse{
-		Content:     content,
-		UserContext: stash.userContext,
-	}, nil
+	if interaction, ok := input.(*InteractionInput); ok {
+		return interactionResponseOf(interaction, content, stash)
+	}
+
+	typed, ok := input.(*Input)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a *discord.Input", input)
+	}
+
+	if stash.ephemeral {
+		return nil, ErrEphemeralNotSupported
+	}
+
+	// When the command is used in the wrong context, reply with the rejection message instead.
+	var response *sarah.CommandResponse
+	rejected := stash.context != nil && !stash.context.matches(typed)
+	if rejected {
+		response = &sarah.CommandResponse{
+			Content: stash.context.rejection,
+		}
+	} else {
+		response = &sarah.CommandResponse{
+			Content:     content,
+			UserContext: stash.userContext,
+		}
+	}
+
+	if !rejected && len(stash.embeds) > 0 {
+		embedded, err := withEmbeds(response.Content, stash.embeds)
+		if err != nil {
+			return nil, err
+		}
+		response.Content = embedded
+	}
+
+	if !rejected && len(stash.components) > 0 {
+		response.Content = withComponents(response.Content, stash.components)
+	}
+
+	if !rejected && stash.tts {
+		response.Content = withTTS(response.Content)
+	}
+
+	if stash.reply && typed.messageID != "" {
+		response.Content = replyTo(typed, response.Content)
+	}
+
+	if stash.allowedMentions != nil {
+		response.Content = withAllowedMentions(response.Content, stash.allowedMentions)
+	}
+
+	if !rejected && stash.reaction != "" && typed.messageID != "" {
+		response.Content = &reactionResponse{
+			MessageID: typed.messageID,
+			Emoji:     stash.reaction,
+			Content:   response.Content,
+		}
+	}
+
+	// A response without the next step completes the conversation once it is sent.
+	if response.UserContext == nil && typed.adapter != nil && typed.adapter.inConversationThread(typed) {
+		response.Content = &completingResponse{Content: response.Content}
+	}
+
+	if len(stash.requestOptions) > 0 {
+		response.Content = &requestOptionsResponse{
+			Content: response.Content,
+			Options: stash.requestOptions,
+		}
+	}
+
+	return response, nil
 }
 
 // RespOption defines a function signature that NewResponse's functional options must satisfy.
 type RespOption func(*respOptions)
 
 type respOptions struct {
-	userContext *sarah.UserContext
+	userContext     *sarah.UserContext
+	context         *contextRequirement
+	reaction        string
+	reply           bool
+	allowedMentions *discordgo.MessageAllowedMentions
+	components      []discordgo.MessageComponent
+	embeds          []*discordgo.MessageEmbed
+	tts             bool
+	ephemeral       bool
+	requestOptions  []discordgo.RequestOption
 }
 
 // RespWithNext sets a given function as part of the response's *sarah.UserContext.
@@ -268,3 +1042,55 @@ func RespWithNextSerializable(arg *sarah.SerializableArgument) RespOption {
 		}
 	}
 }
+
+const (
+	// defaultGuildOnlyMessage is sent when RespGuildOnly is given without a message.
+	defaultGuildOnlyMessage = "This command can only be used in a server."
+
+	// defaultDMOnlyMessage is sent when RespDMOnly is given without a message.
+	defaultDMOnlyMessage = "This command can only be used in direct messages."
+)
+
+// contextRequirement describes where a response is allowed to be sent.
+type contextRequirement struct {
+	guild     bool
+	rejection string
+}
+
+// matches tells if the input was sent in the required context.
+// An input without the original event is always regarded as matching since its context cannot be determined.
+func (r *contextRequirement) matches(input *Input) bool {
+	if input.Event == nil {
+		return true
+	}
+	return r.matchesGuild(input.Event.GuildID)
+}
+
+// matchesGuild tells if the event with the given guild ID, which is empty in a direct message, was sent in the required context.
+func (r *contextRequirement) matchesGuild(guildID string) bool {
+	return (guildID != "") == r.guild
+}
+
+// RespGuildOnly restricts the response to inputs sent in a guild.
+// When the input is sent in a direct message, the given message is sent instead of the response content.
+// A generic message is used when msg is empty.
+func RespGuildOnly(msg string) RespOption {
+	if msg == "" {
+		msg = defaultGuildOnlyMessage
+	}
+	return func(options *respOptions) {
+		options.context = &contextRequirement{guild: true, rejection: msg}
+	}
+}
+
+// RespDMOnly restricts the response to inputs sent in a direct message.
+// When the input is sent in a guild, the given message is sent instead of the response content.
+// A generic message is used when msg is empty.
+func RespDMOnly(msg string) RespOption {
+	if msg == "" {
+		msg = defaultDMOnlyMessage
+	}
+	return func(options *respOptions) {
+		options.context = &contextRequirement{guild: false, rejection: msg}
+	}
+}
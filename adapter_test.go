@@ -4,7 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,14 +19,53 @@ import (
 
 // mockSession implements the session interface for testing.
 type mockSession struct {
-	addHandlerFunc                func(handler interface{}) func()
-	openFunc                      func() error
-	closeFunc                     func() error
-	channelMessageSendFunc        func(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
-	channelMessageSendComplexFunc func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	addHandlerFunc                      func(handler interface{}) func()
+	openFunc                            func() error
+	closeFunc                           func() error
+	closeWithCodeFunc                   func(closeCode int) error
+	channelMessageSendFunc              func(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	channelMessageSendComplexFunc       func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	channelMessageEditFunc              func(channelID string, messageID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	channelFunc                         func(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	guildFunc                           func(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error)
+	guildMemberFunc                     func(guildID string, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error)
+	messageReactionAddFunc              func(channelID string, messageID string, emojiID string, options ...discordgo.RequestOption) error
+	channelMessageFunc                  func(channelID string, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	messageThreadStartComplexFunc       func(channelID string, messageID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	guildMemberDeleteWithReasonFunc     func(guildID string, userID string, reason string, options ...discordgo.RequestOption) error
+	guildBanCreateWithReasonFunc        func(guildID string, userID string, reason string, days int, options ...discordgo.RequestOption) error
+	guildMemberRoleAddFunc              func(guildID string, userID string, roleID string, options ...discordgo.RequestOption) error
+	guildMemberRoleRemoveFunc           func(guildID string, userID string, roleID string, options ...discordgo.RequestOption) error
+	channelMessagesFunc                 func(channelID string, limit int, beforeID string, afterID string, aroundID string, options ...discordgo.RequestOption) ([]*discordgo.Message, error)
+	channelMessagePinFunc               func(channelID string, messageID string, options ...discordgo.RequestOption) error
+	channelMessageUnpinFunc             func(channelID string, messageID string, options ...discordgo.RequestOption) error
+	channelMessagesBulkDeleteFunc       func(channelID string, messages []string, options ...discordgo.RequestOption) error
+	threadStartComplexFunc              func(channelID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	channelEditComplexFunc              func(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	guildInvitesFunc                    func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Invite, error)
+	interactionRespondFunc              func(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error
+	guildEmojisFunc                     func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Emoji, error)
+	applicationEmojisFunc               func(appID string, options ...discordgo.RequestOption) ([]*discordgo.Emoji, error)
+	channelTypingFunc                   func(channelID string, options ...discordgo.RequestOption) error
+	channelMessageDeleteFunc            func(channelID string, messageID string, options ...discordgo.RequestOption) error
+	interactionResponseEditFunc         func(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	updateStatusComplexFunc             func(usd discordgo.UpdateStatusData) error
+	userFunc                            func(userID string, options ...discordgo.RequestOption) (*discordgo.User, error)
+	userChannelCreateFunc               func(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	applicationCommandCreateFunc        func(appID string, guildID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error)
+	applicationCommandDeleteFunc        func(appID string, guildID string, cmdID string, options ...discordgo.RequestOption) error
+	applicationCommandBulkOverwriteFunc func(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error)
+
+	mutex              sync.Mutex
+	disconnectHandlers []func(*discordgo.Session, *discordgo.Disconnect)
 }
 
 func (m *mockSession) AddHandler(handler interface{}) func() {
+	if h, ok := handler.(func(*discordgo.Session, *discordgo.Disconnect)); ok {
+		m.mutex.Lock()
+		m.disconnectHandlers = append(m.disconnectHandlers, h)
+		m.mutex.Unlock()
+	}
 	if m.addHandlerFunc != nil {
 		return m.addHandlerFunc(handler)
 	}
@@ -35,13 +79,33 @@ func (m *mockSession) Open() error {
 	return nil
 }
 
+// Close emits a Disconnect event as discordgo does on every close.
 func (m *mockSession) Close() error {
+	defer m.emitDisconnect()
 	if m.closeFunc != nil {
 		return m.closeFunc()
 	}
 	return nil
 }
 
+// CloseWithCode falls back to Close when closeWithCodeFunc is not set.
+func (m *mockSession) CloseWithCode(closeCode int) error {
+	if m.closeWithCodeFunc == nil {
+		return m.Close()
+	}
+	defer m.emitDisconnect()
+	return m.closeWithCodeFunc(closeCode)
+}
+
+func (m *mockSession) emitDisconnect() {
+	m.mutex.Lock()
+	handlers := slices.Clone(m.disconnectHandlers)
+	m.mutex.Unlock()
+	for _, h := range handlers {
+		h(nil, &discordgo.Disconnect{})
+	}
+}
+
 func (m *mockSession) ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
 	if m.channelMessageSendFunc != nil {
 		return m.channelMessageSendFunc(channelID, content, options...)
@@ -56,6 +120,217 @@ func (m *mockSession) ChannelMessageSendComplex(channelID string, data *discordg
 	return &discordgo.Message{}, nil
 }
 
+func (m *mockSession) ChannelMessageEdit(channelID string, messageID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if m.channelMessageEditFunc != nil {
+		return m.channelMessageEditFunc(channelID, messageID, content, options...)
+	}
+	return &discordgo.Message{}, nil
+}
+
+func (m *mockSession) Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if m.channelFunc != nil {
+		return m.channelFunc(channelID, options...)
+	}
+	return &discordgo.Channel{ID: channelID}, nil
+}
+
+func (m *mockSession) Guild(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+	if m.guildFunc != nil {
+		return m.guildFunc(guildID, options...)
+	}
+	return &discordgo.Guild{ID: guildID}, nil
+}
+
+func (m *mockSession) GuildMember(guildID string, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error) {
+	if m.guildMemberFunc != nil {
+		return m.guildMemberFunc(guildID, userID, options...)
+	}
+	return &discordgo.Member{GuildID: guildID, User: &discordgo.User{ID: userID}}, nil
+}
+
+func (m *mockSession) MessageReactionAdd(channelID string, messageID string, emojiID string, options ...discordgo.RequestOption) error {
+	if m.messageReactionAddFunc != nil {
+		return m.messageReactionAddFunc(channelID, messageID, emojiID, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) ChannelMessage(channelID string, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if m.channelMessageFunc != nil {
+		return m.channelMessageFunc(channelID, messageID, options...)
+	}
+	return &discordgo.Message{ID: messageID, ChannelID: channelID}, nil
+}
+
+func (m *mockSession) GuildMemberDeleteWithReason(guildID string, userID string, reason string, options ...discordgo.RequestOption) error {
+	if m.guildMemberDeleteWithReasonFunc != nil {
+		return m.guildMemberDeleteWithReasonFunc(guildID, userID, reason, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) GuildBanCreateWithReason(guildID string, userID string, reason string, days int, options ...discordgo.RequestOption) error {
+	if m.guildBanCreateWithReasonFunc != nil {
+		return m.guildBanCreateWithReasonFunc(guildID, userID, reason, days, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) GuildMemberRoleAdd(guildID string, userID string, roleID string, options ...discordgo.RequestOption) error {
+	if m.guildMemberRoleAddFunc != nil {
+		return m.guildMemberRoleAddFunc(guildID, userID, roleID, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) GuildMemberRoleRemove(guildID string, userID string, roleID string, options ...discordgo.RequestOption) error {
+	if m.guildMemberRoleRemoveFunc != nil {
+		return m.guildMemberRoleRemoveFunc(guildID, userID, roleID, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) ChannelMessages(channelID string, limit int, beforeID string, afterID string, aroundID string, options ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+	if m.channelMessagesFunc != nil {
+		return m.channelMessagesFunc(channelID, limit, beforeID, afterID, aroundID, options...)
+	}
+	return []*discordgo.Message{}, nil
+}
+
+func (m *mockSession) ChannelMessagePin(channelID string, messageID string, options ...discordgo.RequestOption) error {
+	if m.channelMessagePinFunc != nil {
+		return m.channelMessagePinFunc(channelID, messageID, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) ChannelMessageUnpin(channelID string, messageID string, options ...discordgo.RequestOption) error {
+	if m.channelMessageUnpinFunc != nil {
+		return m.channelMessageUnpinFunc(channelID, messageID, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) ChannelMessagesBulkDelete(channelID string, messages []string, options ...discordgo.RequestOption) error {
+	if m.channelMessagesBulkDeleteFunc != nil {
+		return m.channelMessagesBulkDeleteFunc(channelID, messages, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) MessageThreadStartComplex(channelID string, messageID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if m.messageThreadStartComplexFunc != nil {
+		return m.messageThreadStartComplexFunc(channelID, messageID, data, options...)
+	}
+	// A thread started from a message shares the message's ID.
+	return &discordgo.Channel{ID: messageID, ParentID: channelID, Name: data.Name, Type: discordgo.ChannelTypeGuildPublicThread}, nil
+}
+
+func (m *mockSession) ThreadStartComplex(channelID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if m.threadStartComplexFunc != nil {
+		return m.threadStartComplexFunc(channelID, data, options...)
+	}
+	return &discordgo.Channel{ID: "thread-1", ParentID: channelID, Name: data.Name, Type: data.Type}, nil
+}
+
+func (m *mockSession) ChannelEditComplex(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if m.channelEditComplexFunc != nil {
+		return m.channelEditComplexFunc(channelID, data, options...)
+	}
+	return &discordgo.Channel{ID: channelID}, nil
+}
+
+func (m *mockSession) GuildInvites(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Invite, error) {
+	if m.guildInvitesFunc != nil {
+		return m.guildInvitesFunc(guildID, options...)
+	}
+	return []*discordgo.Invite{}, nil
+}
+
+func (m *mockSession) InteractionRespond(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+	if m.interactionRespondFunc != nil {
+		return m.interactionRespondFunc(interaction, resp, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) GuildEmojis(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Emoji, error) {
+	if m.guildEmojisFunc != nil {
+		return m.guildEmojisFunc(guildID, options...)
+	}
+	return nil, nil
+}
+
+func (m *mockSession) ChannelTyping(channelID string, options ...discordgo.RequestOption) error {
+	if m.channelTypingFunc != nil {
+		return m.channelTypingFunc(channelID, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) ChannelMessageDelete(channelID string, messageID string, options ...discordgo.RequestOption) error {
+	if m.channelMessageDeleteFunc != nil {
+		return m.channelMessageDeleteFunc(channelID, messageID, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) InteractionResponseEdit(interaction *discordgo.Interaction, newresp *discordgo.WebhookEdit, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	if m.interactionResponseEditFunc != nil {
+		return m.interactionResponseEditFunc(interaction, newresp, options...)
+	}
+	return &discordgo.Message{}, nil
+}
+
+func (m *mockSession) UpdateStatusComplex(usd discordgo.UpdateStatusData) error {
+	if m.updateStatusComplexFunc != nil {
+		return m.updateStatusComplexFunc(usd)
+	}
+	return nil
+}
+
+func (m *mockSession) User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error) {
+	if m.userFunc != nil {
+		return m.userFunc(userID, options...)
+	}
+	return &discordgo.User{}, nil
+}
+
+func (m *mockSession) UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	if m.userChannelCreateFunc != nil {
+		return m.userChannelCreateFunc(recipientID, options...)
+	}
+	return &discordgo.Channel{ID: "dm-" + recipientID, Type: discordgo.ChannelTypeDM}, nil
+}
+
+func (m *mockSession) ApplicationCommandBulkOverwrite(appID string, guildID string, commands []*discordgo.ApplicationCommand, options ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+	if m.applicationCommandBulkOverwriteFunc != nil {
+		return m.applicationCommandBulkOverwriteFunc(appID, guildID, commands, options...)
+	}
+	return commands, nil
+}
+
+func (m *mockSession) ApplicationCommandCreate(appID string, guildID string, cmd *discordgo.ApplicationCommand, options ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+	if m.applicationCommandCreateFunc != nil {
+		return m.applicationCommandCreateFunc(appID, guildID, cmd, options...)
+	}
+	return cmd, nil
+}
+
+func (m *mockSession) ApplicationCommandDelete(appID string, guildID string, cmdID string, options ...discordgo.RequestOption) error {
+	if m.applicationCommandDeleteFunc != nil {
+		return m.applicationCommandDeleteFunc(appID, guildID, cmdID, options...)
+	}
+	return nil
+}
+
+func (m *mockSession) ApplicationEmojis(appID string, options ...discordgo.RequestOption) ([]*discordgo.Emoji, error) {
+	if m.applicationEmojisFunc != nil {
+		return m.applicationEmojisFunc(appID, options...)
+	}
+	return nil, nil
+}
+
 func TestBotTypeValue(t *testing.T) {
 	if DISCORD != ("discord") {
 		t.Errorf("Expected DISCORD to be %q, got %q", "discord", DISCORD)
@@ -111,6 +386,184 @@ func TestNewAdapter(t *testing.T) {
 			t.Error("Expected injected session to be used")
 		}
 	})
+
+	t.Run("with shards", func(t *testing.T) {
+		config := NewConfig()
+		config.Token = "test-token"
+		config.ShardID = 1
+		config.ShardCount = 3
+
+		adapter, err := NewAdapter(config)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		s := adapter.Session()
+		if s.ShardID != 1 || s.ShardCount != 3 {
+			t.Errorf("Expected shard 1 of 3, got %d of %d", s.ShardID, s.ShardCount)
+		}
+	})
+
+	t.Run("without shards", func(t *testing.T) {
+		config := NewConfig()
+		config.Token = "test-token"
+
+		adapter, err := NewAdapter(config)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		// discordgo.New connects as the single shard by default.
+		s := adapter.Session()
+		if s.ShardID != 0 || s.ShardCount != 1 {
+			t.Errorf("Expected a single shard, got %d of %d", s.ShardID, s.ShardCount)
+		}
+	})
+
+	invalid := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{name: "same help and abort commands", modify: func(c *Config) { c.AbortCommand = c.HelpCommand }},
+		{name: "prefix without message content", modify: func(c *Config) {
+			c.CommandPrefix = "!"
+			c.Intents = discordgo.IntentsGuildMessages
+		}},
+		{name: "no message intents", modify: func(c *Config) { c.Intents = discordgo.IntentsGuilds }},
+	}
+	for _, tt := range invalid {
+		t.Run("invalid config: "+tt.name, func(t *testing.T) {
+			for _, withSession := range []bool{false, true} {
+				config := NewConfig()
+				var options []AdapterOption
+				if withSession {
+					options = append(options, WithSession(&discordgo.Session{}))
+				} else {
+					config.Token = "test-token"
+				}
+				tt.modify(config)
+
+				_, err := NewAdapter(config, options...)
+				if !errors.Is(err, ErrInvalidConfig) {
+					t.Errorf("Expected ErrInvalidConfig with session %t, got %+v", withSession, err)
+				}
+				if errors.Is(err, ErrEmptyToken) {
+					t.Error("Token should not be required")
+				}
+			}
+		})
+	}
+}
+
+func TestWithIntents(t *testing.T) {
+	t.Run("intents of the created session", func(t *testing.T) {
+		config := NewConfig()
+		config.Token = "test-token"
+		intents := discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent | discordgo.IntentsGuildMembers
+
+		adapter, err := NewAdapter(config, WithIntents(intents))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if got := adapter.Session().Identify.Intents; got != intents {
+			t.Errorf("Expected intents %d, got %d", intents, got)
+		}
+		if config.Intents != NewConfig().Intents {
+			t.Error("Config should not be modified")
+		}
+	})
+
+	t.Run("overriding intents are validated", func(t *testing.T) {
+		config := NewConfig()
+		config.Token = "test-token"
+
+		_, err := NewAdapter(config, WithIntents(discordgo.IntentsGuilds))
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Expected ErrInvalidConfig, got %+v", err)
+		}
+	})
+}
+
+func TestWithStateEnabled(t *testing.T) {
+	t.Run("state of the created session", func(t *testing.T) {
+		for _, enabled := range []bool{false, true} {
+			config := NewConfig()
+			config.Token = "test-token"
+
+			adapter, err := NewAdapter(config, WithStateEnabled(enabled))
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			if adapter.Session().StateEnabled != enabled {
+				t.Errorf("Expected StateEnabled to be %t", enabled)
+			}
+		}
+	})
+
+	t.Run("state of the given session", func(t *testing.T) {
+		session, _ := discordgo.New("Bot test-token")
+
+		adapter, err := NewAdapter(NewConfig(), WithStateEnabled(false), WithSession(session))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if adapter.Session().StateEnabled {
+			t.Error("Expected the state to be disabled regardless of the option order")
+		}
+	})
+
+	t.Run("messages from the bot itself are ignored without the state", func(t *testing.T) {
+		config := NewConfig()
+		config.Token = "test-token"
+		adapter, err := NewAdapter(config, WithStateEnabled(false))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		s := adapter.Session()
+		if err := s.State.OnInterface(s, &discordgo.Ready{User: &discordgo.User{ID: "bot-1"}}); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		var enqueued bool
+		m := &discordgo.MessageCreate{
+			Message: &discordgo.Message{ChannelID: "ch-1", Content: "hello", Author: &discordgo.User{ID: "bot-1"}},
+		}
+		adapter.handleMessage(context.Background(), s, m, func(sarah.Input) error {
+			enqueued = true
+			return nil
+		})
+
+		if enqueued {
+			t.Error("Expected the bot's own message to be ignored")
+		}
+	})
+}
+
+func TestAdapter_Session(t *testing.T) {
+	t.Run("injected session", func(t *testing.T) {
+		session := &discordgo.Session{}
+
+		adapter, err := NewAdapter(NewConfig(), WithSession(session))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if adapter.Session() != session {
+			t.Error("Expected the injected session to be returned")
+		}
+	})
+
+	t.Run("other implementation", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		if adapter.Session() != nil {
+			t.Error("Expected nil for a non-discordgo session")
+		}
+	})
 }
 
 func TestAdapter_BotType(t *testing.T) {
@@ -257,6 +710,7 @@ func TestAdapter_handleMessage(t *testing.T) {
 
 		m := &discordgo.MessageCreate{
 			Message: &discordgo.Message{
+				ID:        "msg-1",
 				ChannelID: "ch-1",
 				Content:   "hello",
 				Timestamp: time.Now(),
@@ -264,14 +718,19 @@ func TestAdapter_handleMessage(t *testing.T) {
 			},
 		}
 
-		adapter.handleMessage(sessionWithState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionWithState, m, enqueue)
 
 		if received == nil {
 			t.Fatal("Expected input to be enqueued")
 		}
 
-		if _, ok := received.(*Input); !ok {
-			t.Errorf("Expected *Input, got %T", received)
+		typed, ok := received.(*Input)
+		if !ok {
+			t.Fatalf("Expected *Input, got %T", received)
+		}
+
+		if typed.MessageID() != "msg-1" {
+			t.Errorf("Expected MessageID %q, got %q", "msg-1", typed.MessageID())
 		}
 
 		if received.Message() != "hello" {
@@ -298,7 +757,7 @@ func TestAdapter_handleMessage(t *testing.T) {
 			},
 		}
 
-		adapter.handleMessage(sessionWithState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionWithState, m, enqueue)
 
 		if received == nil {
 			t.Fatal("Expected input to be enqueued")
@@ -328,7 +787,7 @@ func TestAdapter_handleMessage(t *testing.T) {
 			},
 		}
 
-		adapter.handleMessage(sessionWithState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionWithState, m, enqueue)
 
 		if received == nil {
 			t.Fatal("Expected input to be enqueued")
@@ -358,7 +817,7 @@ func TestAdapter_handleMessage(t *testing.T) {
 			},
 		}
 
-		adapter.handleMessage(sessionWithState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionWithState, m, enqueue)
 
 		if received != nil {
 			t.Error("Bot's own message should be ignored")
@@ -384,7 +843,7 @@ func TestAdapter_handleMessage(t *testing.T) {
 			},
 		}
 
-		adapter.handleMessage(sessionWithState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionWithState, m, enqueue)
 
 		if received == nil {
 			t.Fatal("Expected input to be enqueued")
@@ -415,7 +874,7 @@ func TestAdapter_handleMessage(t *testing.T) {
 			},
 		}
 
-		adapter.handleMessage(sessionWithState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionWithState, m, enqueue)
 
 		if received == nil {
 			t.Fatal("Expected input to be enqueued")
@@ -447,7 +906,7 @@ func TestAdapter_handleMessage(t *testing.T) {
 			},
 		}
 
-		adapter.handleMessage(sessionNoState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionNoState, m, enqueue)
 
 		if received == nil {
 			t.Fatal("Expected input to be enqueued")
@@ -473,7 +932,7 @@ func TestAdapter_handleMessage(t *testing.T) {
 			},
 		}
 
-		adapter.handleMessage(sessionWithState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionWithState, m, enqueue)
 
 		if received != nil {
 			t.Error("Message with nil Author should be ignored")
@@ -498,57 +957,305 @@ func TestAdapter_handleMessage(t *testing.T) {
 		}
 
 		// Should not panic when enqueue returns an error
-		adapter.handleMessage(sessionWithState, m, enqueue)
+		adapter.handleMessage(context.Background(), sessionWithState, m, enqueue)
 	})
-}
 
-func TestAdapter_SendMessage(t *testing.T) {
-	t.Run("string content", func(t *testing.T) {
-		var gotChannelID, gotContent string
-		mock := &mockSession{
-			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
-				gotChannelID = channelID
-				gotContent = content
-				return &discordgo.Message{}, nil
-			},
-		}
-		adapter := &Adapter{config: NewConfig(), session: mock}
+	t.Run("message after the context is canceled", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: sessionWithState}
 
-		output := sarah.NewOutputMessage(ChannelID("ch-1"), "hello world")
-		adapter.SendMessage(context.Background(), output)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
 
-		if gotChannelID != "ch-1" {
-			t.Errorf("Expected channelID %q, got %q", "ch-1", gotChannelID)
-		}
-		if gotContent != "hello world" {
-			t.Errorf("Expected content %q, got %q", "hello world", gotContent)
+		enqueued := false
+		enqueue := func(input sarah.Input) error {
+			enqueued = true
+			return nil
 		}
-	})
 
-	t.Run("string content with send error", func(t *testing.T) {
-		mock := &mockSession{
-			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
-				return nil, fmt.Errorf("send failed")
+		m := &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "ch-1",
+				Content:   "hello",
+				Timestamp: time.Now(),
+				Author:    &discordgo.User{ID: "user-1"},
 			},
 		}
-		adapter := &Adapter{config: NewConfig(), session: mock}
+		adapter.handleMessage(ctx, sessionWithState, m, enqueue)
 
-		output := sarah.NewOutputMessage(ChannelID("ch-1"), "hello")
-		// Should not panic, just log the error
-		adapter.SendMessage(context.Background(), output)
+		if enqueued {
+			t.Error("Expected the message not to be enqueued after the context is canceled")
+		}
+		if adapter.Stats().Total.Dropped != 1 {
+			t.Errorf("Expected the message to be counted as dropped: %#v", adapter.Stats().Total)
+		}
 	})
+}
 
-	t.Run("MessageSend content", func(t *testing.T) {
-		var gotChannelID string
-		var gotData *discordgo.MessageSend
-		mock := &mockSession{
-			channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
-				gotChannelID = channelID
-				gotData = data
-				return &discordgo.Message{}, nil
-			},
-		}
-		adapter := &Adapter{config: NewConfig(), session: mock}
+func TestAdapter_handleMessage_CaseInsensitiveCommands(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name            string
+		caseInsensitive bool
+		content         string
+		help            bool
+		abort           bool
+	}{
+		{name: "mixed case help with the flag", caseInsensitive: true, content: ".HELP", help: true},
+		{name: "title case help with the flag", caseInsensitive: true, content: " .Help ", help: true},
+		{name: "mixed case abort with the flag", caseInsensitive: true, content: ".Abort", abort: true},
+		{name: "mixed case help without the flag", caseInsensitive: false, content: ".HELP"},
+		{name: "exact help without the flag", caseInsensitive: false, content: ".help", help: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.CaseInsensitiveCommands = tt.caseInsensitive
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			var received sarah.Input
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "ch-1",
+					Content:   tt.content,
+					Author:    &discordgo.User{ID: "user-1"},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(input sarah.Input) error {
+				received = input
+				return nil
+			})
+
+			_, help := received.(*sarah.HelpInput)
+			_, abort := received.(*sarah.AbortInput)
+			if help != tt.help || abort != tt.abort {
+				t.Errorf("Unexpected input: %T", received)
+			}
+		})
+	}
+}
+
+func TestAdapter_handleMessage_IgnoreBots(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-self"}
+
+	tests := []struct {
+		name       string
+		ignoreBots bool
+		authorID   string
+		bot        bool
+		enqueued   bool
+	}{
+		{name: "other bot is dropped", ignoreBots: true, authorID: "bot-other", bot: true, enqueued: false},
+		{name: "other bot is passed through", ignoreBots: false, authorID: "bot-other", bot: true, enqueued: true},
+		{name: "self is always dropped", ignoreBots: false, authorID: "bot-self", bot: true, enqueued: false},
+		{name: "human is passed through", ignoreBots: true, authorID: "user-1", bot: false, enqueued: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.IgnoreBots = tt.ignoreBots
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			enqueued := false
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "ch-1",
+					Content:   "hello",
+					Author:    &discordgo.User{ID: tt.authorID, Bot: tt.bot},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(sarah.Input) error {
+				enqueued = true
+				return nil
+			})
+
+			if enqueued != tt.enqueued {
+				t.Errorf("Expected enqueued to be %t", tt.enqueued)
+			}
+		})
+	}
+}
+
+func TestAdapter_SendMessageWithError(t *testing.T) {
+	apiErr := errors.New("api error")
+	failing := func() *mockSession {
+		return &mockSession{
+			channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return nil, apiErr
+			},
+			channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return nil, apiErr
+			},
+			messageReactionAddFunc: func(_ string, _ string, _ string, _ ...discordgo.RequestOption) error {
+				return apiErr
+			},
+			interactionRespondFunc: func(_ *discordgo.Interaction, _ *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+				return apiErr
+			},
+		}
+	}
+	helps := &sarah.CommandHelps{{Identifier: "echo", Instruction: "Echo"}}
+
+	tests := []struct {
+		name    string
+		config  func(*Config)
+		session func() *mockSession
+		output  func() sarah.Output
+	}{
+		{
+			name:    "string",
+			session: failing,
+			output:  func() sarah.Output { return sarah.NewOutputMessage(ChannelID("ch-1"), "hello") },
+		},
+		{
+			name:    "message send",
+			session: failing,
+			output: func() sarah.Output {
+				return sarah.NewOutputMessage(ChannelID("ch-1"), &discordgo.MessageSend{Content: "hello"})
+			},
+		},
+		{
+			name:    "reaction",
+			session: failing,
+			output: func() sarah.Output {
+				return sarah.NewOutputMessage(ChannelID("ch-1"), &reactionResponse{MessageID: "msg-1", Emoji: "👍"})
+			},
+		},
+		{
+			name: "reply following a reaction",
+			session: func() *mockSession {
+				mock := failing()
+				mock.messageReactionAddFunc = nil
+				return mock
+			},
+			output: func() sarah.Output {
+				return sarah.NewOutputMessage(ChannelID("ch-1"), &reactionResponse{MessageID: "msg-1", Emoji: "👍", Content: "hello"})
+			},
+		},
+		{
+			name:    "help as text",
+			session: failing,
+			output:  func() sarah.Output { return sarah.NewOutputMessage(ChannelID("ch-1"), helps) },
+		},
+		{
+			name:    "help as embeds",
+			config:  func(c *Config) { c.HelpAsEmbed = true },
+			session: failing,
+			output:  func() sarah.Output { return sarah.NewOutputMessage(ChannelID("ch-1"), helps) },
+		},
+		{
+			name:    "help as select menus",
+			config:  func(c *Config) { c.HelpAsSelectMenu = true },
+			session: failing,
+			output:  func() sarah.Output { return sarah.NewOutputMessage(ChannelID("ch-1"), helps) },
+		},
+		{
+			name:    "interaction",
+			session: failing,
+			output: func() sarah.Output {
+				input, _ := InteractionToInput(newCommandInteraction("ping"))
+				return sarah.NewOutputMessage(input.ReplyTo(), "pong")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			if tt.config != nil {
+				tt.config(config)
+			}
+			adapter := &Adapter{config: config, session: tt.session()}
+
+			err := adapter.SendMessageWithError(context.Background(), tt.output())
+			if !errors.Is(err, apiErr) {
+				t.Errorf("Expected the API error, got %+v", err)
+			}
+		})
+	}
+
+	t.Run("unexpected content", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		if err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), 1)); err == nil {
+			t.Error("Expected an error")
+		}
+		if failed := adapter.Stats().Total.SendFailed; failed != 1 {
+			t.Errorf("Expected the send to be counted as failed, got %d", failed)
+		}
+	})
+
+	t.Run("unexpected destination", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		if err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(nil, "hello")); err == nil {
+			t.Error("Expected an error")
+		}
+		if failed := adapter.Stats().Total.SendFailed; failed != 1 {
+			t.Errorf("Expected the send to be counted as failed, got %d", failed)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		if err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello")); err != nil {
+			t.Errorf("Unexpected error: %+v", err)
+		}
+	})
+}
+
+func TestAdapter_SendMessage(t *testing.T) {
+	t.Run("string content", func(t *testing.T) {
+		var gotChannelID, gotContent string
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				gotChannelID = channelID
+				gotContent = content
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		output := sarah.NewOutputMessage(ChannelID("ch-1"), "hello world")
+		adapter.SendMessage(context.Background(), output)
+
+		if gotChannelID != "ch-1" {
+			t.Errorf("Expected channelID %q, got %q", "ch-1", gotChannelID)
+		}
+		if gotContent != "hello world" {
+			t.Errorf("Expected content %q, got %q", "hello world", gotContent)
+		}
+	})
+
+	t.Run("string content with send error", func(t *testing.T) {
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return nil, fmt.Errorf("send failed")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		output := sarah.NewOutputMessage(ChannelID("ch-1"), "hello")
+		// Should not panic, just log the error
+		adapter.SendMessage(context.Background(), output)
+	})
+
+	t.Run("MessageSend content", func(t *testing.T) {
+		var gotChannelID string
+		var gotData *discordgo.MessageSend
+		mock := &mockSession{
+			channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				gotChannelID = channelID
+				gotData = data
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
 
 		msg := &discordgo.MessageSend{Content: "complex msg"}
 		output := sarah.NewOutputMessage(ChannelID("ch-2"), msg)
@@ -601,6 +1308,30 @@ func TestAdapter_SendMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("CommandHelps content with header", func(t *testing.T) {
+		var gotContent string
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				gotContent = content
+				return &discordgo.Message{}, nil
+			},
+		}
+		config := NewConfig()
+		config.HelpHeader = "Available commands:"
+		adapter := &Adapter{config: config, session: mock}
+
+		helps := &sarah.CommandHelps{
+			{Identifier: "echo", Instruction: "Input .echo to echo back.\nExample: .echo hello"},
+			{Identifier: "hello", Instruction: "Input .hello to greet"},
+		}
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-3"), helps))
+
+		expected := "Available commands:\n**echo**: Input .echo to echo back.\nExample: .echo hello\n**hello**: Input .hello to greet"
+		if gotContent != expected {
+			t.Errorf("Expected %q, got %q", expected, gotContent)
+		}
+	})
+
 	t.Run("CommandHelps content with send error", func(t *testing.T) {
 		mock := &mockSession{
 			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
@@ -676,6 +1407,7 @@ func TestMessageToInput(t *testing.T) {
 	now := time.Now()
 	m := &discordgo.MessageCreate{
 		Message: &discordgo.Message{
+			ID:        "message-789",
 			ChannelID: "channel-123",
 			Content:   "hello world",
 			Timestamp: now,
@@ -725,6 +1457,272 @@ func TestMessageToInput(t *testing.T) {
 			t.Error("Original event should be preserved in Input")
 		}
 	})
+
+	t.Run("MessageID", func(t *testing.T) {
+		if input.MessageID() != "message-789" {
+			t.Errorf("Expected MessageID %q, got %q", "message-789", input.MessageID())
+		}
+	})
+
+	t.Run("GuildID of direct message", func(t *testing.T) {
+		if input.GuildID() != "" {
+			t.Errorf("Expected empty GuildID, got %q", input.GuildID())
+		}
+	})
+
+	t.Run("IsDirectMessage of direct message", func(t *testing.T) {
+		if !input.IsDirectMessage() {
+			t.Error("Expected IsDirectMessage to be true")
+		}
+	})
+
+	t.Run("GuildID of guild message", func(t *testing.T) {
+		guildInput, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				GuildID:   "guild-789",
+				Author:    &discordgo.User{ID: "user-456"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if guildInput.GuildID() != "guild-789" {
+			t.Errorf("Expected GuildID %q, got %q", "guild-789", guildInput.GuildID())
+		}
+		if guildInput.SenderKey() != input.SenderKey() {
+			t.Errorf("SenderKey should not depend on the guild: %q", guildInput.SenderKey())
+		}
+		if guildInput.IsDirectMessage() {
+			t.Error("Expected IsDirectMessage to be false")
+		}
+	})
+}
+
+func TestMessageToInput_Mentions(t *testing.T) {
+	t.Run("two users", func(t *testing.T) {
+		input, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				Content:   ".kick <@111> <@222>",
+				Author:    &discordgo.User{ID: "user-456"},
+				Mentions:  []*discordgo.User{{ID: "111", Username: "alice"}, {ID: "222", Username: "bob"}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if len(input.Mentions()) != 2 || input.Mentions()[0].Username != "alice" {
+			t.Errorf("Unexpected mentions: %#v", input.Mentions())
+		}
+
+		ids := input.MentionedUserIDs()
+		if len(ids) != 2 || ids[0] != "111" || ids[1] != "222" {
+			t.Errorf("Unexpected IDs: %#v", ids)
+		}
+	})
+
+	t.Run("no mentions", func(t *testing.T) {
+		input, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				Content:   "hello",
+				Author:    &discordgo.User{ID: "user-456"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if input.Mentions() == nil || len(input.Mentions()) != 0 {
+			t.Errorf("Expected empty mentions, got %#v", input.Mentions())
+		}
+		if ids := input.MentionedUserIDs(); ids == nil || len(ids) != 0 {
+			t.Errorf("Expected empty IDs, got %#v", ids)
+		}
+	})
+}
+
+func TestMessageToInput_Attachments(t *testing.T) {
+	t.Run("one attachment", func(t *testing.T) {
+		input, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				Author:    &discordgo.User{ID: "user-456"},
+				Attachments: []*discordgo.MessageAttachment{
+					{ID: "att-1", Filename: "cat.png", URL: "https://cdn.discordapp.com/attachments/1/2/cat.png"},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		attachments := input.Attachments()
+		if len(attachments) != 1 {
+			t.Fatalf("Unexpected attachments: %#v", attachments)
+		}
+		if attachments[0].Filename != "cat.png" || attachments[0].URL != "https://cdn.discordapp.com/attachments/1/2/cat.png" {
+			t.Errorf("Unexpected attachment: %#v", attachments[0])
+		}
+	})
+
+	t.Run("no attachments", func(t *testing.T) {
+		input, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				Author:    &discordgo.User{ID: "user-456"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if input.Attachments() == nil || len(input.Attachments()) != 0 {
+			t.Errorf("Expected empty attachments, got %#v", input.Attachments())
+		}
+	})
+}
+
+func TestMessageToInput_AuthorDisplayName(t *testing.T) {
+	tests := []struct {
+		name     string
+		member   *discordgo.Member
+		author   *discordgo.User
+		expected string
+	}{
+		{
+			name:     "nickname",
+			member:   &discordgo.Member{Nick: "Sarah"},
+			author:   &discordgo.User{ID: "user-1", GlobalName: "Sarah Global", Username: "sarah_user"},
+			expected: "Sarah",
+		},
+		{
+			name:     "global name without nickname",
+			member:   &discordgo.Member{},
+			author:   &discordgo.User{ID: "user-1", GlobalName: "Sarah Global", Username: "sarah_user"},
+			expected: "Sarah Global",
+		},
+		{
+			name:     "username without global name",
+			member:   &discordgo.Member{},
+			author:   &discordgo.User{ID: "user-1", Username: "sarah_user"},
+			expected: "sarah_user",
+		},
+		{
+			name:     "direct message without member",
+			member:   nil,
+			author:   &discordgo.User{ID: "user-1", GlobalName: "Sarah Global", Username: "sarah_user"},
+			expected: "Sarah Global",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := MessageToInput(&discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "channel-123",
+					Author:    tt.author,
+					Member:    tt.member,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			if input.AuthorDisplayName() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, input.AuthorDisplayName())
+			}
+		})
+	}
+}
+
+func TestMessageToInput_ReferencedMessage(t *testing.T) {
+	t.Run("reply", func(t *testing.T) {
+		input, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				Content:   ".translate",
+				Author:    &discordgo.User{ID: "user-456"},
+				ReferencedMessage: &discordgo.Message{
+					ID:      "msg-1",
+					Content: "Bonjour",
+					Author:  &discordgo.User{ID: "user-789"},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		referenced := input.ReferencedMessage()
+		if referenced == nil || referenced.ID != "msg-1" || referenced.Content != "Bonjour" {
+			t.Errorf("Unexpected referenced message: %#v", referenced)
+		}
+	})
+
+	t.Run("not a reply", func(t *testing.T) {
+		input, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				Author:    &discordgo.User{ID: "user-456"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if input.ReferencedMessage() != nil {
+			t.Errorf("Expected no referenced message, got %#v", input.ReferencedMessage())
+		}
+	})
+}
+
+func TestMessageToInput_StickersAndFlags(t *testing.T) {
+	t.Run("sticker with flags", func(t *testing.T) {
+		input, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				Author:    &discordgo.User{ID: "user-456"},
+				Flags:     discordgo.MessageFlagsSuppressEmbeds,
+				StickerItems: []*discordgo.StickerItem{
+					{ID: "sticker-1", Name: "wave", FormatType: discordgo.StickerFormatTypePNG},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		stickers := input.StickerItems()
+		if len(stickers) != 1 || stickers[0].ID != "sticker-1" || stickers[0].Name != "wave" {
+			t.Errorf("Unexpected sticker items: %#v", stickers)
+		}
+		if input.Flags()&discordgo.MessageFlagsSuppressEmbeds == 0 {
+			t.Errorf("Expected the suppressed embeds flag, got %d", input.Flags())
+		}
+	})
+
+	t.Run("no sticker", func(t *testing.T) {
+		input, err := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "channel-123",
+				Author:    &discordgo.User{ID: "user-456"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if input.StickerItems() == nil || len(input.StickerItems()) != 0 {
+			t.Errorf("Expected empty sticker items, got %#v", input.StickerItems())
+		}
+		if input.Flags() != 0 {
+			t.Errorf("Expected no flags, got %d", input.Flags())
+		}
+	})
 }
 
 func TestInput_SarahInputInterface(t *testing.T) {
@@ -932,6 +1930,191 @@ func TestNewResponse(t *testing.T) {
 	})
 }
 
+func TestNewResponse_Context(t *testing.T) {
+	newInput := func(guildID string) *Input {
+		input, _ := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "ch",
+				GuildID:   guildID,
+				Content:   ".cmd",
+				Timestamp: time.Now(),
+				Author:    &discordgo.User{ID: "user"},
+			},
+		})
+		return input
+	}
+	next := func(_ context.Context, _ sarah.Input) (*sarah.CommandResponse, error) { return nil, nil }
+
+	tests := []struct {
+		name     string
+		guildID  string
+		option   RespOption
+		expected string
+		rejected bool
+	}{
+		{name: "guild-only in guild", guildID: "guild", option: RespGuildOnly("server only"), expected: "ok"},
+		{name: "guild-only in DM", guildID: "", option: RespGuildOnly("server only"), expected: "server only", rejected: true},
+		{name: "guild-only in DM with default message", guildID: "", option: RespGuildOnly(""), expected: defaultGuildOnlyMessage, rejected: true},
+		{name: "DM-only in DM", guildID: "", option: RespDMOnly("DM only"), expected: "ok"},
+		{name: "DM-only in guild", guildID: "guild", option: RespDMOnly("DM only"), expected: "DM only", rejected: true},
+		{name: "DM-only in guild with default message", guildID: "guild", option: RespDMOnly(""), expected: defaultDMOnlyMessage, rejected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewResponse(newInput(tt.guildID), "ok", tt.option, RespWithNext(next))
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			if resp.Content != tt.expected {
+				t.Errorf("Expected content %q, got %v", tt.expected, resp.Content)
+			}
+
+			if tt.rejected && resp.UserContext != nil {
+				t.Error("Expected no UserContext for rejected response")
+			}
+			if !tt.rejected && resp.UserContext == nil {
+				t.Error("Expected UserContext to be kept")
+			}
+		})
+	}
+
+	t.Run("input without event", func(t *testing.T) {
+		input := &Input{senderKey: "ch_user", channelID: ChannelID("ch")}
+
+		resp, err := NewResponse(input, "ok", RespGuildOnly(""))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if resp.Content != "ok" {
+			t.Errorf("Expected content %q, got %v", "ok", resp.Content)
+		}
+	})
+}
+
+// applyRequestOptions applies the given options to an empty request configuration for inspection.
+func applyRequestOptions(options []discordgo.RequestOption) *discordgo.RequestConfig {
+	cfg := &discordgo.RequestConfig{
+		Request:                httptest.NewRequest(http.MethodGet, "https://discord.com/api/v9", nil),
+		ShouldRetryOnRateLimit: true,
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+	return cfg
+}
+
+func TestAdapter_SendMessage_Context(t *testing.T) {
+	newSession := func(received *[][]discordgo.RequestOption) *mockSession {
+		return &mockSession{
+			channelMessageSendFunc: func(_ string, _ string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				*received = append(*received, options)
+				return &discordgo.Message{}, nil
+			},
+			channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				*received = append(*received, options)
+				return &discordgo.Message{}, nil
+			},
+		}
+	}
+
+	t.Run("canceled context is applied", func(t *testing.T) {
+		var received [][]discordgo.RequestOption
+		adapter := &Adapter{config: NewConfig(), session: newSession(&received)}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		adapter.SendMessage(ctx, sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+		adapter.SendMessage(ctx, sarah.NewOutputMessage(ChannelID("ch-1"), &discordgo.MessageSend{Content: "hello"}))
+
+		if len(received) != 2 {
+			t.Fatalf("Unexpected calls: %d", len(received))
+		}
+		for i, options := range received {
+			cfg := applyRequestOptions(options)
+			if !errors.Is(cfg.Request.Context().Err(), context.Canceled) {
+				t.Errorf("Expected canceled context on call %d, got %+v", i, cfg.Request.Context().Err())
+			}
+		}
+	})
+
+	t.Run("send timeout is applied", func(t *testing.T) {
+		var received [][]discordgo.RequestOption
+		config := NewConfig()
+		config.SendTimeout = time.Minute
+		adapter := &Adapter{config: config, session: newSession(&received)}
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+
+		if len(received) != 1 {
+			t.Fatalf("Unexpected calls: %d", len(received))
+		}
+		deadline, ok := applyRequestOptions(received[0]).Request.Context().Deadline()
+		if !ok || time.Until(deadline) > time.Minute {
+			t.Errorf("Expected a deadline within a minute, got %s", deadline)
+		}
+	})
+}
+
+func TestAdapter_DefaultRequestOptions(t *testing.T) {
+	var received [][]discordgo.RequestOption
+	mock := &mockSession{
+		channelMessageSendFunc: func(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			received = append(received, options)
+			return &discordgo.Message{ID: "msg-1"}, nil
+		},
+		channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			received = append(received, options)
+			return &discordgo.Message{}, nil
+		},
+		channelFunc: func(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			received = append(received, options)
+			return &discordgo.Channel{ID: channelID}, nil
+		},
+		messageReactionAddFunc: func(channelID string, messageID string, emojiID string, options ...discordgo.RequestOption) error {
+			received = append(received, options)
+			return nil
+		},
+		channelMessageFunc: func(channelID string, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			received = append(received, options)
+			return &discordgo.Message{ID: messageID}, nil
+		},
+	}
+	config := NewConfig()
+	config.DefaultRequestOptions = []discordgo.RequestOption{
+		discordgo.WithHeader("X-Trace-Id", "trace-1"),
+		discordgo.WithRestRetries(7),
+	}
+	adapter := &Adapter{config: config, session: mock}
+	ctx := context.Background()
+
+	adapter.SendMessage(ctx, sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+	adapter.SendMessage(ctx, sarah.NewOutputMessage(ChannelID("ch-1"), &discordgo.MessageSend{Content: "hello"}))
+	_, _ = adapter.channel("ch-1")
+	_ = adapter.AddReactions(ctx, ChannelID("ch-1"), "msg-1", "👍")
+	_, _ = adapter.MessageFromURL(ctx, "https://discord.com/channels/123/456/789")
+
+	if len(received) != 5 {
+		t.Fatalf("Expected 5 calls, got %d", len(received))
+	}
+	for i, options := range received {
+		cfg := applyRequestOptions(options)
+		if cfg.Request.Header.Get("X-Trace-Id") != "trace-1" {
+			t.Errorf("Expected the header to be set on call %d", i)
+		}
+		if cfg.MaxRestRetries != 7 {
+			t.Errorf("Expected MaxRestRetries to be 7 on call %d, got %d", i, cfg.MaxRestRetries)
+		}
+	}
+
+	// Options specific to the call take precedence over the defaults.
+	if applyRequestOptions(received[3]).ShouldRetryOnRateLimit {
+		t.Error("Expected the reaction call to disable the retry on rate limits")
+	}
+}
+
 func TestWithSession(t *testing.T) {
 	session := &discordgo.Session{}
 	adapter := &Adapter{}
@@ -953,3 +2136,281 @@ func TestChannelID_OutputDestination(t *testing.T) {
 		t.Errorf("Expected %q, got %q", "test-channel", string(chID))
 	}
 }
+
+func TestAdapter_handleMessage_CommandMatchers(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	helpPattern := regexp.MustCompile(`^[.!]help$`)
+	tests := []struct {
+		name    string
+		prefix  string
+		content string
+		help    bool
+		abort   bool
+	}{
+		{name: "dot help", content: ".help", help: true},
+		{name: "exclamation help", content: "!help", help: true},
+		{name: "help with arguments", content: "!help ping"},
+		{name: "default abort without matcher", content: ".abort", abort: true},
+		{name: "prefixed help", prefix: "!", content: "!help", help: true},
+		{name: "help without the prefix", prefix: "!", content: ".help"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.CommandPrefix = tt.prefix
+			config.HelpMatcher = helpPattern.MatchString
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			var received sarah.Input
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "ch-1",
+					GuildID:   "guild-1",
+					Content:   tt.content,
+					Author:    &discordgo.User{ID: "user-1"},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(input sarah.Input) error {
+				received = input
+				return nil
+			})
+
+			_, help := received.(*sarah.HelpInput)
+			_, abort := received.(*sarah.AbortInput)
+			if help != tt.help || abort != tt.abort {
+				t.Errorf("Unexpected input: %T", received)
+			}
+		})
+	}
+
+	t.Run("abort matcher overrides the command", func(t *testing.T) {
+		config := NewConfig()
+		config.AbortMatcher = func(text string) bool { return text == "stop" }
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		for content, expected := range map[string]bool{"stop": true, ".abort": false} {
+			var received sarah.Input
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{ChannelID: "ch-1", Content: content, Author: &discordgo.User{ID: "user-1"}},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(input sarah.Input) error {
+				received = input
+				return nil
+			})
+
+			if _, abort := received.(*sarah.AbortInput); abort != expected {
+				t.Errorf("Unexpected input for %q: %T", content, received)
+			}
+		}
+	})
+}
+
+func TestAdapter_handleMessage_MaxMessageAge(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name      string
+		maxAge    time.Duration
+		timestamp time.Time
+		enqueued  bool
+	}{
+		{name: "old message", maxAge: time.Minute, timestamp: time.Now().Add(-time.Hour), enqueued: false},
+		{name: "recent message", maxAge: time.Minute, timestamp: time.Now().Add(-time.Second), enqueued: true},
+		{name: "old message without the limit", maxAge: 0, timestamp: time.Now().Add(-time.Hour), enqueued: true},
+		{name: "message without timestamp", maxAge: time.Minute, timestamp: time.Time{}, enqueued: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.MaxMessageAge = tt.maxAge
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			var enqueued bool
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ID:        "msg-1",
+					ChannelID: "ch-1",
+					Content:   ".ping",
+					Timestamp: tt.timestamp,
+					Author:    &discordgo.User{ID: "user-1"},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(sarah.Input) error {
+				enqueued = true
+				return nil
+			})
+
+			if enqueued != tt.enqueued {
+				t.Errorf("Expected enqueued to be %t", tt.enqueued)
+			}
+			if !tt.enqueued && adapter.Stats().Total.Dropped != 1 {
+				t.Errorf("Expected the message to be counted as dropped: %+v", adapter.Stats().Total)
+			}
+		})
+	}
+}
+
+func TestAdapter_SendTo(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		var channelID, content string
+		mock := &mockSession{
+			channelMessageSendFunc: func(c string, text string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				channelID, content = c, text
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.SendTo(context.Background(), ChannelID("modlog"), "user-1 was kicked"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if channelID != "modlog" || content != "user-1 was kicked" {
+			t.Errorf("Unexpected message %q to %s", content, channelID)
+		}
+	})
+
+	t.Run("response content", func(t *testing.T) {
+		var channelID string
+		var sent *discordgo.MessageSend
+		mock := &mockSession{
+			channelMessageSendComplexFunc: func(c string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				channelID, sent = c, data
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		// The content of a response built for the input is sent to the other channel.
+		response, err := NewResponse(newReplyInput("guild-1"), "user-1 was kicked", RespWithEmbeds(&discordgo.MessageEmbed{Title: "Kick"}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if err := adapter.SendTo(context.Background(), ChannelID("modlog"), response.Content); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if channelID != "modlog" || sent == nil || len(sent.Embeds) != 1 {
+			t.Errorf("Unexpected message %#v to %s", sent, channelID)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		apiErr := errors.New("api error")
+		mock := &mockSession{
+			channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return nil, apiErr
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.SendTo(context.Background(), ChannelID("modlog"), "hello"); !errors.Is(err, apiErr) {
+			t.Errorf("Expected the API error, got %+v", err)
+		}
+	})
+}
+
+func TestInput_Context(t *testing.T) {
+	t.Run("cancellation of the Run context is observable", func(t *testing.T) {
+		type traceKey struct{}
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), traceKey{}, "trace-1"))
+		defer cancel()
+
+		var handler func(*discordgo.Session, *discordgo.MessageCreate)
+		opened := make(chan struct{})
+		mock := &mockSession{
+			addHandlerFunc: func(h interface{}) func() {
+				if h, ok := h.(func(*discordgo.Session, *discordgo.MessageCreate)); ok {
+					handler = h
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				close(opened)
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		received := make(chan sarah.Input, 1)
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			adapter.Run(ctx, func(input sarah.Input) error {
+				received <- input
+				return nil
+			}, func(error) {})
+		}()
+		<-opened
+
+		s := &discordgo.Session{State: discordgo.NewState()}
+		s.State.User = &discordgo.User{ID: "bot-1"}
+		handler(s, &discordgo.MessageCreate{
+			Message: &discordgo.Message{ID: "msg-1", ChannelID: "ch-1", Content: "hello", Author: &discordgo.User{ID: "user-1"}},
+		})
+		input := (<-received).(*Input)
+
+		if input.Context().Value(traceKey{}) != "trace-1" {
+			t.Errorf("Expected the value set on the Run context, got %v", input.Context().Value(traceKey{}))
+		}
+		if input.Context().Err() != nil {
+			t.Fatalf("Unexpected cancellation: %+v", input.Context().Err())
+		}
+
+		cancel()
+		select {
+		case <-input.Context().Done():
+		case <-time.After(time.Second):
+			t.Fatal("Expected the cancellation to be observable")
+		}
+		<-stopped
+	})
+
+	t.Run("input not received by the Adapter", func(t *testing.T) {
+		input := &Input{}
+		if input.Context() == nil || input.Context().Err() != nil {
+			t.Errorf("Expected a background context, got %v", input.Context())
+		}
+	})
+}
+
+func TestAdapter_handleMessage_SanitizeIncomingMentions(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name     string
+		sanitize bool
+		expected string
+	}{
+		{name: "enabled", sanitize: true, expected: ".echo @\u200beveryone"},
+		{name: "disabled", sanitize: false, expected: ".echo @everyone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.SanitizeIncomingMentions = tt.sanitize
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			var received sarah.Input
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{ChannelID: "ch-1", Content: ".echo @everyone", Author: &discordgo.User{ID: "user-1"}},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(input sarah.Input) error {
+				received = input
+				return nil
+			})
+
+			if received == nil {
+				t.Fatal("Expected input to be enqueued")
+			}
+			if received.Message() != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, received.Message())
+			}
+		})
+	}
+}
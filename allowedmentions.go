@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// RespWithAllowedMentions controls which mentions in the response actually notify users.
+// This overrides Config.DefaultAllowedMentions for the response.
+// Pass &discordgo.MessageAllowedMentions{} to suppress every ping, e.g., when echoing user content.
+func RespWithAllowedMentions(mentions *discordgo.MessageAllowedMentions) RespOption {
+	return func(options *respOptions) {
+		options.allowedMentions = mentions
+	}
+}
+
+// withAllowedMentions converts the given response content to a *discordgo.MessageSend with the given allowed mentions.
+// The given *discordgo.MessageSend is copied so the caller's value is not modified.
+func withAllowedMentions(content any, mentions *discordgo.MessageAllowedMentions) any {
+	switch typed := content.(type) {
+	case string:
+		// An empty string means no message, e.g., when only a reaction is sent.
+		if typed == "" {
+			return content
+		}
+		return &discordgo.MessageSend{
+			Content:         typed,
+			AllowedMentions: mentions,
+		}
+
+	case *discordgo.MessageSend:
+		copied := *typed
+		copied.AllowedMentions = mentions
+		return &copied
+
+	default:
+		return content
+	}
+}
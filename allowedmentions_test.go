@@ -0,0 +1,138 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_SendMessage_DefaultAllowedMentions(t *testing.T) {
+	t.Run("nil keeps the plain send", func(t *testing.T) {
+		plain := false
+		mock := &mockSession{
+			channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				plain = true
+				return &discordgo.Message{}, nil
+			},
+			channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				t.Error("ChannelMessageSendComplex should not be called")
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "@everyone hi"))
+
+		if !plain {
+			t.Error("Expected ChannelMessageSend to be called")
+		}
+	})
+
+	t.Run("string content is sent with allowed mentions", func(t *testing.T) {
+		allowed := &discordgo.MessageAllowedMentions{Users: []string{"user-1"}}
+		var sent *discordgo.MessageSend
+		mock := &mockSession{
+			channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sent = data
+				return &discordgo.Message{}, nil
+			},
+		}
+		config := NewConfig()
+		config.DefaultAllowedMentions = allowed
+		adapter := &Adapter{config: config, session: mock}
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "@everyone hi"))
+
+		if sent == nil || sent.Content != "@everyone hi" || sent.AllowedMentions != allowed {
+			t.Errorf("Unexpected message: %#v", sent)
+		}
+	})
+
+	t.Run("rich content keeps its own allowed mentions", func(t *testing.T) {
+		own := &discordgo.MessageAllowedMentions{Parse: []discordgo.AllowedMentionType{discordgo.AllowedMentionTypeRoles}}
+		var sent []*discordgo.MessageSend
+		mock := &mockSession{
+			channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sent = append(sent, data)
+				return &discordgo.Message{}, nil
+			},
+		}
+		config := NewConfig()
+		config.DefaultAllowedMentions = &discordgo.MessageAllowedMentions{}
+		adapter := &Adapter{config: config, session: mock}
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), &discordgo.MessageSend{Content: "a", AllowedMentions: own}))
+		original := &discordgo.MessageSend{Content: "b"}
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), original))
+
+		if len(sent) != 2 {
+			t.Fatalf("Unexpected messages: %#v", sent)
+		}
+		if sent[0].AllowedMentions != own {
+			t.Errorf("Own allowed mentions should be kept: %#v", sent[0].AllowedMentions)
+		}
+		if sent[1].AllowedMentions != config.DefaultAllowedMentions {
+			t.Errorf("Default allowed mentions should be applied: %#v", sent[1].AllowedMentions)
+		}
+		if original.AllowedMentions != nil {
+			t.Error("Original content should not be modified")
+		}
+	})
+}
+
+func TestRespWithAllowedMentions(t *testing.T) {
+	input, _ := MessageToInput(&discordgo.MessageCreate{
+		Message: &discordgo.Message{
+			ID:        "msg-1",
+			ChannelID: "ch-1",
+			Author:    &discordgo.User{ID: "user-1"},
+		},
+	})
+	allowed := &discordgo.MessageAllowedMentions{}
+
+	t.Run("string content", func(t *testing.T) {
+		response, err := NewResponse(input, "@everyone", RespWithAllowedMentions(allowed))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		data, ok := response.Content.(*discordgo.MessageSend)
+		if !ok || data.Content != "@everyone" || data.AllowedMentions != allowed {
+			t.Errorf("Unexpected content: %#v", response.Content)
+		}
+	})
+
+	t.Run("with reply", func(t *testing.T) {
+		response, err := NewResponse(input, "@everyone", RespAsReply(), RespWithAllowedMentions(allowed))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		data, ok := response.Content.(*discordgo.MessageSend)
+		if !ok || data.Reference == nil || data.AllowedMentions != allowed {
+			t.Errorf("Unexpected content: %#v", response.Content)
+		}
+	})
+
+	t.Run("overrides the default", func(t *testing.T) {
+		var sent *discordgo.MessageSend
+		mock := &mockSession{
+			channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sent = data
+				return &discordgo.Message{}, nil
+			},
+		}
+		config := NewConfig()
+		config.DefaultAllowedMentions = &discordgo.MessageAllowedMentions{Users: []string{"user-1"}}
+		adapter := &Adapter{config: config, session: mock}
+
+		response, _ := NewResponse(input, "@everyone", RespWithAllowedMentions(allowed))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+		if sent == nil || sent.AllowedMentions != allowed {
+			t.Errorf("Unexpected message: %#v", sent)
+		}
+	})
+}
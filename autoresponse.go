@@ -0,0 +1,100 @@
+package discord
+
+import (
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// autoResponse is a pair of a keyword pattern and its response.
+type autoResponse struct {
+	pattern  *regexp.Regexp
+	response string
+}
+
+// autoResponses returns Config.AutoResponses ordered by their patterns so the matching is deterministic.
+func (a *Adapter) autoResponses() []autoResponse {
+	responses := make([]autoResponse, 0, len(a.config.AutoResponses))
+	for pattern, response := range a.config.AutoResponses {
+		if pattern != nil {
+			responses = append(responses, autoResponse{pattern: pattern, response: response})
+		}
+	}
+
+	slices.SortFunc(responses, func(x, y autoResponse) int {
+		if x.pattern.String() < y.pattern.String() {
+			return -1
+		}
+		if x.pattern.String() > y.pattern.String() {
+			return 1
+		}
+		return 0
+	})
+	return responses
+}
+
+// autoRespond replies with the response of the first pattern that matches the given text.
+// A pattern that has already responded in the channel within Config.AutoResponseCooldown is skipped.
+// true is returned when a response is sent, in which case the message should not be passed to go-sarah.
+func (a *Adapter) autoRespond(input *Input, text string) bool {
+	if len(a.config.AutoResponses) == 0 {
+		return false
+	}
+
+	channelID := string(input.channelID)
+	now := time.Now()
+	for _, auto := range a.autoResponses() {
+		if !auto.pattern.MatchString(text) {
+			continue
+		}
+
+		if !a.autoResponseCooldown.acquire(channelID+"\x00"+auto.pattern.String(), a.config.AutoResponseCooldown, now) {
+			a.log().Debugf("[%s] Auto response for %s is cooling down in %s", input.correlationID, auto.pattern, channelID)
+			continue
+		}
+
+		err := a.SendMessageWithError(input.Context(), sarah.NewOutputMessage(input.channelID, auto.response))
+		if err != nil {
+			a.log().Errorf("[%s] Failed to send auto response to %s: %+v", input.correlationID, channelID, err)
+		}
+		return true
+	}
+
+	return false
+}
+
+// cooldownTracker remembers when each key was last used to keep a key from being used again within a cooldown.
+// The zero value is ready to use.
+type cooldownTracker struct {
+	mutex    sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+// acquire tells if the key can be used now, and records the use if so.
+func (c *cooldownTracker) acquire(key string, cooldown time.Duration, now time.Time) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.lastUsed == nil {
+		c.lastUsed = map[string]time.Time{}
+	}
+
+	if last, ok := c.lastUsed[key]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+
+	// Sweep expired keys occasionally so the map does not grow with every channel ever seen.
+	if len(c.lastUsed) >= 1024 {
+		for k, last := range c.lastUsed {
+			if now.Sub(last) >= cooldown {
+				delete(c.lastUsed, k)
+			}
+		}
+	}
+
+	c.lastUsed[key] = now
+	return true
+}
@@ -0,0 +1,137 @@
+package discord
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_handleMessage_AutoResponses(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	newMessage := func(content string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "ch-1",
+				Content:   content,
+				Author:    &discordgo.User{ID: "user-1"},
+			},
+		}
+	}
+
+	setup := func() (*Adapter, *[]string, *[]sarah.Input, func(sarah.Input) error) {
+		var sent []string
+		var enqueued []sarah.Input
+		config := NewConfig()
+		config.CommandPrefix = "!"
+		config.AutoResponses = map[*regexp.Regexp]string{
+			regexp.MustCompile(`(?i)where.*docs`): "See the docs.",
+			regexp.MustCompile(`(?i)docs`):        "Docs are here.",
+		}
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sent = append(sent, content)
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: config, session: mock}
+		enqueue := func(input sarah.Input) error {
+			enqueued = append(enqueued, input)
+			return nil
+		}
+		return adapter, &sent, &enqueued, enqueue
+	}
+
+	t.Run("matched message is answered without go-sarah", func(t *testing.T) {
+		adapter, sent, enqueued, enqueue := setup()
+
+		adapter.handleMessage(context.Background(), s, newMessage("Where are the docs?"), enqueue)
+
+		if len(*sent) != 1 || (*sent)[0] != "Docs are here." {
+			t.Errorf("Unexpected responses: %#v", *sent)
+		}
+		if len(*enqueued) != 0 {
+			t.Errorf("Matched message should not be enqueued: %#v", *enqueued)
+		}
+	})
+
+	t.Run("unmatched message is enqueued", func(t *testing.T) {
+		adapter, sent, enqueued, enqueue := setup()
+
+		adapter.handleMessage(context.Background(), s, newMessage("hello"), enqueue)
+
+		if len(*sent) != 0 {
+			t.Errorf("Unexpected responses: %#v", *sent)
+		}
+		if len(*enqueued) != 1 {
+			t.Errorf("Expected message to be enqueued: %#v", *enqueued)
+		}
+	})
+
+	t.Run("cooling down pattern is skipped", func(t *testing.T) {
+		adapter, sent, enqueued, enqueue := setup()
+		adapter.config.AutoResponses = map[*regexp.Regexp]string{
+			regexp.MustCompile(`docs`): "Docs are here.",
+		}
+
+		adapter.handleMessage(context.Background(), s, newMessage("docs"), enqueue)
+		adapter.handleMessage(context.Background(), s, newMessage("docs"), enqueue)
+
+		if len(*sent) != 1 {
+			t.Errorf("Expected one response during cooldown: %#v", *sent)
+		}
+		if len(*enqueued) != 1 {
+			t.Errorf("Expected the second message to be enqueued: %#v", *enqueued)
+		}
+	})
+
+	t.Run("command invocation is not auto-responded", func(t *testing.T) {
+		adapter, sent, enqueued, enqueue := setup()
+
+		adapter.handleMessage(context.Background(), s, newMessage("!docs"), enqueue)
+
+		if len(*sent) != 0 {
+			t.Errorf("Unexpected responses: %#v", *sent)
+		}
+		if len(*enqueued) != 1 {
+			t.Errorf("Expected invocation to be enqueued: %#v", *enqueued)
+		}
+	})
+
+	t.Run("command invocation is auto-responded when configured", func(t *testing.T) {
+		adapter, sent, enqueued, enqueue := setup()
+		adapter.config.AutoRespondToInvocations = true
+
+		adapter.handleMessage(context.Background(), s, newMessage("!docs"), enqueue)
+
+		if len(*sent) != 1 {
+			t.Errorf("Expected a response: %#v", *sent)
+		}
+		if len(*enqueued) != 0 {
+			t.Errorf("Unexpected enqueued inputs: %#v", *enqueued)
+		}
+	})
+}
+
+func TestCooldownTracker_acquire(t *testing.T) {
+	tracker := &cooldownTracker{}
+	now := time.Now()
+
+	if !tracker.acquire("key", time.Minute, now) {
+		t.Error("First use should be permitted")
+	}
+	if tracker.acquire("key", time.Minute, now.Add(30*time.Second)) {
+		t.Error("Use within the cooldown should not be permitted")
+	}
+	if !tracker.acquire("other", time.Minute, now) {
+		t.Error("Other key should be permitted")
+	}
+	if !tracker.acquire("key", time.Minute, now.Add(time.Minute)) {
+		t.Error("Use after the cooldown should be permitted")
+	}
+}
@@ -0,0 +1,99 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Channel returns the channel's metadata such as the name, the type, and the parent channel.
+// The fetched channel is cached for Config.ChannelCacheTTL so frequent lookups do not hit Discord's rate limits,
+// and the cache is discarded when the channel is updated or deleted while Run is connected.
+func (a *Adapter) Channel(ctx context.Context, channelID ChannelID) (*discordgo.Channel, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is empty")
+	}
+
+	fetch := func() (*discordgo.Channel, error) {
+		channel, err := a.session.Channel(string(channelID), a.requestOptions(discordgo.WithContext(ctx))...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch channel %s: %w", channelID, err)
+		}
+		return channel, nil
+	}
+
+	if a.config.ChannelCacheTTL <= 0 {
+		return fetch()
+	}
+	return a.channels.get(string(channelID), a.config.ChannelCacheTTL, time.Now(), fetch)
+}
+
+// channelCache caches the fetched channels by their IDs. The zero value is ready to use.
+type channelCache struct {
+	mutex     sync.Mutex
+	entries   map[string]*channelCacheEntry
+	lastSweep time.Time
+}
+
+type channelCacheEntry struct {
+	channel   *discordgo.Channel
+	fetchedAt time.Time
+}
+
+// get returns the cached channel, or fetches and caches it when not cached or expired.
+// Expired entries are evicted when a channel is cached so channels looked up only once do not stay forever.
+// The eviction scans every entry, so it runs at most once per TTL.
+func (c *channelCache) get(channelID string, ttl time.Duration, now time.Time, fetch func() (*discordgo.Channel, error)) (*discordgo.Channel, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[channelID]
+	c.mutex.Unlock()
+	if ok && now.Sub(entry.fetchedAt) < ttl {
+		return entry.channel, nil
+	}
+
+	channel, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]*channelCacheEntry{}
+	}
+	if now.Sub(c.lastSweep) >= ttl {
+		for id, entry := range c.entries {
+			if now.Sub(entry.fetchedAt) >= ttl {
+				delete(c.entries, id)
+			}
+		}
+		c.lastSweep = now
+	}
+	c.entries[channelID] = &channelCacheEntry{channel: channel, fetchedAt: now}
+
+	return channel, nil
+}
+
+// forget discards the cached channel.
+func (c *channelCache) forget(channelID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, channelID)
+}
+
+// forgetGuild discards the cached channels of the given guild.
+func (c *channelCache) forgetGuild(guildID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for channelID, entry := range c.entries {
+		if entry.channel.GuildID == guildID {
+			delete(c.entries, channelID)
+		}
+	}
+}
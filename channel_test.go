@@ -0,0 +1,156 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAdapter_Channel(t *testing.T) {
+	t.Run("cached channel is reused", func(t *testing.T) {
+		fetched := 0
+		mock := &mockSession{
+			channelFunc: func(channelID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				fetched++
+				return &discordgo.Channel{ID: channelID, Name: "general", Type: discordgo.ChannelTypeGuildText}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		for range 3 {
+			channel, err := adapter.Channel(context.Background(), ChannelID("ch-1"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if channel.ID != "ch-1" || channel.Name != "general" {
+				t.Errorf("Unexpected channel: %#v", channel)
+			}
+		}
+
+		if _, err := adapter.Channel(context.Background(), ChannelID("ch-2")); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if fetched != 2 {
+			t.Errorf("Expected each channel to be fetched once, got %d fetches", fetched)
+		}
+	})
+
+	t.Run("cache disabled", func(t *testing.T) {
+		fetched := 0
+		mock := &mockSession{
+			channelFunc: func(channelID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				fetched++
+				return &discordgo.Channel{ID: channelID}, nil
+			},
+		}
+		config := NewConfig()
+		config.ChannelCacheTTL = 0
+		adapter := &Adapter{config: config, session: mock}
+
+		for range 2 {
+			if _, err := adapter.Channel(context.Background(), ChannelID("ch-1")); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+		}
+
+		if fetched != 2 {
+			t.Errorf("Expected every call to fetch the channel, got %d fetches", fetched)
+		}
+	})
+
+	t.Run("failure is not cached", func(t *testing.T) {
+		fetched := 0
+		mock := &mockSession{
+			channelFunc: func(channelID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				fetched++
+				if fetched == 1 {
+					return nil, errors.New("unknown channel")
+				}
+				return &discordgo.Channel{ID: channelID}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if _, err := adapter.Channel(context.Background(), ChannelID("ch-1")); err == nil {
+			t.Fatal("Expected an error")
+		}
+		if _, err := adapter.Channel(context.Background(), ChannelID("ch-1")); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+	})
+
+	t.Run("empty channel ID", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		if _, err := adapter.Channel(context.Background(), ""); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestChannelCache_get(t *testing.T) {
+	fetched := 0
+	fetch := func() (*discordgo.Channel, error) {
+		fetched++
+		return &discordgo.Channel{ID: "ch-1"}, nil
+	}
+
+	cache := &channelCache{}
+	now := time.Now()
+	ttl := time.Minute
+
+	_, _ = cache.get("ch-1", ttl, now, fetch)
+	_, _ = cache.get("ch-1", ttl, now.Add(ttl-time.Second), fetch)
+	if fetched != 1 {
+		t.Fatalf("Expected the cache to be hit within the TTL, got %d fetches", fetched)
+	}
+
+	_, _ = cache.get("ch-1", ttl, now.Add(ttl), fetch)
+	if fetched != 2 {
+		t.Fatalf("Expected the cache to expire after the TTL, got %d fetches", fetched)
+	}
+
+	cache.forget("ch-1")
+	_, _ = cache.get("ch-1", ttl, now.Add(ttl), fetch)
+	if fetched != 3 {
+		t.Errorf("Expected the forgotten channel to be fetched again, got %d fetches", fetched)
+	}
+
+	_, _ = cache.get("ch-2", ttl, now.Add(3*ttl), fetch)
+	if _, ok := cache.entries["ch-1"]; ok {
+		t.Error("Expected the expired channel to be evicted")
+	}
+
+	// The next sweep waits for the TTL since the last one.
+	_, _ = cache.get("ch-3", ttl, now.Add(3*ttl+ttl/2), fetch)
+	_, _ = cache.get("ch-4", ttl, now.Add(4*ttl+time.Second), fetch)
+	_, _ = cache.get("ch-5", ttl, now.Add(5*ttl), fetch)
+	if _, ok := cache.entries["ch-3"]; !ok {
+		t.Error("Expected the expired channel to be kept until the next sweep")
+	}
+}
+
+func TestAdapter_channel(t *testing.T) {
+	fetched := 0
+	mock := &mockSession{
+		channelFunc: func(channelID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			fetched++
+			return &discordgo.Channel{ID: channelID}, nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+
+	for range 2 {
+		if _, err := adapter.channel("ch-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+	}
+
+	if fetched != 1 {
+		t.Errorf("Expected the fetched channel to be cached, got %d fetches", fetched)
+	}
+}
@@ -0,0 +1,11 @@
+package discord
+
+// channelAllowed tells if messages in the given channel should be handled in terms of
+// Config.AllowedChannels and Config.BlockedChannels.
+// Like Config.BlockedUsers, the lists are read once on the first call.
+func (a *Adapter) channelAllowed(channelID string) bool {
+	// Look up both lists so they are read at the same time.
+	blocked := a.blockedChannels.contains(a.config.BlockedChannels, channelID)
+	allowed := a.allowedChannels.empty(a.config.AllowedChannels) || a.allowedChannels.contains(a.config.AllowedChannels, channelID)
+	return allowed && !blocked
+}
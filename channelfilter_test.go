@@ -0,0 +1,84 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_handleMessage_ChannelFilter(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name      string
+		allowed   []string
+		blocked   []string
+		channelID string
+		enqueued  bool
+	}{
+		{name: "no lists", channelID: "ch-1", enqueued: true},
+		{name: "allowed channel", allowed: []string{"ch-1"}, channelID: "ch-1", enqueued: true},
+		{name: "channel not allowed", allowed: []string{"ch-1"}, channelID: "ch-2", enqueued: false},
+		{name: "blocked channel", blocked: []string{"ch-1"}, channelID: "ch-1", enqueued: false},
+		{name: "channel not blocked", blocked: []string{"ch-1"}, channelID: "ch-2", enqueued: true},
+		{name: "blocklist wins over allowlist", allowed: []string{"ch-1", "ch-2"}, blocked: []string{"ch-1"}, channelID: "ch-1", enqueued: false},
+		{name: "allowed and not blocked", allowed: []string{"ch-1", "ch-2"}, blocked: []string{"ch-1"}, channelID: "ch-2", enqueued: true},
+		{name: "neither allowed nor blocked", allowed: []string{"ch-1"}, blocked: []string{"ch-2"}, channelID: "ch-3", enqueued: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.AllowedChannels = tt.allowed
+			config.BlockedChannels = tt.blocked
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			enqueued := false
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: tt.channelID,
+					Content:   "hello",
+					Author:    &discordgo.User{ID: "user-1"},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(sarah.Input) error {
+				enqueued = true
+				return nil
+			})
+
+			if enqueued != tt.enqueued {
+				t.Errorf("Expected enqueued to be %t", tt.enqueued)
+			}
+			if !tt.enqueued && adapter.Stats().Total.Dropped != 1 {
+				t.Errorf("Expected the message to be counted as dropped: %#v", adapter.Stats().Total)
+			}
+		})
+	}
+}
+
+func TestAdapter_channelAllowed_ReadOnce(t *testing.T) {
+	config := NewConfig()
+	config.BlockedChannels = []string{"ch-1"}
+	adapter := &Adapter{config: config, session: &mockSession{}}
+
+	if adapter.channelAllowed("ch-1") {
+		t.Fatal("Expected the channel to be blocked")
+	}
+
+	// The lists are read once as Config.BlockedUsers is.
+	config.BlockedChannels = nil
+	config.AllowedChannels = []string{"ch-2"}
+	if adapter.channelAllowed("ch-1") || !adapter.channelAllowed("ch-3") {
+		t.Error("Expected later changes to the lists not to be reflected")
+	}
+	if adapter.userBlocked("user-1") {
+		t.Fatal("Expected the user not to be blocked")
+	}
+	config.BlockedUsers = []string{"user-1"}
+	if adapter.userBlocked("user-1") {
+		t.Error("Expected later changes to the list not to be reflected")
+	}
+}
@@ -0,0 +1,119 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// ChannelKind represents the kind of channel where a message was sent.
+// Commands can switch on this to behave appropriately for the context.
+type ChannelKind int
+
+const (
+	// ChannelKindUnknown indicates that the kind of channel could not be determined.
+	ChannelKindUnknown ChannelKind = iota
+
+	// ChannelKindGuildText indicates a text channel in a guild.
+	ChannelKindGuildText
+
+	// ChannelKindAnnouncement indicates an announcement channel in a guild.
+	ChannelKindAnnouncement
+
+	// ChannelKindThread indicates a public, private, or announcement thread.
+	ChannelKindThread
+
+	// ChannelKindVoiceText indicates the text chat of a voice or stage channel.
+	ChannelKindVoiceText
+
+	// ChannelKindDM indicates a direct message between the bot and a user.
+	ChannelKindDM
+
+	// ChannelKindGroupDM indicates a direct message among multiple users.
+	ChannelKindGroupDM
+)
+
+// String returns the name of the ChannelKind.
+func (k ChannelKind) String() string {
+	switch k {
+	case ChannelKindGuildText:
+		return "GuildText"
+
+	case ChannelKindAnnouncement:
+		return "Announcement"
+
+	case ChannelKindThread:
+		return "Thread"
+
+	case ChannelKindVoiceText:
+		return "VoiceText"
+
+	case ChannelKindDM:
+		return "DM"
+
+	case ChannelKindGroupDM:
+		return "GroupDM"
+
+	default:
+		return "Unknown"
+	}
+}
+
+// IsGuild tells if the kind of channel belongs to a guild.
+func (k ChannelKind) IsGuild() bool {
+	return k == ChannelKindGuildText || k == ChannelKindAnnouncement || k == ChannelKindThread || k == ChannelKindVoiceText
+}
+
+// channelKindOf maps the given discordgo.ChannelType to ChannelKind.
+func channelKindOf(channelType discordgo.ChannelType) ChannelKind {
+	switch channelType {
+	case discordgo.ChannelTypeGuildText:
+		return ChannelKindGuildText
+
+	case discordgo.ChannelTypeGuildNews:
+		return ChannelKindAnnouncement
+
+	case discordgo.ChannelTypeGuildPublicThread, discordgo.ChannelTypeGuildPrivateThread, discordgo.ChannelTypeGuildNewsThread:
+		return ChannelKindThread
+
+	case discordgo.ChannelTypeGuildVoice, discordgo.ChannelTypeGuildStageVoice:
+		return ChannelKindVoiceText
+
+	case discordgo.ChannelTypeDM:
+		return ChannelKindDM
+
+	case discordgo.ChannelTypeGroupDM:
+		return ChannelKindGroupDM
+
+	default:
+		return ChannelKindUnknown
+	}
+}
+
+// ChannelKind returns the kind of channel where the message was sent.
+//
+// The channel is looked up from the cached state or, if not cached, fetched via Discord's REST API.
+// The result is cached in the Input, so the lookup happens only once.
+// When the lookup fails, the kind is estimated from the event:
+// ChannelKindDM for a message without a guild ID and ChannelKindUnknown otherwise.
+// Such an estimation is not cached, so the lookup is tried again on the next call.
+func (i *Input) ChannelKind() ChannelKind {
+	i.channelKindMutex.Lock()
+	defer i.channelKindMutex.Unlock()
+
+	if i.channelKind != ChannelKindUnknown {
+		return i.channelKind
+	}
+
+	if i.adapter != nil {
+		channel, err := i.adapter.channel(string(i.channelID))
+		if err == nil {
+			i.channelKind = channelKindOf(channel.Type)
+			return i.channelKind
+		}
+		i.adapter.log().Warnf("[%s] Failed to fetch channel %s: %+v", i.correlationID, i.channelID, err)
+	}
+
+	if i.Event != nil && i.Event.GuildID == "" {
+		return ChannelKindDM
+	}
+	return ChannelKindUnknown
+}
@@ -0,0 +1,115 @@
+package discord
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestChannelKindOf(t *testing.T) {
+	tests := []struct {
+		channelType discordgo.ChannelType
+		expected    ChannelKind
+	}{
+		{channelType: discordgo.ChannelTypeGuildText, expected: ChannelKindGuildText},
+		{channelType: discordgo.ChannelTypeGuildNews, expected: ChannelKindAnnouncement},
+		{channelType: discordgo.ChannelTypeGuildPublicThread, expected: ChannelKindThread},
+		{channelType: discordgo.ChannelTypeGuildPrivateThread, expected: ChannelKindThread},
+		{channelType: discordgo.ChannelTypeGuildNewsThread, expected: ChannelKindThread},
+		{channelType: discordgo.ChannelTypeGuildVoice, expected: ChannelKindVoiceText},
+		{channelType: discordgo.ChannelTypeGuildStageVoice, expected: ChannelKindVoiceText},
+		{channelType: discordgo.ChannelTypeDM, expected: ChannelKindDM},
+		{channelType: discordgo.ChannelTypeGroupDM, expected: ChannelKindGroupDM},
+		{channelType: discordgo.ChannelTypeGuildCategory, expected: ChannelKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected.String(), func(t *testing.T) {
+			if got := channelKindOf(tt.channelType); got != tt.expected {
+				t.Errorf("Expected %s for type %d, got %s", tt.expected, tt.channelType, got)
+			}
+		})
+	}
+}
+
+func TestChannelKind_IsGuild(t *testing.T) {
+	if !ChannelKindThread.IsGuild() {
+		t.Error("Expected a thread to belong to a guild")
+	}
+	if ChannelKindDM.IsGuild() || ChannelKindUnknown.IsGuild() {
+		t.Error("Expected DM and unknown not to belong to a guild")
+	}
+}
+
+func TestInput_ChannelKind(t *testing.T) {
+	t.Run("resolved from state", func(t *testing.T) {
+		s := &discordgo.Session{State: discordgo.NewState()}
+		_ = s.State.GuildAdd(&discordgo.Guild{ID: "guild-1"})
+		_ = s.State.ChannelAdd(&discordgo.Channel{ID: "ch-1", GuildID: "guild-1", Type: discordgo.ChannelTypeGuildPublicThread})
+		adapter := &Adapter{config: NewConfig(), session: s}
+		input := &Input{channelID: "ch-1", adapter: adapter}
+
+		if kind := input.ChannelKind(); kind != ChannelKindThread {
+			t.Errorf("Expected %s, got %s", ChannelKindThread, kind)
+		}
+	})
+
+	t.Run("resolved via API once", func(t *testing.T) {
+		var calls int
+		mock := &mockSession{
+			channelFunc: func(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				calls++
+				return &discordgo.Channel{ID: channelID, Type: discordgo.ChannelTypeGuildVoice}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		input := &Input{channelID: "ch-1", adapter: adapter}
+
+		input.ChannelKind()
+		kind := input.ChannelKind()
+
+		if kind != ChannelKindVoiceText {
+			t.Errorf("Expected %s, got %s", ChannelKindVoiceText, kind)
+		}
+		if calls != 1 {
+			t.Errorf("Expected 1 lookup, got %d", calls)
+		}
+	})
+
+	t.Run("failed lookup is estimated and retried", func(t *testing.T) {
+		var calls int
+		mock := &mockSession{
+			channelFunc: func(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				calls++
+				return nil, errors.New("unavailable")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		input := &Input{
+			Event:     &discordgo.MessageCreate{Message: &discordgo.Message{GuildID: "guild-1"}},
+			channelID: "ch-1",
+			adapter:   adapter,
+		}
+
+		input.ChannelKind()
+		kind := input.ChannelKind()
+
+		if kind != ChannelKindUnknown {
+			t.Errorf("Expected %s, got %s", ChannelKindUnknown, kind)
+		}
+		if calls != 2 {
+			t.Errorf("Expected 2 lookups, got %d", calls)
+		}
+	})
+
+	t.Run("estimated without adapter", func(t *testing.T) {
+		input, _ := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{ChannelID: "dm-1", Author: &discordgo.User{ID: "user-1"}},
+		})
+
+		if kind := input.ChannelKind(); kind != ChannelKindDM {
+			t.Errorf("Expected %s, got %s", ChannelKindDM, kind)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package discord
+
+import "sync"
+
+// maxPendingPerChannel is the maximum number of messages waiting for the preceding messages to the same channel to be sent
+// with Config.SerializePerChannel.
+// Further messages are dropped so a flooding command cannot grow the queue without bound.
+const maxPendingPerChannel = 32
+
+// channelQueues sends messages to the same channel one by one in the order they were queued,
+// while messages to different channels are sent concurrently.
+// Each channel's queue is drained by a dedicated goroutine, so the caller neither waits for its own message
+// nor delivers the messages of other callers.
+// A worker exists only while its channel has pending messages, so idle channels do not consume memory or goroutines.
+// The zero value is ready to use.
+type channelQueues struct {
+	mutex  sync.Mutex
+	queues map[string][]func() // Channel key to pending sends; a key is present while its worker is running
+}
+
+// enqueue queues the send for the channel and starts the channel's worker unless it is already running.
+// false is returned when the send is dropped because the channel has too many pending sends.
+func (q *channelQueues) enqueue(key string, send func()) bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.queues == nil {
+		q.queues = map[string][]func(){}
+	}
+
+	if pending, running := q.queues[key]; running {
+		if len(pending) >= maxPendingPerChannel {
+			return false
+		}
+		q.queues[key] = append(pending, send)
+		return true
+	}
+
+	q.queues[key] = nil
+	go q.work(key, send)
+	return true
+}
+
+// work runs the given send and then the channel's pending sends until none is left.
+func (q *channelQueues) work(key string, send func()) {
+	for send != nil {
+		send()
+
+		q.mutex.Lock()
+		pending := q.queues[key]
+		if len(pending) == 0 {
+			delete(q.queues, key)
+			send = nil
+		} else {
+			send = pending[0]
+			q.queues[key] = pending[1:]
+		}
+		q.mutex.Unlock()
+	}
+}
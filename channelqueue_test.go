@@ -0,0 +1,126 @@
+package discord
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_SendMessage_SerializePerChannel(t *testing.T) {
+	newBlockingAdapter := func() (*Adapter, chan struct{}, chan struct{}, func() []string) {
+		entered := make(chan struct{})
+		release := make(chan struct{})
+		var mutex sync.Mutex
+		var sent []string
+		mock := &mockSession{
+			channelMessageSendFunc: func(_ string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				if content == "0" {
+					close(entered)
+					<-release
+				}
+				mutex.Lock()
+				defer mutex.Unlock()
+				sent = append(sent, content)
+				return &discordgo.Message{}, nil
+			},
+		}
+		config := NewConfig()
+		config.SerializePerChannel = true
+		adapter := &Adapter{config: config, session: mock, logger: &capturingLogger{}}
+
+		return adapter, entered, release, func() []string {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return slices.Clone(sent)
+		}
+	}
+
+	// waitSends waits for the workers to send every queued message.
+	waitSends := func(t *testing.T, adapter *Adapter) {
+		select {
+		case <-adapter.sends.close():
+		case <-time.After(time.Second):
+			t.Fatal("Expected the queued messages to be sent")
+		}
+	}
+
+	t.Run("messages to a channel are sent in the submitted order", func(t *testing.T) {
+		adapter, entered, release, sent := newBlockingAdapter()
+
+		// The caller returns without waiting for the worker to send the message.
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "0"))
+		<-entered
+
+		for i := 1; i < 10; i++ {
+			adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), strconv.Itoa(i)))
+		}
+
+		// A message to another channel is not blocked.
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-2"), "other"))
+		deadline := time.Now().Add(time.Second)
+		for !slices.Equal(sent(), []string{"other"}) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected only the message to the other channel to be sent, got %v", sent())
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		close(release)
+		waitSends(t, adapter)
+
+		expected := []string{"other", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}
+		if !slices.Equal(sent(), expected) {
+			t.Errorf("Expected %v, got %v", expected, sent())
+		}
+	})
+
+	t.Run("messages beyond the pending limit are dropped", func(t *testing.T) {
+		adapter, entered, release, sent := newBlockingAdapter()
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "0"))
+		<-entered
+
+		for i := 1; i <= maxPendingPerChannel+1; i++ {
+			adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), strconv.Itoa(i)))
+		}
+		close(release)
+		waitSends(t, adapter)
+
+		if len(sent()) != maxPendingPerChannel+1 {
+			t.Errorf("Expected %d messages to be sent, got %d", maxPendingPerChannel+1, len(sent()))
+		}
+		if adapter.Stats().Total.SendFailed != 1 {
+			t.Errorf("Expected the dropped message to be counted as failed: %+v", adapter.Stats().Total)
+		}
+		if !adapter.logger.(*capturingLogger).has("WARN", "too many messages are pending") {
+			t.Errorf("Expected a warning: %v", adapter.logger.(*capturingLogger).entries)
+		}
+	})
+
+	t.Run("each message is sent with its own context", func(t *testing.T) {
+		adapter, entered, release, sent := newBlockingAdapter()
+		adapter.config.SendRateLimit = 1000 // Lets the send observe the canceled context
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "0"))
+		<-entered
+
+		// The canceled context of the queued message does not affect the others.
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		adapter.SendMessage(ctx, sarah.NewOutputMessage(ChannelID("ch-1"), "canceled"))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "1"))
+
+		close(release)
+		waitSends(t, adapter)
+
+		if expected := []string{"0", "1"}; !slices.Equal(sent(), expected) {
+			t.Errorf("Expected %v, got %v", expected, sent())
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package discord
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// codeFence is the delimiter of a Markdown code block.
+const codeFence = "```"
+
+// chunkMessage splits the given text into chunks of at most limit characters.
+// Each split prefers a line break, then a space, as splitMessage does.
+// When a split falls inside a code block, the chunk closes the block and the next chunk reopens it with the same language,
+// so every chunk renders as intended.
+func chunkMessage(text string, limit int) []string {
+	if limit <= 0 || limit > MaxMessageLength {
+		limit = MaxMessageLength
+	}
+
+	var chunks []string
+	for utf8.RuneCountInString(text) > limit {
+		// Leave room to close a code block that the chunk may end in.
+		head, rest := splitMessage(text, limit-len("\n"+codeFence))
+
+		// The reopened block must be shorter than the chunk so the text keeps shrinking.
+		if opening, ok := openCodeFence(head); ok && len(head) > len(opening)+1 {
+			head += "\n" + codeFence
+			rest = opening + "\n" + rest
+		}
+
+		chunks = append(chunks, head)
+		text = rest
+	}
+
+	if text != "" || len(chunks) == 0 {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+// splitContent splits the given message into messages whose content has at most limit characters each, as chunkMessage does.
+// The first message keeps everything else such as the reference, embeds, files, and components,
+// while the following messages only carry the rest of the content.
+// The given message is returned as it is when its content does not exceed the limit.
+func splitContent(data *discordgo.MessageSend, limit int) []*discordgo.MessageSend {
+	chunks := chunkMessage(data.Content, limit)
+	if len(chunks) <= 1 {
+		return []*discordgo.MessageSend{data}
+	}
+
+	first := *data
+	first.Content = chunks[0]
+	messages := []*discordgo.MessageSend{&first}
+
+	for _, chunk := range chunks[1:] {
+		messages = append(messages, &discordgo.MessageSend{
+			Content:         chunk,
+			TTS:             data.TTS,
+			AllowedMentions: data.AllowedMentions,
+		})
+	}
+
+	return messages
+}
+
+// openCodeFence returns the line that opens a code block when the given text ends inside the block.
+func openCodeFence(text string) (string, bool) {
+	if strings.Count(text, codeFence)%2 == 0 {
+		return "", false
+	}
+
+	opening := text[strings.LastIndex(text, codeFence):]
+	if i := strings.Index(opening, "\n"); i >= 0 {
+		opening = opening[:i]
+	}
+	return opening, true
+}
+
+// sendText sends the given text, splitting it into multiple messages when it exceeds Config.MaxMessageLength.
+// The sending stops at the first failure. The given options are applied after the defaults.
+func (a *Adapter) sendText(ctx context.Context, channelID string, text string, options ...discordgo.RequestOption) error {
+	for _, chunk := range chunkMessage(text, a.config.MaxMessageLength) {
+		if _, err := a.sendChunk(ctx, channelID, chunk, options...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendChunk sends the given text that fits in a single message, and returns the sent message.
+// The given options are applied after the defaults.
+func (a *Adapter) sendChunk(ctx context.Context, channelID string, chunk string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	options = append([]discordgo.RequestOption{discordgo.WithContext(ctx)}, options...)
+
+	var message *discordgo.Message
+	err := a.retrySend(ctx, func() error {
+		var err error
+		if a.config.DefaultAllowedMentions != nil {
+			data := &discordgo.MessageSend{Content: chunk, AllowedMentions: a.config.DefaultAllowedMentions}
+			message, err = a.session.ChannelMessageSendComplex(channelID, data, a.requestOptions(options...)...)
+		} else {
+			message, err = a.session.ChannelMessageSend(channelID, chunk, a.requestOptions(options...)...)
+		}
+		return err
+	})
+	return message, err
+}
+
+// sendComplex sends the given message with the same retry and Config.DefaultAllowedMentions as sendText.
+// The given options are applied after the defaults.
+func (a *Adapter) sendComplex(ctx context.Context, channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) error {
+	if data.AllowedMentions == nil && a.config.DefaultAllowedMentions != nil {
+		data = withAllowedMentions(data, a.config.DefaultAllowedMentions).(*discordgo.MessageSend)
+	}
+
+	options = append([]discordgo.RequestOption{discordgo.WithContext(ctx)}, options...)
+	return a.retrySend(ctx, func() error {
+		_, err := a.session.ChannelMessageSendComplex(channelID, data, a.requestOptions(options...)...)
+		return err
+	})
+}
@@ -0,0 +1,140 @@
+package discord
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestChunkMessage(t *testing.T) {
+	t.Run("short text", func(t *testing.T) {
+		chunks := chunkMessage("hello", 2000)
+		if len(chunks) != 1 || chunks[0] != "hello" {
+			t.Errorf("Unexpected chunks: %#v", chunks)
+		}
+	})
+
+	t.Run("split on line boundaries", func(t *testing.T) {
+		line := strings.Repeat("a", 99)
+		text := strings.TrimSuffix(strings.Repeat(line+"\n", 50), "\n")
+
+		chunks := chunkMessage(text, 2000)
+
+		if len(chunks) != 3 {
+			t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+		}
+		for i, chunk := range chunks {
+			if utf8.RuneCountInString(chunk) > 2000 {
+				t.Errorf("Chunk %d is too long: %d", i, utf8.RuneCountInString(chunk))
+			}
+			for _, l := range strings.Split(chunk, "\n") {
+				if l != line {
+					t.Errorf("Line is cut in chunk %d: %q", i, l)
+				}
+			}
+		}
+	})
+
+	t.Run("code block is closed and reopened", func(t *testing.T) {
+		text := "Logs:\n```go\n" + strings.Repeat("fmt.Println()\n", 20) + "```\nDone."
+
+		chunks := chunkMessage(text, 100)
+
+		if len(chunks) < 2 {
+			t.Fatalf("Expected multiple chunks, got %#v", chunks)
+		}
+		for i, chunk := range chunks {
+			if utf8.RuneCountInString(chunk) > 100 {
+				t.Errorf("Chunk %d is too long: %q", i, chunk)
+			}
+			if strings.Count(chunk, "```")%2 != 0 {
+				t.Errorf("Chunk %d has an unclosed code block: %q", i, chunk)
+			}
+		}
+		if !strings.HasPrefix(chunks[1], "```go\n") {
+			t.Errorf("Expected the code block to be reopened with the language: %q", chunks[1])
+		}
+	})
+
+	t.Run("long fence line without breaks", func(t *testing.T) {
+		text := "```go\n" + strings.Repeat("a", 300)
+
+		chunks := chunkMessage(text, 100)
+
+		if strings.Join(chunks, "") == "" {
+			t.Fatal("Expected chunks")
+		}
+		for i, chunk := range chunks {
+			if utf8.RuneCountInString(chunk) > 100 {
+				t.Errorf("Chunk %d is too long: %q", i, chunk)
+			}
+		}
+	})
+}
+
+func TestAdapter_SendMessage_LongText(t *testing.T) {
+	var sent []string
+	mock := &mockSession{
+		channelMessageSendFunc: func(_ string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, content)
+			return &discordgo.Message{}, nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+
+	text := strings.Repeat(strings.Repeat("a", 99)+"\n", 50)
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), text))
+
+	if len(sent) != 3 {
+		t.Errorf("Expected 3 sends, got %d", len(sent))
+	}
+	if adapter.Stats().Total.SendSucceeded != 1 {
+		t.Errorf("Expected one response to be recorded, got %#v", adapter.Stats().Total)
+	}
+}
+
+func TestAdapter_SendMessage_LongReply(t *testing.T) {
+	var sent []*discordgo.MessageSend
+	mock := &mockSession{
+		channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			if utf8.RuneCountInString(data.Content) > MaxMessageLength {
+				t.Errorf("Content exceeds the limit: %d", utf8.RuneCountInString(data.Content))
+			}
+			sent = append(sent, data)
+			return &discordgo.Message{}, nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+
+	input := newReplyInput("guild-1")
+	text := strings.Repeat(strings.Repeat("a", 99)+"\n", 50)
+	button := discordgo.Button{Label: "OK", CustomID: "ok"}
+	response, _ := NewResponse(input, text, RespAsReply(), RespWithComponents(discordgo.ActionsRow{Components: []discordgo.MessageComponent{button}}))
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+	if len(sent) != 3 {
+		t.Fatalf("Expected 3 sends, got %d", len(sent))
+	}
+	if sent[0].Reference == nil || len(sent[0].Components) != 1 {
+		t.Errorf("Expected the first chunk to keep the reference and the components: %#v", sent[0])
+	}
+	for _, data := range sent[1:] {
+		if data.Reference != nil || len(data.Components) != 0 {
+			t.Errorf("Expected the following chunks to only carry the content: %#v", data)
+		}
+	}
+	var joined string
+	for _, data := range sent {
+		joined += data.Content
+	}
+	if strings.ReplaceAll(joined, "\n", "") != strings.ReplaceAll(text, "\n", "") {
+		t.Error("Expected the whole content to be sent")
+	}
+	if adapter.Stats().Total.SendSucceeded != 1 {
+		t.Errorf("Expected one response to be recorded, got %#v", adapter.Stats().Total)
+	}
+}
@@ -0,0 +1,113 @@
+package discord
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+const (
+	// maxCommandNameLength is the maximum number of characters in an application command name.
+	maxCommandNameLength = 32
+
+	// maxCommandDescriptionLength is the maximum number of characters in an application command description.
+	maxCommandDescriptionLength = 100
+)
+
+// BuildApplicationCommands converts the given CommandHelps to slash command definitions.
+// This helps a bot that has text commands to register the corresponding slash commands,
+// e.g., with discordgo.Session.ApplicationCommandBulkOverwrite.
+//
+// Each identifier is sanitized to the characters Discord allows in a command name: it is lowercased,
+// disallowed characters are replaced with hyphens, and the result is truncated to 32 characters.
+// A suffix is appended when names collide after the sanitization, and an entry with no usable character is skipped.
+// Each instruction becomes the description with its whitespace collapsed, truncated to 100 characters.
+func BuildApplicationCommands(helps *sarah.CommandHelps) []*discordgo.ApplicationCommand {
+	if helps == nil {
+		return nil
+	}
+
+	commands := make([]*discordgo.ApplicationCommand, 0, len(*helps))
+	used := map[string]bool{}
+	for _, help := range *helps {
+		name := sanitizeCommandName(help.Identifier)
+		if name == "" {
+			continue
+		}
+		name = uniqueCommandName(name, used)
+		used[name] = true
+
+		description := commandDescription(help.Instruction)
+		if description == "" {
+			// Discord requires a non-empty description.
+			description = name
+		}
+
+		commands = append(commands, &discordgo.ApplicationCommand{
+			Type:        discordgo.ChatApplicationCommand,
+			Name:        name,
+			Description: description,
+		})
+	}
+
+	return commands
+}
+
+// sanitizeCommandName converts the given identifier to a valid slash command name.
+// Letters and digits are kept in lowercase, hyphens and underscores are kept, and other characters are replaced with a hyphen.
+func sanitizeCommandName(identifier string) string {
+	var builder strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(identifier) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r) || r == '_':
+			builder.WriteRune(r)
+			hyphen = false
+
+		case !hyphen && builder.Len() > 0:
+			// A run of disallowed characters including "-" becomes a single hyphen.
+			builder.WriteRune('-')
+			hyphen = true
+		}
+	}
+
+	name := strings.TrimRight(builder.String(), "-")
+	return strings.TrimRight(truncateRunes(name, maxCommandNameLength), "-")
+}
+
+// uniqueCommandName appends a numeric suffix to the name when it is already used.
+func uniqueCommandName(name string, used map[string]bool) string {
+	if !used[name] {
+		return name
+	}
+
+	for i := 2; ; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		candidate := truncateRunes(name, maxCommandNameLength-len(suffix)) + suffix
+		if !used[candidate] {
+			return candidate
+		}
+	}
+}
+
+// commandDescription converts the given instruction to a slash command description.
+func commandDescription(instruction string) string {
+	description := strings.Join(strings.Fields(instruction), " ")
+	if utf8.RuneCountInString(description) <= maxCommandDescriptionLength {
+		return description
+	}
+	return strings.TrimSpace(truncateRunes(description, maxCommandDescriptionLength-1)) + "…"
+}
+
+// truncateRunes truncates the given string to the given number of runes.
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,107 @@
+package discord
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestBuildApplicationCommands(t *testing.T) {
+	t.Run("nil helps", func(t *testing.T) {
+		if commands := BuildApplicationCommands(nil); commands != nil {
+			t.Errorf("Expected nil, got %#v", commands)
+		}
+	})
+
+	t.Run("helps are converted in order", func(t *testing.T) {
+		helps := &sarah.CommandHelps{
+			{Identifier: "echo", Instruction: "Input .echo foo to get foo"},
+			{Identifier: ".Weather Forecast", Instruction: "Tells\nthe   weather"},
+			{Identifier: "!!!", Instruction: "No usable character"},
+			{Identifier: "empty", Instruction: ""},
+		}
+
+		commands := BuildApplicationCommands(helps)
+
+		if len(commands) != 3 {
+			t.Fatalf("Expected 3 commands, got %d", len(commands))
+		}
+
+		expected := []struct {
+			name        string
+			description string
+		}{
+			{name: "echo", description: "Input .echo foo to get foo"},
+			{name: "weather-forecast", description: "Tells the weather"},
+			{name: "empty", description: "empty"},
+		}
+		for i, e := range expected {
+			if commands[i].Type != discordgo.ChatApplicationCommand {
+				t.Errorf("Expected chat input command, got %d", commands[i].Type)
+			}
+			if commands[i].Name != e.name {
+				t.Errorf("Expected name %q, got %q", e.name, commands[i].Name)
+			}
+			if commands[i].Description != e.description {
+				t.Errorf("Expected description %q, got %q", e.description, commands[i].Description)
+			}
+		}
+	})
+
+	t.Run("colliding names get suffixes", func(t *testing.T) {
+		helps := &sarah.CommandHelps{
+			{Identifier: "hello world", Instruction: "a"},
+			{Identifier: "hello_world", Instruction: "b"},
+			{Identifier: "Hello-World", Instruction: "c"},
+		}
+
+		commands := BuildApplicationCommands(helps)
+
+		names := []string{commands[0].Name, commands[1].Name, commands[2].Name}
+		if names[0] != "hello-world" || names[1] != "hello_world" || names[2] != "hello-world-2" {
+			t.Errorf("Unexpected names: %v", names)
+		}
+	})
+
+	t.Run("long description is truncated", func(t *testing.T) {
+		instruction := strings.Repeat("あ", 150)
+		commands := BuildApplicationCommands(&sarah.CommandHelps{{Identifier: "long", Instruction: instruction}})
+
+		description := commands[0].Description
+		if utf8.RuneCountInString(description) != maxCommandDescriptionLength {
+			t.Errorf("Expected %d characters, got %d", maxCommandDescriptionLength, utf8.RuneCountInString(description))
+		}
+		if !strings.HasSuffix(description, "…") {
+			t.Errorf("Expected an ellipsis, got %q", description)
+		}
+	})
+}
+
+func TestSanitizeCommandName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "echo", expected: "echo"},
+		{input: "ECHO", expected: "echo"},
+		{input: ".help", expected: "help"},
+		{input: "--a--b--", expected: "a-b"},
+		{input: "snake_case", expected: "snake_case"},
+		{input: "天気", expected: "天気"},
+		{input: "a b c!", expected: "a-b-c"},
+		{input: "🎉", expected: ""},
+		{input: strings.Repeat("a", 40), expected: strings.Repeat("a", 32)},
+		{input: strings.Repeat("a", 31) + " b", expected: strings.Repeat("a", 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := sanitizeCommandName(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// RespWithComponents attaches the given message components, e.g., action rows holding buttons, to the response.
+// A click on a button is passed to go-sarah as *InteractionInput whose message is the button's custom ID,
+// so a command matching the custom ID handles the click.
+//
+//	discord.RespWithComponents(discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+//		discordgo.Button{Label: "Yes", Style: discordgo.PrimaryButton, CustomID: "vote yes"},
+//	}})
+func RespWithComponents(components ...discordgo.MessageComponent) RespOption {
+	return func(options *respOptions) {
+		options.components = append(options.components, components...)
+	}
+}
+
+// withComponents converts the given response content to a *discordgo.MessageSend with the given components.
+// The given *discordgo.MessageSend is copied so the caller's value is not modified.
+func withComponents(content any, components []discordgo.MessageComponent) any {
+	switch typed := content.(type) {
+	case string:
+		return &discordgo.MessageSend{
+			Content:    typed,
+			Components: components,
+		}
+
+	case *discordgo.MessageSend:
+		copied := *typed
+		copied.Components = append(append([]discordgo.MessageComponent{}, typed.Components...), components...)
+		return &copied
+
+	default:
+		return content
+	}
+}
@@ -0,0 +1,164 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newButtonRow(customIDs ...string) discordgo.ActionsRow {
+	row := discordgo.ActionsRow{}
+	for _, id := range customIDs {
+		row.Components = append(row.Components, discordgo.Button{Label: id, Style: discordgo.PrimaryButton, CustomID: id})
+	}
+	return row
+}
+
+func newButtonClick(customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:        "interaction-1",
+			Type:      discordgo.InteractionMessageComponent,
+			ChannelID: "ch-1",
+			Member:    &discordgo.Member{User: &discordgo.User{ID: "user-1"}},
+			Data: discordgo.MessageComponentInteractionData{
+				CustomID:      customID,
+				ComponentType: discordgo.ButtonComponent,
+			},
+		},
+	}
+}
+
+func TestRespWithComponents(t *testing.T) {
+	tests := []struct {
+		name    string
+		content func() *sarah.CommandResponse
+		want    int
+	}{
+		{
+			name: "string content",
+			content: func() *sarah.CommandResponse {
+				response, _ := NewResponse(newReplyInput("guild-1"), "vote", RespWithComponents(newButtonRow("vote yes", "vote no")))
+				return response
+			},
+			want: 1,
+		},
+		{
+			name: "components are appended to the existing ones",
+			content: func() *sarah.CommandResponse {
+				data := &discordgo.MessageSend{Content: "vote", Components: []discordgo.MessageComponent{newButtonRow("vote maybe")}}
+				response, _ := NewResponse(newReplyInput("guild-1"), data, RespWithComponents(newButtonRow("vote yes", "vote no")))
+				if len(data.Components) != 1 {
+					t.Error("The given message should not be modified")
+				}
+				return response
+			},
+			want: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent *discordgo.MessageSend
+			mock := &mockSession{
+				channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+					sent = data
+					return &discordgo.Message{}, nil
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			response := tt.content()
+			adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), response.Content))
+
+			if sent == nil || sent.Content != "vote" || len(sent.Components) != tt.want {
+				t.Fatalf("Unexpected message: %#v", sent)
+			}
+			last := sent.Components[len(sent.Components)-1].(discordgo.ActionsRow)
+			if len(last.Components) != 2 || last.Components[0].(discordgo.Button).CustomID != "vote yes" {
+				t.Errorf("Unexpected row: %#v", last)
+			}
+		})
+	}
+}
+
+func TestAdapter_handleInteraction_Button(t *testing.T) {
+	adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+	var inputs []sarah.Input
+	enqueue := func(input sarah.Input) error {
+		inputs = append(inputs, input)
+		return nil
+	}
+
+	adapter.handleInteraction(context.Background(), newButtonClick(helpMenuCustomIDPrefix+"1:0"), enqueue)
+	adapter.handleInteraction(context.Background(), newButtonClick("vote yes"), enqueue)
+
+	if len(inputs) != 1 {
+		t.Fatalf("Expected only the button click to be enqueued: %#v", inputs)
+	}
+	input := inputs[0].(*InteractionInput)
+	if input.Message() != "vote yes" || input.CustomID() != "vote yes" || input.CommandName() != "" {
+		t.Errorf("Unexpected input: %#v", input)
+	}
+	if input.SenderKey() != senderKeyOf("ch-1", "user-1") {
+		t.Errorf("Unexpected sender key: %s", input.SenderKey())
+	}
+}
+
+func TestInteractionToInput_SenderKeyMatchesMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *discordgo.Message
+		click   func() *discordgo.InteractionCreate
+	}{
+		{
+			name:    "guild",
+			message: &discordgo.Message{ChannelID: "ch-1", GuildID: "guild-1", Author: &discordgo.User{ID: "user-1"}},
+			click:   func() *discordgo.InteractionCreate { return newButtonClick("next") },
+		},
+		{
+			name:    "direct message",
+			message: &discordgo.Message{ChannelID: "dm-1", Author: &discordgo.User{ID: "user-1"}},
+			click: func() *discordgo.InteractionCreate {
+				i := newButtonClick("next")
+				i.ChannelID = "dm-1"
+				i.Member = nil
+				i.User = &discordgo.User{ID: "user-1"}
+				return i
+			},
+		},
+		{
+			name:    "channel only on the message",
+			message: &discordgo.Message{ChannelID: "ch-1", GuildID: "guild-1", Author: &discordgo.User{ID: "user-1"}},
+			click: func() *discordgo.InteractionCreate {
+				i := newButtonClick("next")
+				i.ChannelID = ""
+				i.Message = &discordgo.Message{ID: "msg-1", ChannelID: "ch-1"}
+				return i
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messageInput, err := MessageToInput(&discordgo.MessageCreate{Message: tt.message})
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			clickInput, err := InteractionToInput(tt.click())
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			if clickInput.SenderKey() != messageInput.SenderKey() {
+				t.Errorf("Expected the sender key %s to match the message's, got %s", messageInput.SenderKey(), clickInput.SenderKey())
+			}
+			if clickInput.SenderKey() != senderKeyOf(tt.message.ChannelID, "user-1") {
+				t.Errorf("Unexpected sender key format: %s", clickInput.SenderKey())
+			}
+		})
+	}
+}
@@ -1,6 +1,16 @@
 package discord
 
-import "github.com/bwmarrin/discordgo"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
 
 // Config contains configuration variables for the Discord Adapter.
 type Config struct {
@@ -9,23 +19,519 @@ type Config struct {
 
 	// HelpCommand is the command string that triggers help.
 	// When a user sends this exact string, the input is converted to sarah.HelpInput.
+	// When CommandPrefix is set, the string must follow the prefix or the bot's mention in a guild,
+	// so set "help" to trigger help with "!help" for the prefix "!".
 	HelpCommand string `json:"help_command" yaml:"help_command"`
 
 	// AbortCommand is the command string that triggers context cancellation.
 	// When a user sends this exact string, the input is converted to sarah.AbortInput.
+	// This follows the prefix in the same way as HelpCommand.
 	AbortCommand string `json:"abort_command" yaml:"abort_command"`
 
 	// Intents declares the Gateway Intents the bot requires.
 	Intents discordgo.Intent `json:"intents" yaml:"intents"`
+
+	// StreamEditInterval is the minimum interval between edits made by StreamWriter.
+	// Keeping this reasonably long prevents streamed responses from hitting Discord's rate limits.
+	StreamEditInterval time.Duration `json:"stream_edit_interval" yaml:"stream_edit_interval"`
+
+	// EnableGuildToggle enables the built-in commands that let server managers enable or disable the bot per guild.
+	// See GuildEnabledStore.
+	EnableGuildToggle bool `json:"enable_guild_toggle" yaml:"enable_guild_toggle"`
+
+	// GuildEnableCommand is the command string that enables the bot in the guild.
+	// This is only effective when EnableGuildToggle is true.
+	GuildEnableCommand string `json:"guild_enable_command" yaml:"guild_enable_command"`
+
+	// GuildDisableCommand is the command string that disables the bot in the guild.
+	// This is only effective when EnableGuildToggle is true.
+	GuildDisableCommand string `json:"guild_disable_command" yaml:"guild_disable_command"`
+
+	// CommandPrefix is the prefix that invokes the bot, such as "!".
+	// When a message starts with this prefix, the prefix is stripped before the message is passed to go-sarah.
+	// A message starting with the bot's mention is also treated as an invocation, and the mention is stripped.
+	CommandPrefix string `json:"command_prefix" yaml:"command_prefix"`
+
+	// RequireInvocation drops guild messages that neither start with CommandPrefix nor mention the bot.
+	// Direct messages are always treated as invocations.
+	RequireInvocation bool `json:"require_invocation" yaml:"require_invocation"`
+
+	// ReactionInterval is the initial interval between reaction additions made by Adapter.AddReactions.
+	// The interval is widened when Discord still responds with a rate limit error.
+	ReactionInterval time.Duration `json:"reaction_interval" yaml:"reaction_interval"`
+
+	// SuppressDuplicateSends skips sending a message that is identical to one recently sent to the same channel.
+	// This prevents double posts when a command is triggered twice by a retried gateway event.
+	SuppressDuplicateSends bool `json:"suppress_duplicate_sends" yaml:"suppress_duplicate_sends"`
+
+	// DuplicateSendWindow is the duration in which an identical message is regarded as a duplicate.
+	DuplicateSendWindow time.Duration `json:"duplicate_send_window" yaml:"duplicate_send_window"`
+
+	// OpenRetryLimit is the number of times opening the session is retried on startup, and on reconnection unless MaxReconnectAttempts is set.
+	// Errors such as an invalid token are not retried.
+	OpenRetryLimit int `json:"open_retry_limit" yaml:"open_retry_limit"`
+
+	// OpenRetryInterval is the initial interval between retries to open the session.
+	// The interval is doubled on every retry up to one minute.
+	OpenRetryInterval time.Duration `json:"open_retry_interval" yaml:"open_retry_interval"`
+
+	// ReconnectOnInvalidSession lets the Adapter reopen the session when the gateway connection is dropped,
+	// instead of relying on discordgo's own reconnection.
+	// The reopened session is resumed, and discordgo re-identifies when the gateway rejects the resume as an invalid session.
+	// The reopening is retried as configured by MaxReconnectAttempts and OpenRetryInterval.
+	ReconnectOnInvalidSession bool `json:"reconnect_on_invalid_session" yaml:"reconnect_on_invalid_session"`
+
+	// DefaultRequestOptions are applied to every REST API call the Adapter makes,
+	// e.g., to set tracing headers or to disable discordgo's retry on rate limits.
+	// Options specific to each call are applied after these.
+	DefaultRequestOptions []discordgo.RequestOption `json:"-" yaml:"-"`
+
+	// ThreadAutoArchiveDuration is the duration in minutes after which a thread started by Adapter.StartConversationThread
+	// is archived by Discord due to inactivity. Discord accepts 60, 1440, 4320, and 10080. Zero uses the channel's default.
+	ThreadAutoArchiveDuration int `json:"thread_auto_archive_duration" yaml:"thread_auto_archive_duration"`
+
+	// ArchiveThreadOnCompletion archives a thread started by Adapter.StartConversationThread when its conversation completes.
+	ArchiveThreadOnCompletion bool `json:"archive_thread_on_completion" yaml:"archive_thread_on_completion"`
+
+	// ThreadClosingMessage is sent to the thread right before it is archived on completion.
+	// No message is sent when this is empty.
+	ThreadClosingMessage string `json:"thread_closing_message" yaml:"thread_closing_message"`
+
+	// MaxEmbedsPerMessage is the maximum number of embeds sent in a single message.
+	// When a *discordgo.MessageSend has more embeds, the rest are sent in the following messages.
+	// Values out of the range of 1 to 10, Discord's limit, are treated as 10.
+	MaxEmbedsPerMessage int `json:"max_embeds_per_message" yaml:"max_embeds_per_message"`
+
+	// ZombieTimeout is the duration after which a connection that receives neither events nor heartbeat acknowledgements
+	// is regarded as dead. The session is then closed and opened again. Zero disables the watchdog.
+	ZombieTimeout time.Duration `json:"zombie_timeout" yaml:"zombie_timeout"`
+
+	// HelpAsSelectMenu renders help as select menus of command identifiers instead of a list of instructions.
+	// Choosing a command in the menu replies with its instruction, visible only to the user who chose it.
+	HelpAsSelectMenu bool `json:"help_as_select_menu" yaml:"help_as_select_menu"`
+
+	// CorrelationIDFunc generates the correlation ID of each received message, which is available via Input.CorrelationID
+	// and is included in the logs for the input.
+	// Use this to propagate an ID from an external tracing system. A random ID is generated when this is nil or returns an empty string.
+	CorrelationIDFunc func(m *discordgo.MessageCreate) string `json:"-" yaml:"-"`
+
+	// OnGuildLeave is called with the guild ID when the bot is removed from a guild, e.g., kicked or the guild is deleted.
+	// Use this to clean up per-guild state. This is not called when a guild is only unavailable due to an outage.
+	OnGuildLeave func(guildID string) `json:"-" yaml:"-"`
+
+	// SerializePerSender handles messages from the same sender one by one in the order they were received,
+	// while messages from different senders are handled concurrently.
+	// This keeps conversational flows in order since discordgo otherwise handles each message in its own goroutine.
+	SerializePerSender bool `json:"serialize_per_sender" yaml:"serialize_per_sender"`
+
+	// MaxSenderQueues is the maximum number of senders whose messages are serialized at the same time.
+	// When exceeded, a new sender's message is handled right away without the ordering guarantee. Zero means no limit.
+	MaxSenderQueues int `json:"max_sender_queues" yaml:"max_sender_queues"`
+
+	// AutoResponses maps keyword patterns to the responses that the adapter sends directly when a message matches.
+	// A matched message is not passed to go-sarah. When multiple patterns match, the one that comes first in lexical order is used.
+	AutoResponses map[*regexp.Regexp]string `json:"-" yaml:"-"`
+
+	// AutoResponseCooldown is the duration in which the same auto response is not sent again to the same channel.
+	// A message matching a cooling-down pattern is passed to go-sarah as usual.
+	AutoResponseCooldown time.Duration `json:"auto_response_cooldown" yaml:"auto_response_cooldown"`
+
+	// AutoRespondToInvocations applies AutoResponses to messages that start with CommandPrefix or the bot's mention.
+	// By default, such messages are regarded as command invocations and passed to go-sarah.
+	AutoRespondToInvocations bool `json:"auto_respond_to_invocations" yaml:"auto_respond_to_invocations"`
+
+	// ResolveEmojiInContent replaces ":name:" in the text of responses with the custom emoji of the guild or the application.
+	// When a name is unknown, the response is not sent at all rather than rendered with the broken text.
+	ResolveEmojiInContent bool `json:"resolve_emoji_in_content" yaml:"resolve_emoji_in_content"`
+
+	// ShowTypingIndicator shows the typing indicator in the channel before passing an explicit invocation to go-sarah.
+	// Messages starting with CommandPrefix or the bot's mention and direct messages are regarded as explicit invocations.
+	ShowTypingIndicator bool `json:"show_typing_indicator" yaml:"show_typing_indicator"`
+
+	// IgnoreBots drops messages sent by other bots to prevent loops between bots.
+	// The bot's own messages are always dropped regardless of this setting.
+	IgnoreBots bool `json:"ignore_bots" yaml:"ignore_bots"`
+
+	// DefaultAllowedMentions controls which mentions in responses actually notify users unless a response specifies its own.
+	// Set &discordgo.MessageAllowedMentions{} to suppress accidental @everyone and role pings when echoing user content.
+	// When nil, Discord's default applies and every mention notifies.
+	DefaultAllowedMentions *discordgo.MessageAllowedMentions `json:"default_allowed_mentions" yaml:"default_allowed_mentions"`
+
+	// MaxReconnectAttempts is the maximum number of attempts to reopen the session after it is dropped
+	// when ReconnectOnInvalidSession is true or ZombieTimeout is positive.
+	// The attempts are spaced with exponential backoff starting from OpenRetryInterval, and the bot stops only when all of them fail.
+	// When zero, the attempts are limited by OpenRetryLimit as on startup.
+	MaxReconnectAttempts int `json:"max_reconnect_attempts" yaml:"max_reconnect_attempts"`
+
+	// SendTimeout bounds the time SendMessage spends on the API calls to send a response.
+	// The context given to SendMessage is applied regardless of this setting, and zero means no additional timeout.
+	SendTimeout time.Duration `json:"send_timeout" yaml:"send_timeout"`
+
+	// MaxMessageLength is the number of characters at which a text response is split into multiple messages.
+	// Values greater than MaxMessageLength, Discord's limit, are capped at the limit.
+	MaxMessageLength int `json:"max_message_length" yaml:"max_message_length"`
+
+	// HelpAsEmbed sends the help as embeds with a field per command instead of plain text.
+	// HelpAsSelectMenu takes precedence when both are set.
+	HelpAsEmbed bool `json:"help_as_embed" yaml:"help_as_embed"`
+
+	// AllowedChannels limits the channels where messages are handled.
+	// Empty means messages in every channel are handled.
+	// Changes after the Adapter starts handling messages are not reflected.
+	AllowedChannels []string `json:"allowed_channels" yaml:"allowed_channels"`
+
+	// BlockedChannels lists the channels where messages are ignored.
+	// This takes precedence over AllowedChannels.
+	// Changes after the Adapter starts handling messages are not reflected.
+	BlockedChannels []string `json:"blocked_channels" yaml:"blocked_channels"`
+
+	// SendRateLimit is the number of messages per second that the Adapter sends at most.
+	// Sends exceeding the rate wait for their turn instead of being dropped. Zero disables the limit.
+	// A message split into chunks or spilled over to multiple messages counts once per message actually sent, and so does a retry.
+	SendRateLimit float64 `json:"send_rate_limit" yaml:"send_rate_limit"`
+
+	// HandleEdits passes edited messages to go-sarah as *EditInput.
+	// This is disabled by default since most bots do not want an edit to run a command again.
+	HandleEdits bool `json:"handle_edits" yaml:"handle_edits"`
+
+	// HandleDeletes passes deleted messages to go-sarah as *DeleteInput, e.g., for audit logging.
+	HandleDeletes bool `json:"handle_deletes" yaml:"handle_deletes"`
+
+	// HandleReactions passes reactions added to messages to go-sarah as *ReactionInput, e.g., for reaction roles and polls.
+	// Intents must include IntentsGuildMessageReactions or IntentsDirectMessageReactions to receive reactions.
+	HandleReactions bool `json:"handle_reactions" yaml:"handle_reactions"`
+
+	// HandleMemberJoin passes members joining a guild to go-sarah as *MemberJoinInput, e.g., to greet them.
+	// Intents must include IntentsGuildMembers, which is a privileged intent to enable in the Developer Portal.
+	HandleMemberJoin bool `json:"handle_member_join" yaml:"handle_member_join"`
+
+	// CaseInsensitiveCommands lets HelpCommand and AbortCommand match regardless of case, e.g., ".HELP" for ".help".
+	CaseInsensitiveCommands bool `json:"case_insensitive_commands" yaml:"case_insensitive_commands"`
+
+	// ShutdownGracePeriod is the maximum duration to wait for in-flight sends to finish before the session is closed on shutdown.
+	// Zero closes the session right away.
+	ShutdownGracePeriod time.Duration `json:"shutdown_grace_period" yaml:"shutdown_grace_period"`
+
+	// Status is the bot's online status: "online", "idle", "dnd", or "invisible".
+	// Empty keeps Discord's default, which is online, unless Activity is set.
+	Status string `json:"status" yaml:"status"`
+
+	// Activity is shown as "Playing <Activity>" on the bot's profile, e.g., ".help" to tell users the command to start with.
+	Activity string `json:"activity" yaml:"activity"`
+
+	// BlockedUsers lists the users whose messages are ignored, e.g., to mitigate spam from known bad actors.
+	// Their edits, reactions, interactions and joins to guilds are ignored as well.
+	// Changes after the Adapter starts handling messages are not reflected.
+	BlockedUsers []string `json:"blocked_users" yaml:"blocked_users"`
+
+	// ShardID is the zero-based ID of the gateway shard this Adapter connects as.
+	// This is used only when ShardCount is positive.
+	ShardID int `json:"shard_id" yaml:"shard_id"`
+
+	// ShardCount is the total number of gateway shards, which Discord requires for bots in many guilds.
+	// Run one Adapter per shard with ShardID from 0 to ShardCount-1; each receives events of its own share of the guilds.
+	// Zero connects without sharding.
+	// These are applied to the session that NewAdapter creates, while a session given via WithSession is used as it is.
+	ShardCount int `json:"shard_count" yaml:"shard_count"`
+
+	// SendMaxRetries is the number of times a message sent by Adapter.SendMessage is retried when Discord fails transiently,
+	// i.e., with a server error or a rate limit. Other client errors such as missing permissions are not retried.
+	SendMaxRetries int `json:"send_max_retries" yaml:"send_max_retries"`
+
+	// SendRetryInterval is the initial interval between retries to send a message, doubled on every retry.
+	// Retry-After is honored instead when Discord specifies it for a rate limit.
+	SendRetryInterval time.Duration `json:"send_retry_interval" yaml:"send_retry_interval"`
+
+	// ChannelCacheTTL is the duration in which a channel fetched by Adapter.Channel is reused.
+	// The cache also serves the lookups for permission checks, channel kinds and emoji resolution when the state does not have the channel.
+	// Zero disables the cache.
+	ChannelCacheTTL time.Duration `json:"channel_cache_ttl" yaml:"channel_cache_ttl"`
+
+	// ActivityRotation lists the activities to cycle through, e.g., to show tips one after another.
+	// This takes precedence over Activity.
+	ActivityRotation []string `json:"activity_rotation" yaml:"activity_rotation"`
+
+	// ActivityRotationInterval is the interval to switch to the next activity in ActivityRotation.
+	// Discord rate-limits presence updates, so keep this at tens of seconds or longer.
+	ActivityRotationInterval time.Duration `json:"activity_rotation_interval" yaml:"activity_rotation_interval"`
+
+	// DryRun makes Adapter.SendMessage log the content and the destination at info level instead of sending them,
+	// so the command logic can be validated against a real token without posting to Discord.
+	// The messages the Adapter sends by itself, such as auto responses and thread closing messages, are logged likewise,
+	// and so are the messages of StreamWriter and the edits by Adapter.EditMessage.
+	// Other operations such as reactions and deletions by the Adapter's methods are still performed.
+	DryRun bool `json:"dry_run" yaml:"dry_run"`
+
+	// HelpMatcher tells if the given command text triggers help, e.g., to accept both ".help" and "!help" with a regular expression.
+	// The text is given after the prefix or the bot's mention is stripped, and also as the whole message.
+	// When this is nil, HelpCommand is compared as it is.
+	HelpMatcher func(text string) bool `json:"-" yaml:"-"`
+
+	// AbortMatcher tells if the given command text triggers context cancellation in the same way as HelpMatcher.
+	// When this is nil, AbortCommand is compared as it is.
+	AbortMatcher func(text string) bool `json:"-" yaml:"-"`
+
+	// MaxMessageAge drops messages sent longer ago than this, e.g., those replayed after the gateway resumes, so old commands are not executed again.
+	// Zero handles messages regardless of their age.
+	MaxMessageAge time.Duration `json:"max_message_age" yaml:"max_message_age"`
+
+	// ReactionCommands maps emojis to the command text that adding them to any message triggers, e.g., "🔁" to ".retry".
+	// The emoji is a unicode emoji or "name:id" for a custom emoji, and the matching reaction is passed to go-sarah as *ReactionInput whose message is the command text.
+	// Other reactions are passed as they are only when HandleReactions is true.
+	// Intents must include IntentsGuildMessageReactions or IntentsDirectMessageReactions to receive reactions.
+	ReactionCommands map[string]string `json:"reaction_commands" yaml:"reaction_commands"`
+
+	// SerializePerChannel lets SendMessage deliver messages to the same channel one by one in the order they were submitted,
+	// while messages to different channels are sent concurrently.
+	// This keeps the responses of concurrent commands from interleaving. SendMessage only queues the message,
+	// and a goroutine per channel with pending messages sends them. Messages beyond 32 pending for the channel are dropped.
+	SerializePerChannel bool `json:"serialize_per_channel" yaml:"serialize_per_channel"`
+
+	// HelpHeader is the line sent before the command list when the help is sent as plain text, e.g., "Available commands:".
+	// Empty sends the command list only.
+	HelpHeader string `json:"help_header" yaml:"help_header"`
+
+	// SanitizeIncomingMentions neutralizes @everyone and @here in received messages with SanitizeMentions,
+	// so commands echoing Input.Message cannot be used to ping everyone.
+	SanitizeIncomingMentions bool `json:"sanitize_incoming_mentions" yaml:"sanitize_incoming_mentions"`
 }
 
 // NewConfig creates and returns a new Config instance with default settings.
 // Token is empty and must be set before use.
 func NewConfig() *Config {
 	return &Config{
-		Token:        "",
-		HelpCommand:  ".help",
-		AbortCommand: ".abort",
-		Intents:      discordgo.IntentsGuildMessages | discordgo.IntentsDirectMessages | discordgo.IntentsMessageContent,
+		Token:                     "",
+		HelpCommand:               ".help",
+		AbortCommand:              ".abort",
+		Intents:                   discordgo.IntentsGuildMessages | discordgo.IntentsDirectMessages | discordgo.IntentsMessageContent,
+		StreamEditInterval:        1 * time.Second,
+		EnableGuildToggle:         false,
+		GuildEnableCommand:        "/enable",
+		GuildDisableCommand:       "/disable",
+		CommandPrefix:             "",
+		RequireInvocation:         false,
+		ReactionInterval:          250 * time.Millisecond,
+		SuppressDuplicateSends:    false,
+		DuplicateSendWindow:       5 * time.Second,
+		OpenRetryLimit:            0,
+		OpenRetryInterval:         1 * time.Second,
+		ReconnectOnInvalidSession: false,
+		DefaultRequestOptions:     nil,
+		ThreadAutoArchiveDuration: 0,
+		ArchiveThreadOnCompletion: false,
+		ThreadClosingMessage:      "",
+		MaxEmbedsPerMessage:       MaxEmbedsPerMessage,
+		ZombieTimeout:             0,
+		HelpAsSelectMenu:          false,
+		CorrelationIDFunc:         nil,
+		OnGuildLeave:              nil,
+		SerializePerSender:        false,
+		MaxSenderQueues:           1000,
+		AutoResponses:             nil,
+		AutoResponseCooldown:      30 * time.Second,
+		AutoRespondToInvocations:  false,
+		ResolveEmojiInContent:     false,
+		ShowTypingIndicator:       false,
+		IgnoreBots:                true,
+		DefaultAllowedMentions:    nil,
+		MaxReconnectAttempts:      0,
+		SendTimeout:               0,
+		MaxMessageLength:          MaxMessageLength,
+		HelpAsEmbed:               false,
+		AllowedChannels:           nil,
+		BlockedChannels:           nil,
+		SendRateLimit:             0,
+		HandleEdits:               false,
+		HandleDeletes:             false,
+		HandleReactions:           false,
+		HandleMemberJoin:          false,
+		CaseInsensitiveCommands:   false,
+		ShutdownGracePeriod:       5 * time.Second,
+		Status:                    "",
+		Activity:                  "",
+		BlockedUsers:              nil,
+		ShardID:                   0,
+		ShardCount:                0,
+		SendMaxRetries:            0,
+		SendRetryInterval:         1 * time.Second,
+		ChannelCacheTTL:           5 * time.Minute,
+		ActivityRotation:          nil,
+		ActivityRotationInterval:  1 * time.Minute,
+		DryRun:                    false,
+		HelpMatcher:               nil,
+		AbortMatcher:              nil,
+		MaxMessageAge:             0,
+		ReactionCommands:          nil,
+		SerializePerChannel:       false,
+		HelpHeader:                "",
+		SanitizeIncomingMentions:  false,
+	}
+}
+
+// threadAutoArchiveDurations are the durations in minutes that Discord accepts for a thread's auto archive duration.
+var threadAutoArchiveDurations = []int{60, 1440, 4320, 10080}
+
+// Validate checks if the configuration is coherent.
+// All problems are reported at once, and each of them wraps ErrInvalidConfig.
+// An empty Token is reported with ErrEmptyToken.
+// NewAdapter validates the given configuration likewise, but does not require Token when a session is given via WithSession.
+func (c *Config) Validate() error {
+	return c.validate(true)
+}
+
+func (c *Config) validate(requireToken bool) error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidConfig, fmt.Sprintf(format, args...)))
+	}
+
+	if requireToken && c.Token == "" {
+		errs = append(errs, ErrEmptyToken)
+	}
+
+	// Intents
+	if c.Intents&(discordgo.IntentsGuildMessages|discordgo.IntentsDirectMessages) == 0 {
+		invalid("intents must include GuildMessages or DirectMessages to receive messages")
+	}
+	if c.CommandPrefix != "" && c.Intents&discordgo.IntentsGuildMessages != 0 && c.Intents&discordgo.IntentsMessageContent == 0 {
+		invalid("intents must include MessageContent to detect CommandPrefix in guild messages")
+	}
+	if c.EnableGuildToggle && c.Intents&discordgo.IntentsGuildMessages == 0 {
+		invalid("intents must include GuildMessages to toggle the bot per guild")
+	}
+
+	if c.HandleReactions && c.Intents&(discordgo.IntentsGuildMessageReactions|discordgo.IntentsDirectMessageReactions) == 0 {
+		invalid("intents must include GuildMessageReactions or DirectMessageReactions to handle reactions")
+	}
+	if len(c.ReactionCommands) > 0 && c.Intents&(discordgo.IntentsGuildMessageReactions|discordgo.IntentsDirectMessageReactions) == 0 {
+		invalid("intents must include GuildMessageReactions or DirectMessageReactions to handle ReactionCommands")
+	}
+
+	if c.HandleMemberJoin && c.Intents&discordgo.IntentsGuildMembers == 0 {
+		invalid("intents must include GuildMembers to handle member joins")
+	}
+
+	// Conflicting commands
+	if c.HelpCommand != "" && c.HelpCommand == c.AbortCommand {
+		invalid("HelpCommand and AbortCommand must differ: %q", c.HelpCommand)
+	}
+	if c.EnableGuildToggle {
+		if c.GuildEnableCommand == "" && c.GuildDisableCommand == "" {
+			invalid("GuildEnableCommand or GuildDisableCommand must be set when EnableGuildToggle is true")
+		}
+		if c.GuildEnableCommand != "" && c.GuildEnableCommand == c.GuildDisableCommand {
+			invalid("GuildEnableCommand and GuildDisableCommand must differ: %q", c.GuildEnableCommand)
+		}
+	}
+
+	// Ranges
+	if c.StreamEditInterval < 0 {
+		invalid("StreamEditInterval must not be negative: %s", c.StreamEditInterval)
+	}
+	if c.ReactionInterval < 0 {
+		invalid("ReactionInterval must not be negative: %s", c.ReactionInterval)
+	}
+	if c.SuppressDuplicateSends && c.DuplicateSendWindow <= 0 {
+		invalid("DuplicateSendWindow must be positive when SuppressDuplicateSends is true: %s", c.DuplicateSendWindow)
+	}
+	if c.OpenRetryLimit < 0 {
+		invalid("OpenRetryLimit must not be negative: %d", c.OpenRetryLimit)
+	}
+	if c.MaxReconnectAttempts < 0 {
+		invalid("MaxReconnectAttempts must not be negative: %d", c.MaxReconnectAttempts)
+	}
+	if c.OpenRetryLimit > 0 && c.OpenRetryInterval <= 0 {
+		invalid("OpenRetryInterval must be positive when OpenRetryLimit is set: %s", c.OpenRetryInterval)
+	}
+	if c.ThreadAutoArchiveDuration != 0 && !slices.Contains(threadAutoArchiveDurations, c.ThreadAutoArchiveDuration) {
+		invalid("ThreadAutoArchiveDuration must be one of %v: %d", threadAutoArchiveDurations, c.ThreadAutoArchiveDuration)
+	}
+	if c.MaxEmbedsPerMessage < 0 || c.MaxEmbedsPerMessage > MaxEmbedsPerMessage {
+		invalid("MaxEmbedsPerMessage must be between 0 and %d: %d", MaxEmbedsPerMessage, c.MaxEmbedsPerMessage)
+	}
+	if c.MaxMessageLength < 0 || c.MaxMessageLength > MaxMessageLength {
+		invalid("MaxMessageLength must be between 0 and %d: %d", MaxMessageLength, c.MaxMessageLength)
+	}
+	if c.ZombieTimeout < 0 {
+		invalid("ZombieTimeout must not be negative: %s", c.ZombieTimeout)
+	}
+	if c.MaxSenderQueues < 0 {
+		invalid("MaxSenderQueues must not be negative: %d", c.MaxSenderQueues)
+	}
+	if c.SendTimeout < 0 {
+		invalid("SendTimeout must not be negative: %s", c.SendTimeout)
+	}
+	if c.AutoResponseCooldown < 0 {
+		invalid("AutoResponseCooldown must not be negative: %s", c.AutoResponseCooldown)
+	}
+	if c.Status != "" && !slices.Contains(presenceStatuses, c.Status) {
+		invalid("Status must be one of %v: %q", presenceStatuses, c.Status)
+	}
+	if c.ShardCount < 0 {
+		invalid("ShardCount must not be negative: %d", c.ShardCount)
+	}
+	if c.ShardID < 0 || (c.ShardCount > 0 && c.ShardID >= c.ShardCount) || (c.ShardCount == 0 && c.ShardID != 0) {
+		invalid("ShardID must be between 0 and ShardCount-1: %d", c.ShardID)
+	}
+	if c.SendMaxRetries < 0 {
+		invalid("SendMaxRetries must not be negative: %d", c.SendMaxRetries)
+	}
+	if c.SendMaxRetries > 0 && c.SendRetryInterval <= 0 {
+		invalid("SendRetryInterval must be positive when SendMaxRetries is set: %s", c.SendRetryInterval)
+	}
+	if c.ChannelCacheTTL < 0 {
+		invalid("ChannelCacheTTL must not be negative: %s", c.ChannelCacheTTL)
+	}
+	if len(c.ActivityRotation) > 0 && c.ActivityRotationInterval <= 0 {
+		invalid("ActivityRotationInterval must be positive when ActivityRotation is set: %s", c.ActivityRotationInterval)
+	}
+	if c.ShutdownGracePeriod < 0 {
+		invalid("ShutdownGracePeriod must not be negative: %s", c.ShutdownGracePeriod)
+	}
+	if c.SendRateLimit < 0 {
+		invalid("SendRateLimit must not be negative: %g", c.SendRateLimit)
+	}
+	if c.MaxMessageAge < 0 {
+		invalid("MaxMessageAge must not be negative: %s", c.MaxMessageAge)
+	}
+
+	return errors.Join(errs...)
+}
+
+// LoadConfig reads a JSON-encoded configuration from the given reader and validates it.
+// Only JSON is supported; to load YAML, unmarshal the input onto the value returned by NewConfig
+// with a YAML library of your choice and call Config.Validate.
+//
+// Fields omitted in the input keep the defaults of NewConfig.
+// Fields that cannot be serialized, such as Config.DefaultRequestOptions and Config.AutoResponses, must be set afterwards.
+// Token is not required here since it is typically given apart from the file, e.g., by an environment variable,
+// while NewAdapter still requires it.
+func LoadConfig(r io.Reader) (*Config, error) {
+	config := NewConfig()
+	if err := json.NewDecoder(r).Decode(config); err != nil {
+		return nil, fmt.Errorf("failed to decode configuration: %w", err)
+	}
+
+	if err := config.validate(false); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Save writes the configuration, including the defaults, to the given writer in JSON so LoadConfig can read it back.
+// Token is left out so the saved file does not leak the credential.
+func (c *Config) Save(w io.Writer) error {
+	// The shallower field hides Config.Token from the encoder.
+	redacted := struct {
+		*Config
+		Token string `json:"token,omitempty"`
+	}{Config: c}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(redacted); err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
 	}
+	return nil
 }
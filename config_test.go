@@ -1,7 +1,11 @@
 package discord
 
 import (
+	"bytes"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -25,4 +29,416 @@ func TestNewConfig(t *testing.T) {
 	if config.Intents != expectedIntents {
 		t.Errorf("Expected Intents to be %d, got %d", expectedIntents, config.Intents)
 	}
+
+	if config.StreamEditInterval != 1*time.Second {
+		t.Errorf("Expected StreamEditInterval to be %s, got %s", 1*time.Second, config.StreamEditInterval)
+	}
+
+	if config.EnableGuildToggle {
+		t.Error("Expected EnableGuildToggle to be false")
+	}
+
+	if config.GuildEnableCommand != "/enable" {
+		t.Errorf("Expected GuildEnableCommand to be %q, got %q", "/enable", config.GuildEnableCommand)
+	}
+
+	if config.GuildDisableCommand != "/disable" {
+		t.Errorf("Expected GuildDisableCommand to be %q, got %q", "/disable", config.GuildDisableCommand)
+	}
+
+	if config.ReactionInterval != 250*time.Millisecond {
+		t.Errorf("Expected ReactionInterval to be %s, got %s", 250*time.Millisecond, config.ReactionInterval)
+	}
+
+	if config.CommandPrefix != "" {
+		t.Errorf("Expected empty CommandPrefix, got %q", config.CommandPrefix)
+	}
+
+	if config.RequireInvocation {
+		t.Error("Expected RequireInvocation to be false")
+	}
+
+	if config.SuppressDuplicateSends {
+		t.Error("Expected SuppressDuplicateSends to be false")
+	}
+
+	if config.DuplicateSendWindow != 5*time.Second {
+		t.Errorf("Expected DuplicateSendWindow to be %s, got %s", 5*time.Second, config.DuplicateSendWindow)
+	}
+
+	if config.OpenRetryLimit != 0 {
+		t.Errorf("Expected OpenRetryLimit to be 0, got %d", config.OpenRetryLimit)
+	}
+
+	if config.OpenRetryInterval != 1*time.Second {
+		t.Errorf("Expected OpenRetryInterval to be %s, got %s", 1*time.Second, config.OpenRetryInterval)
+	}
+
+	if config.ReconnectOnInvalidSession {
+		t.Error("Expected ReconnectOnInvalidSession to be false")
+	}
+
+	if config.DefaultRequestOptions != nil {
+		t.Errorf("Expected no DefaultRequestOptions, got %d", len(config.DefaultRequestOptions))
+	}
+
+	if config.ThreadAutoArchiveDuration != 0 {
+		t.Errorf("Expected ThreadAutoArchiveDuration to be 0, got %d", config.ThreadAutoArchiveDuration)
+	}
+
+	if config.ArchiveThreadOnCompletion {
+		t.Error("Expected ArchiveThreadOnCompletion to be false")
+	}
+
+	if config.ThreadClosingMessage != "" {
+		t.Errorf("Expected empty ThreadClosingMessage, got %q", config.ThreadClosingMessage)
+	}
+
+	if config.MaxEmbedsPerMessage != 10 {
+		t.Errorf("Expected MaxEmbedsPerMessage to be 10, got %d", config.MaxEmbedsPerMessage)
+	}
+
+	if config.ZombieTimeout != 0 {
+		t.Errorf("Expected ZombieTimeout to be 0, got %s", config.ZombieTimeout)
+	}
+
+	if config.HelpAsSelectMenu {
+		t.Error("Expected HelpAsSelectMenu to be false")
+	}
+
+	if config.CorrelationIDFunc != nil {
+		t.Error("Expected CorrelationIDFunc to be nil")
+	}
+
+	if config.OnGuildLeave != nil {
+		t.Error("Expected OnGuildLeave to be nil")
+	}
+
+	if config.SerializePerSender {
+		t.Error("Expected SerializePerSender to be false")
+	}
+
+	if config.MaxSenderQueues != 1000 {
+		t.Errorf("Expected MaxSenderQueues to be 1000, got %d", config.MaxSenderQueues)
+	}
+
+	if config.AutoResponses != nil {
+		t.Error("Expected AutoResponses to be nil")
+	}
+
+	if config.AutoResponseCooldown != 30*time.Second {
+		t.Errorf("Expected AutoResponseCooldown to be %s, got %s", 30*time.Second, config.AutoResponseCooldown)
+	}
+
+	if config.AutoRespondToInvocations {
+		t.Error("Expected AutoRespondToInvocations to be false")
+	}
+
+	if config.ResolveEmojiInContent {
+		t.Error("Expected ResolveEmojiInContent to be false")
+	}
+
+	if config.ShowTypingIndicator {
+		t.Error("Expected ShowTypingIndicator to be false")
+	}
+
+	if !config.IgnoreBots {
+		t.Error("Expected IgnoreBots to be true")
+	}
+
+	if config.DefaultAllowedMentions != nil {
+		t.Errorf("Expected DefaultAllowedMentions to be nil, got %#v", config.DefaultAllowedMentions)
+	}
+
+	if config.MaxReconnectAttempts != 0 {
+		t.Errorf("Expected MaxReconnectAttempts to be 0, got %d", config.MaxReconnectAttempts)
+	}
+
+	if config.SendTimeout != 0 {
+		t.Errorf("Expected SendTimeout to be 0, got %s", config.SendTimeout)
+	}
+
+	if config.MaxMessageLength != MaxMessageLength {
+		t.Errorf("Expected MaxMessageLength to be %d, got %d", MaxMessageLength, config.MaxMessageLength)
+	}
+
+	if config.HelpAsEmbed {
+		t.Error("Expected HelpAsEmbed to be false")
+	}
+
+	if config.AllowedChannels != nil || config.BlockedChannels != nil {
+		t.Error("Expected AllowedChannels and BlockedChannels to be nil")
+	}
+
+	if config.SendRateLimit != 0 {
+		t.Errorf("Expected SendRateLimit to be 0, got %g", config.SendRateLimit)
+	}
+
+	if config.HandleEdits {
+		t.Error("Expected HandleEdits to be false")
+	}
+
+	if config.HandleDeletes {
+		t.Error("Expected HandleDeletes to be false")
+	}
+
+	if config.HandleReactions {
+		t.Error("Expected HandleReactions to be false")
+	}
+
+	if config.HandleMemberJoin {
+		t.Error("Expected HandleMemberJoin to be false")
+	}
+
+	if config.CaseInsensitiveCommands {
+		t.Error("Expected CaseInsensitiveCommands to be false")
+	}
+
+	if config.ShutdownGracePeriod != 5*time.Second {
+		t.Errorf("Expected ShutdownGracePeriod to be 5s, got %s", config.ShutdownGracePeriod)
+	}
+
+	if config.Status != "" || config.Activity != "" {
+		t.Errorf("Expected Status and Activity to be empty, got %q and %q", config.Status, config.Activity)
+	}
+
+	if config.BlockedUsers != nil {
+		t.Errorf("Expected BlockedUsers to be nil, got %v", config.BlockedUsers)
+	}
+
+	if config.ShardID != 0 || config.ShardCount != 0 {
+		t.Errorf("Expected no sharding, got shard %d of %d", config.ShardID, config.ShardCount)
+	}
+
+	if config.SendMaxRetries != 0 {
+		t.Errorf("Expected SendMaxRetries to be 0, got %d", config.SendMaxRetries)
+	}
+
+	if config.SendRetryInterval != 1*time.Second {
+		t.Errorf("Expected SendRetryInterval to be %s, got %s", 1*time.Second, config.SendRetryInterval)
+	}
+
+	if config.ChannelCacheTTL != 5*time.Minute {
+		t.Errorf("Expected ChannelCacheTTL to be %s, got %s", 5*time.Minute, config.ChannelCacheTTL)
+	}
+
+	if config.ActivityRotation != nil {
+		t.Errorf("Expected ActivityRotation to be nil, got %v", config.ActivityRotation)
+	}
+
+	if config.ActivityRotationInterval != 1*time.Minute {
+		t.Errorf("Expected ActivityRotationInterval to be %s, got %s", 1*time.Minute, config.ActivityRotationInterval)
+	}
+
+	if config.DryRun {
+		t.Error("Expected DryRun to be false")
+	}
+
+	if config.HelpMatcher != nil {
+		t.Error("Expected HelpMatcher to be nil")
+	}
+
+	if config.AbortMatcher != nil {
+		t.Error("Expected AbortMatcher to be nil")
+	}
+
+	if config.MaxMessageAge != 0 {
+		t.Errorf("Expected MaxMessageAge to be 0, got %s", config.MaxMessageAge)
+	}
+
+	if config.ReactionCommands != nil {
+		t.Errorf("Expected ReactionCommands to be nil, got %v", config.ReactionCommands)
+	}
+
+	if config.SerializePerChannel {
+		t.Error("Expected SerializePerChannel to be false")
+	}
+
+	if config.HelpHeader != "" {
+		t.Errorf("Expected HelpHeader to be empty, got %q", config.HelpHeader)
+	}
+
+	if config.SanitizeIncomingMentions {
+		t.Error("Expected SanitizeIncomingMentions to be false")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		config := NewConfig()
+		config.Token = "token"
+		return config
+	}
+
+	t.Run("default with token", func(t *testing.T) {
+		if err := valid().Validate(); err != nil {
+			t.Errorf("Unexpected error: %+v", err)
+		}
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		err := NewConfig().Validate()
+		if !errors.Is(err, ErrEmptyToken) {
+			t.Errorf("Expected ErrEmptyToken, got %+v", err)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{name: "no message intents", modify: func(c *Config) { c.Intents = discordgo.IntentsGuilds }},
+		{name: "prefix without message content", modify: func(c *Config) {
+			c.CommandPrefix = "!"
+			c.Intents = discordgo.IntentsGuildMessages
+		}},
+		{name: "guild toggle without guild messages", modify: func(c *Config) {
+			c.EnableGuildToggle = true
+			c.Intents = discordgo.IntentsDirectMessages
+		}},
+		{name: "reactions without reaction intents", modify: func(c *Config) {
+			c.HandleReactions = true
+			c.Intents = discordgo.IntentsGuildMessages
+		}},
+		{name: "member join without guild members intent", modify: func(c *Config) { c.HandleMemberJoin = true }},
+		{name: "same help and abort commands", modify: func(c *Config) { c.AbortCommand = c.HelpCommand }},
+		{name: "same guild toggle commands", modify: func(c *Config) {
+			c.EnableGuildToggle = true
+			c.GuildDisableCommand = c.GuildEnableCommand
+		}},
+		{name: "guild toggle without commands", modify: func(c *Config) {
+			c.EnableGuildToggle = true
+			c.GuildEnableCommand = ""
+			c.GuildDisableCommand = ""
+		}},
+		{name: "duplicate suppression without window", modify: func(c *Config) {
+			c.SuppressDuplicateSends = true
+			c.DuplicateSendWindow = 0
+		}},
+		{name: "negative retry limit", modify: func(c *Config) { c.OpenRetryLimit = -1 }},
+		{name: "negative reconnect attempts", modify: func(c *Config) { c.MaxReconnectAttempts = -1 }},
+		{name: "unsupported archive duration", modify: func(c *Config) { c.ThreadAutoArchiveDuration = 30 }},
+		{name: "too many embeds", modify: func(c *Config) { c.MaxEmbedsPerMessage = MaxEmbedsPerMessage + 1 }},
+		{name: "too long messages", modify: func(c *Config) { c.MaxMessageLength = MaxMessageLength + 1 }},
+		{name: "negative zombie timeout", modify: func(c *Config) { c.ZombieTimeout = -time.Second }},
+		{name: "activity rotation without interval", modify: func(c *Config) {
+			c.ActivityRotation = []string{".help", "with sarah"}
+			c.ActivityRotationInterval = 0
+		}},
+		{name: "negative channel cache TTL", modify: func(c *Config) { c.ChannelCacheTTL = -time.Second }},
+		{name: "negative send retries", modify: func(c *Config) { c.SendMaxRetries = -1 }},
+		{name: "send retries without interval", modify: func(c *Config) { c.SendMaxRetries = 1; c.SendRetryInterval = 0 }},
+		{name: "negative shard count", modify: func(c *Config) { c.ShardCount = -1 }},
+		{name: "shard ID out of range", modify: func(c *Config) { c.ShardCount = 2; c.ShardID = 2 }},
+		{name: "negative shard ID", modify: func(c *Config) { c.ShardCount = 2; c.ShardID = -1 }},
+		{name: "shard ID without shard count", modify: func(c *Config) { c.ShardID = 1 }},
+		{name: "unknown status", modify: func(c *Config) { c.Status = "away" }},
+		{name: "reaction commands without intents", modify: func(c *Config) { c.ReactionCommands = map[string]string{"🔁": ".retry"} }},
+		{name: "negative max message age", modify: func(c *Config) { c.MaxMessageAge = -time.Second }},
+		{name: "negative shutdown grace period", modify: func(c *Config) { c.ShutdownGracePeriod = -time.Second }},
+		{name: "negative send rate limit", modify: func(c *Config) { c.SendRateLimit = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid()
+			tt.modify(config)
+
+			err := config.Validate()
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("Expected ErrInvalidConfig, got %+v", err)
+			}
+		})
+	}
+
+	t.Run("all problems are reported", func(t *testing.T) {
+		config := NewConfig()
+		config.OpenRetryLimit = -1
+		config.ZombieTimeout = -time.Second
+
+		err := config.Validate()
+		if !errors.Is(err, ErrEmptyToken) || !strings.Contains(err.Error(), "OpenRetryLimit") || !strings.Contains(err.Error(), "ZombieTimeout") {
+			t.Errorf("Expected all problems to be reported, got %+v", err)
+		}
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("partial config keeps defaults", func(t *testing.T) {
+		input := `{"token": "token", "command_prefix": "!", "max_sender_queues": 10}`
+
+		config, err := LoadConfig(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if config.Token != "token" || config.CommandPrefix != "!" || config.MaxSenderQueues != 10 {
+			t.Errorf("Given fields are not loaded: %#v", config)
+		}
+
+		defaults := NewConfig()
+		if config.HelpCommand != defaults.HelpCommand {
+			t.Errorf("Expected default HelpCommand %q, got %q", defaults.HelpCommand, config.HelpCommand)
+		}
+		if config.Intents != defaults.Intents {
+			t.Errorf("Expected default Intents %d, got %d", defaults.Intents, config.Intents)
+		}
+		if config.StreamEditInterval != defaults.StreamEditInterval {
+			t.Errorf("Expected default StreamEditInterval %s, got %s", defaults.StreamEditInterval, config.StreamEditInterval)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := LoadConfig(strings.NewReader(`{"token": "token", "open_retry_limit": -1}`))
+		if !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("Expected ErrInvalidConfig, got %+v", err)
+		}
+	})
+
+	t.Run("token is not required", func(t *testing.T) {
+		config, err := LoadConfig(strings.NewReader(`{"command_prefix": "!"}`))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if config.Token != "" {
+			t.Errorf("Expected empty Token, got %q", config.Token)
+		}
+	})
+
+	t.Run("malformed input", func(t *testing.T) {
+		_, err := LoadConfig(strings.NewReader(`{"token": `))
+		if err == nil {
+			t.Error("Expected error for malformed input")
+		}
+	})
+}
+
+func TestConfig_Save(t *testing.T) {
+	config := NewConfig()
+	config.Token = "token"
+	config.CommandPrefix = "!"
+	config.ZombieTimeout = time.Minute
+	config.ThreadAutoArchiveDuration = 1440
+	config.OnGuildLeave = func(string) {}
+
+	buf := &bytes.Buffer{}
+	if err := config.Save(buf); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	if !strings.Contains(buf.String(), `"help_command": ".help"`) {
+		t.Errorf("Defaults should be saved: %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "token") {
+		t.Errorf("Token should not be saved: %s", buf.String())
+	}
+
+	loaded, err := LoadConfig(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	if loaded.Token != "" || loaded.CommandPrefix != config.CommandPrefix || loaded.ZombieTimeout != config.ZombieTimeout || loaded.ThreadAutoArchiveDuration != config.ThreadAutoArchiveDuration {
+		t.Errorf("Config does not round-trip: %#v", loaded)
+	}
 }
@@ -0,0 +1,32 @@
+package discord
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CorrelationID returns the ID that correlates the logs and outgoing messages caused by this input.
+// The ID is generated by Config.CorrelationIDFunc when set, or randomly otherwise.
+func (i *Input) CorrelationID() string {
+	return i.correlationID
+}
+
+// newCorrelationID generates a random correlation ID.
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	// crypto/rand.Read never returns an error.
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// correlationID returns the correlation ID for the given message.
+func (a *Adapter) correlationID(m *discordgo.MessageCreate) string {
+	if a.config.CorrelationIDFunc != nil {
+		if id := a.config.CorrelationIDFunc(m); id != "" {
+			return id
+		}
+	}
+	return newCorrelationID()
+}
@@ -0,0 +1,76 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestInput_CorrelationID(t *testing.T) {
+	newMessage := func() *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ID:        "msg-1",
+				ChannelID: "ch-1",
+				Content:   "hello",
+				Author:    &discordgo.User{ID: "user-1"},
+			},
+		}
+	}
+	receive := func(adapter *Adapter) *Input {
+		var received *Input
+		adapter.handleMessage(context.Background(), &discordgo.Session{State: discordgo.NewState()}, newMessage(), func(input sarah.Input) error {
+			received = input.(*Input)
+			return nil
+		})
+		return received
+	}
+
+	t.Run("random IDs are generated", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		first := receive(adapter).CorrelationID()
+		second := receive(adapter).CorrelationID()
+
+		if len(first) != 16 {
+			t.Errorf("Expected a 16-character ID, got %q", first)
+		}
+		if first == second {
+			t.Errorf("Expected unique IDs, got %q twice", first)
+		}
+	})
+
+	t.Run("CorrelationIDFunc is used", func(t *testing.T) {
+		config := NewConfig()
+		config.CorrelationIDFunc = func(m *discordgo.MessageCreate) string {
+			return "trace-" + m.ID
+		}
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		if id := receive(adapter).CorrelationID(); id != "trace-msg-1" {
+			t.Errorf("Expected the generated ID, got %q", id)
+		}
+	})
+
+	t.Run("empty ID from CorrelationIDFunc falls back to a random one", func(t *testing.T) {
+		config := NewConfig()
+		config.CorrelationIDFunc = func(*discordgo.MessageCreate) string {
+			return ""
+		}
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		if id := receive(adapter).CorrelationID(); id == "" {
+			t.Error("Expected a random ID")
+		}
+	})
+
+	t.Run("MessageToInput generates an ID", func(t *testing.T) {
+		input, _ := MessageToInput(newMessage())
+
+		if input.CorrelationID() == "" {
+			t.Error("Expected an ID")
+		}
+	})
+}
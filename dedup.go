@@ -0,0 +1,115 @@
+package discord
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// dedupEntriesPerChannel is the maximum number of recent sends remembered per channel.
+	dedupEntriesPerChannel = 8
+
+	// dedupMaxChannels is the maximum number of channels whose recent sends are remembered.
+	// The least recently used channel is forgotten when this is exceeded.
+	dedupMaxChannels = 1024
+)
+
+type contentHash [sha256.Size]byte
+
+// hashContent returns a hash that identifies the given output content.
+// false is returned when the content cannot be hashed.
+func hashContent(content interface{}) (contentHash, bool) {
+	if str, ok := content.(string); ok {
+		return sha256.Sum256([]byte("string:" + str)), true
+	}
+
+	// Embeds, components, and other fields of *discordgo.MessageSend are covered by its JSON representation.
+	// Attached files are identified by their names and content types since their readers cannot be consumed here.
+	encoded, err := json.Marshal(content)
+	if err != nil {
+		return contentHash{}, false
+	}
+	return sha256.Sum256(append([]byte(fmt.Sprintf("%T:", content)), encoded...)), true
+}
+
+type dedupEntry struct {
+	hash contentHash
+	at   time.Time
+}
+
+type dedupChannel struct {
+	channelID string
+	entries   []dedupEntry // Newest first
+}
+
+// sendDeduplicator remembers recent sends per channel to suppress identical consecutive sends.
+// The zero value is ready to use.
+type sendDeduplicator struct {
+	mutex    sync.Mutex
+	channels map[string]*list.Element
+	lru      *list.List
+}
+
+// reserve tells if the given content may be sent to the channel.
+// When true is returned, the send is remembered so an identical send within the window is suppressed.
+func (d *sendDeduplicator) reserve(channelID string, hash contentHash, window time.Duration, now time.Time) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.channels == nil {
+		d.channels = map[string]*list.Element{}
+		d.lru = list.New()
+	}
+
+	elem, ok := d.channels[channelID]
+	if ok {
+		d.lru.MoveToFront(elem)
+	} else {
+		elem = d.lru.PushFront(&dedupChannel{channelID: channelID})
+		d.channels[channelID] = elem
+
+		if d.lru.Len() > dedupMaxChannels {
+			oldest := d.lru.Back()
+			d.lru.Remove(oldest)
+			delete(d.channels, oldest.Value.(*dedupChannel).channelID)
+		}
+	}
+
+	channel := elem.Value.(*dedupChannel)
+	for _, entry := range channel.entries {
+		if entry.hash == hash && now.Sub(entry.at) < window {
+			return false
+		}
+	}
+
+	entries := append([]dedupEntry{{hash: hash, at: now}}, channel.entries...)
+	if len(entries) > dedupEntriesPerChannel {
+		entries = entries[:dedupEntriesPerChannel]
+	}
+	channel.entries = entries
+
+	return true
+}
+
+// release forgets the reserved send so the same content can be sent again, e.g., after the send failed.
+func (d *sendDeduplicator) release(channelID string, hash contentHash) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	elem, ok := d.channels[channelID]
+	if !ok {
+		return
+	}
+
+	channel := elem.Value.(*dedupChannel)
+	for i, entry := range channel.entries {
+		if entry.hash == hash {
+			channel.entries = append(channel.entries[:i], channel.entries[i+1:]...)
+			return
+		}
+	}
+}
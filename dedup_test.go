@@ -0,0 +1,157 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_SendMessage_SuppressDuplicateSends(t *testing.T) {
+	newAdapter := func(sent *[]string) *Adapter {
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				*sent = append(*sent, channelID+":"+content)
+				return &discordgo.Message{}, nil
+			},
+			channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				*sent = append(*sent, channelID+":"+data.Content)
+				return &discordgo.Message{}, nil
+			},
+		}
+		config := NewConfig()
+		config.SuppressDuplicateSends = true
+		config.DuplicateSendWindow = time.Minute
+		return &Adapter{config: config, session: mock}
+	}
+
+	t.Run("identical string is suppressed", func(t *testing.T) {
+		var sent []string
+		adapter := newAdapter(&sent)
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+
+		if len(sent) != 1 {
+			t.Errorf("Expected 1 send, got %v", sent)
+		}
+	})
+
+	t.Run("identical MessageSend is suppressed", func(t *testing.T) {
+		var sent []string
+		adapter := newAdapter(&sent)
+
+		newContent := func() *discordgo.MessageSend {
+			return &discordgo.MessageSend{
+				Content: "hello",
+				Embeds:  []*discordgo.MessageEmbed{{Title: "title"}},
+			}
+		}
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), newContent()))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), newContent()))
+
+		if len(sent) != 1 {
+			t.Errorf("Expected 1 send, got %v", sent)
+		}
+	})
+
+	t.Run("different content, channel, or type is allowed", func(t *testing.T) {
+		var sent []string
+		adapter := newAdapter(&sent)
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "bye"))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-2"), "hello"))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), &discordgo.MessageSend{Content: "hello"}))
+
+		if len(sent) != 4 {
+			t.Errorf("Expected 4 sends, got %v", sent)
+		}
+	})
+
+	t.Run("identical content is allowed after the window", func(t *testing.T) {
+		var sent []string
+		adapter := newAdapter(&sent)
+		adapter.config.DuplicateSendWindow = time.Millisecond
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+		time.Sleep(5 * time.Millisecond)
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+
+		if len(sent) != 2 {
+			t.Errorf("Expected 2 sends, got %v", sent)
+		}
+	})
+
+	t.Run("failed send is not remembered", func(t *testing.T) {
+		var attempts int
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				attempts++
+				if attempts == 1 {
+					return nil, errors.New("network error")
+				}
+				return &discordgo.Message{}, nil
+			},
+		}
+		config := NewConfig()
+		config.SuppressDuplicateSends = true
+		adapter := &Adapter{config: config, session: mock}
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+
+		if attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		var sent []string
+		adapter := newAdapter(&sent)
+		adapter.config.SuppressDuplicateSends = false
+
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+
+		if len(sent) != 2 {
+			t.Errorf("Expected 2 sends, got %v", sent)
+		}
+	})
+}
+
+func TestSendDeduplicator(t *testing.T) {
+	t.Run("entries per channel are bounded", func(t *testing.T) {
+		d := &sendDeduplicator{}
+		now := time.Now()
+		first, _ := hashContent("0")
+		d.reserve("ch-1", first, time.Hour, now)
+		for i := 1; i <= dedupEntriesPerChannel; i++ {
+			hash, _ := hashContent(string(rune('0' + i)))
+			d.reserve("ch-1", hash, time.Hour, now)
+		}
+
+		if !d.reserve("ch-1", first, time.Hour, now) {
+			t.Error("Expected the oldest entry to be evicted")
+		}
+	})
+
+	t.Run("channels are bounded", func(t *testing.T) {
+		d := &sendDeduplicator{}
+		now := time.Now()
+		hash, _ := hashContent("hello")
+		for i := 0; i <= dedupMaxChannels; i++ {
+			d.reserve(string(rune(0x4e00+i)), hash, time.Hour, now)
+		}
+
+		if len(d.channels) != dedupMaxChannels {
+			t.Errorf("Expected %d channels, got %d", dedupMaxChannels, len(d.channels))
+		}
+		if !d.reserve(string(rune(0x4e00)), hash, time.Hour, now) {
+			t.Error("Expected the least recently used channel to be evicted")
+		}
+	})
+}
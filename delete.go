@@ -0,0 +1,137 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// DeleteInput is a sarah.Input implementation that represents a deleted message.
+// This is passed to go-sarah only when Config.HandleDeletes is true.
+// Discord only tells the IDs of the deleted message, so the content and the author are only available
+// when the message is in discordgo's state cache.
+type DeleteInput struct {
+	Event     *discordgo.MessageDelete
+	senderKey string
+	text      string
+	sentAt    time.Time
+	channelID ChannelID
+	guildID   string
+	messageID string
+
+	correlationID string
+}
+
+var _ sarah.Input = (*DeleteInput)(nil)
+
+// SenderKey returns a unique key representing the author in the channel when the author is known,
+// or the channel ID otherwise.
+func (i *DeleteInput) SenderKey() string {
+	return i.senderKey
+}
+
+// Message returns the content of the deleted message, or an empty string when the content is not known.
+func (i *DeleteInput) Message() string {
+	return i.text
+}
+
+// SentAt returns when the deletion was received.
+func (i *DeleteInput) SentAt() time.Time {
+	return i.sentAt
+}
+
+// ReplyTo returns the channel where the message was deleted.
+func (i *DeleteInput) ReplyTo() sarah.OutputDestination {
+	return i.channelID
+}
+
+// MessageID returns the ID of the deleted message.
+func (i *DeleteInput) MessageID() string {
+	return i.messageID
+}
+
+// ChannelID returns the ID of the channel where the message was deleted.
+func (i *DeleteInput) ChannelID() string {
+	return string(i.channelID)
+}
+
+// GuildID returns the ID of the guild where the message was deleted, or an empty string in a direct message.
+func (i *DeleteInput) GuildID() string {
+	return i.guildID
+}
+
+// Original returns the deleted message when it is in the state cache.
+func (i *DeleteInput) Original() (*discordgo.Message, bool) {
+	return i.Event.BeforeDelete, i.Event.BeforeDelete != nil
+}
+
+// CorrelationID returns the ID that correlates the logs and outgoing messages caused by this input.
+func (i *DeleteInput) CorrelationID() string {
+	return i.correlationID
+}
+
+// MessageDeleteToInput converts the given message deletion to *DeleteInput.
+// Unlike MessageToInput, this does not require the author since Discord does not tell who wrote the deleted message.
+func MessageDeleteToInput(m *discordgo.MessageDelete) (*DeleteInput, error) {
+	if m.Message == nil || m.ID == "" {
+		return nil, errors.New("message deletion has no message ID")
+	}
+
+	input := &DeleteInput{
+		Event:     m,
+		senderKey: m.ChannelID,
+		sentAt:    time.Now(),
+		channelID: ChannelID(m.ChannelID),
+		guildID:   m.GuildID,
+		messageID: m.ID,
+
+		correlationID: newCorrelationID(),
+	}
+	if before := m.BeforeDelete; before != nil {
+		input.text = before.Content
+		if before.Author != nil {
+			input.senderKey = senderKeyOf(m.ChannelID, before.Author.ID)
+		}
+	}
+	return input, nil
+}
+
+// handleMessageDelete passes the deleted message to go-sarah.
+// Deletions received after the given context is canceled are dropped.
+func (a *Adapter) handleMessageDelete(ctx context.Context, m *discordgo.MessageDelete, enqueueInput func(sarah.Input) error) {
+	a.messageReceived()
+
+	if ctx.Err() != nil {
+		a.log().Debugf("Skipping message deletion %s received during shutdown", m.ID)
+		a.messageDropped(ctx.Err())
+		return
+	}
+
+	input, err := MessageDeleteToInput(m)
+	if err != nil {
+		a.log().Debugf("Skipping message deletion: %+v", err)
+		a.messageDropped(err)
+		return
+	}
+
+	if !a.channelAllowed(m.ChannelID) {
+		a.messageDropped(nil)
+		return
+	}
+
+	if !a.guildIDEnabled(m.GuildID) {
+		a.messageDropped(nil)
+		return
+	}
+
+	if err := enqueueInput(input); err != nil {
+		a.enqueueFailed(input, input.correlationID, "deleted message", err)
+		a.messageDropped(err)
+		return
+	}
+	a.log().Debugf("[%s] Enqueued deleted message %s in %s", input.correlationID, m.ID, m.ChannelID)
+	a.messageEnqueued()
+}
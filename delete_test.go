@@ -0,0 +1,145 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newMessageDelete() *discordgo.MessageDelete {
+	return &discordgo.MessageDelete{
+		Message: &discordgo.Message{
+			ID:        "msg-1",
+			ChannelID: "ch-1",
+			GuildID:   "guild-1",
+		},
+	}
+}
+
+func TestMessageDeleteToInput(t *testing.T) {
+	t.Run("without cached message", func(t *testing.T) {
+		input, err := MessageDeleteToInput(newMessageDelete())
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if input.MessageID() != "msg-1" || input.ChannelID() != "ch-1" || input.GuildID() != "guild-1" {
+			t.Errorf("Unexpected identifiers: %#v", input)
+		}
+		if input.Message() != "" || input.SenderKey() != "ch-1" || input.ReplyTo() != ChannelID("ch-1") {
+			t.Errorf("Unexpected input: %#v", input)
+		}
+		if _, ok := input.Original(); ok {
+			t.Error("Expected no original message")
+		}
+	})
+
+	t.Run("with cached message", func(t *testing.T) {
+		m := newMessageDelete()
+		m.BeforeDelete = &discordgo.Message{ID: "msg-1", Content: "oops", Author: &discordgo.User{ID: "user-1"}}
+
+		input, err := MessageDeleteToInput(m)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if input.Message() != "oops" || input.SenderKey() != senderKeyOf("ch-1", "user-1") {
+			t.Errorf("Unexpected input: %#v", input)
+		}
+		if original, ok := input.Original(); !ok || original.Content != "oops" {
+			t.Errorf("Unexpected original message: %#v", original)
+		}
+	})
+
+	t.Run("without message ID", func(t *testing.T) {
+		if _, err := MessageDeleteToInput(&discordgo.MessageDelete{Message: &discordgo.Message{}}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestAdapter_handleMessageDelete(t *testing.T) {
+	t.Run("identifiers are enqueued", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		var enqueued sarah.Input
+		adapter.handleMessageDelete(context.Background(), newMessageDelete(), func(input sarah.Input) error {
+			enqueued = input
+			return nil
+		})
+
+		input, ok := enqueued.(*DeleteInput)
+		if !ok {
+			t.Fatalf("Expected *DeleteInput, got %T", enqueued)
+		}
+		if input.MessageID() != "msg-1" || input.ChannelID() != "ch-1" {
+			t.Errorf("Unexpected identifiers: %#v", input)
+		}
+		if adapter.Stats().Total.Enqueued != 1 {
+			t.Errorf("Expected the deletion to be counted: %#v", adapter.Stats().Total)
+		}
+	})
+
+	t.Run("deletion in a blocked channel is dropped", func(t *testing.T) {
+		config := NewConfig()
+		config.BlockedChannels = []string{"ch-1"}
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		adapter.handleMessageDelete(context.Background(), newMessageDelete(), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+	})
+
+	t.Run("deletion in a disabled guild is dropped", func(t *testing.T) {
+		store := NewInMemoryGuildEnabledStore()
+		_ = store.SetEnabled("guild-1", false)
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}, guildEnabledStore: store}
+
+		adapter.handleMessageDelete(context.Background(), newMessageDelete(), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+	})
+
+	t.Run("deletion during shutdown is dropped", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		adapter.handleMessageDelete(ctx, newMessageDelete(), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+		if adapter.Stats().Total.Dropped != 1 {
+			t.Errorf("Expected the deletion to be counted as dropped: %#v", adapter.Stats().Total)
+		}
+	})
+
+	t.Run("handler is registered on Run only when enabled", func(t *testing.T) {
+		for _, handleDeletes := range []bool{false, true} {
+			var registered bool
+			mock := &mockSession{
+				addHandlerFunc: func(handler interface{}) func() {
+					if _, ok := handler.(func(*discordgo.Session, *discordgo.MessageDelete)); ok {
+						registered = true
+					}
+					return func() {}
+				},
+				openFunc: func() error {
+					return errors.New("stop here")
+				},
+			}
+			config := NewConfig()
+			config.HandleDeletes = handleDeletes
+			adapter := &Adapter{config: config, session: mock}
+
+			adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+			if registered != handleDeletes {
+				t.Errorf("Expected registration to be %t", handleDeletes)
+			}
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// UserID represents a Discord user as sarah.OutputDestination.
+// A message to a UserID is sent to the direct message channel with the user,
+// e.g., to reply privately to a command sent in a guild.
+type UserID string
+
+var _ sarah.OutputDestination = UserID("")
+
+// dmChannelID returns the ID of the direct message channel with the given user.
+// The channel is created on the first call and cached afterwards since the channel does not change.
+func (a *Adapter) dmChannelID(ctx context.Context, userID UserID) (ChannelID, error) {
+	if channelID, ok := a.dmChannels.get(userID); ok {
+		return channelID, nil
+	}
+
+	channel, err := a.session.UserChannelCreate(string(userID), a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create direct message channel with %s: %w", userID, err)
+	}
+
+	channelID := ChannelID(channel.ID)
+	a.dmChannels.add(userID, channelID)
+	return channelID, nil
+}
+
+// dmChannelRegistry caches the direct message channel with each user.
+// The zero value is ready to use.
+type dmChannelRegistry struct {
+	mutex    sync.RWMutex
+	channels map[UserID]ChannelID
+}
+
+func (r *dmChannelRegistry) get(userID UserID) (ChannelID, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	channelID, ok := r.channels[userID]
+	return channelID, ok
+}
+
+func (r *dmChannelRegistry) add(userID UserID, channelID ChannelID) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.channels == nil {
+		r.channels = map[UserID]ChannelID{}
+	}
+	r.channels[userID] = channelID
+}
@@ -0,0 +1,71 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_SendMessage_UserID(t *testing.T) {
+	t.Run("DM channel is created once and reused", func(t *testing.T) {
+		created := 0
+		var sentTo []string
+		mock := &mockSession{
+			userChannelCreateFunc: func(recipientID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				created++
+				return &discordgo.Channel{ID: "dm-" + recipientID}, nil
+			},
+			channelMessageSendFunc: func(channelID string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sentTo = append(sentTo, channelID)
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		for range 2 {
+			err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(UserID("user-1"), "psst"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+		}
+		err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(UserID("user-2"), "psst"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if created != 2 {
+			t.Errorf("Expected a DM channel per user, created %d", created)
+		}
+		if len(sentTo) != 3 || sentTo[0] != "dm-user-1" || sentTo[1] != "dm-user-1" || sentTo[2] != "dm-user-2" {
+			t.Errorf("Unexpected destinations: %v", sentTo)
+		}
+	})
+
+	t.Run("failed DM channel creation is not cached", func(t *testing.T) {
+		apiErr := errors.New("cannot send messages to this user")
+		calls := 0
+		mock := &mockSession{
+			userChannelCreateFunc: func(_ string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				calls++
+				return nil, apiErr
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		for range 2 {
+			err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(UserID("user-1"), "psst"))
+			if !errors.Is(err, apiErr) {
+				t.Errorf("Expected the API error, got %+v", err)
+			}
+		}
+		if calls != 2 {
+			t.Errorf("Expected the creation to be retried, called %d times", calls)
+		}
+		if adapter.Stats().Total.SendFailed != 2 {
+			t.Errorf("Expected failures to be counted: %#v", adapter.Stats().Total)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package discord
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_SendMessage_DryRun(t *testing.T) {
+	fail := func(method string) {
+		t.Errorf("%s should not be called in dry-run mode", method)
+	}
+	mock := &mockSession{
+		channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			fail("ChannelMessageSend")
+			return &discordgo.Message{}, nil
+		},
+		channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			fail("ChannelMessageSendComplex")
+			return &discordgo.Message{}, nil
+		},
+		interactionRespondFunc: func(_ *discordgo.Interaction, _ *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+			fail("InteractionRespond")
+			return nil
+		},
+		userChannelCreateFunc: func(_ string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			fail("UserChannelCreate")
+			return &discordgo.Channel{}, nil
+		},
+	}
+	logger := &capturingLogger{}
+	config := NewConfig()
+	config.DryRun = true
+	adapter := &Adapter{config: config, session: mock, logger: logger}
+
+	input, _ := InteractionToInput(newCommandInteraction("ping"))
+	outputs := []sarah.Output{
+		sarah.NewOutputMessage(ChannelID("ch-1"), "hello"),
+		sarah.NewOutputMessage(ChannelID("ch-1"), &discordgo.MessageSend{Content: "rich"}),
+		sarah.NewOutputMessage(UserID("user-1"), "private"),
+		sarah.NewOutputMessage(input.ReplyTo(), "pong"),
+	}
+	for _, output := range outputs {
+		if err := adapter.SendMessageWithError(context.Background(), output); err != nil {
+			t.Errorf("Unexpected error: %+v", err)
+		}
+	}
+
+	var logged []string
+	for _, entry := range logger.entries {
+		if strings.HasPrefix(entry, "INFO: [dry run]") {
+			logged = append(logged, entry)
+		}
+	}
+	if len(logged) != len(outputs) {
+		t.Fatalf("Expected every message to be logged: %v", logger.entries)
+	}
+	if !strings.Contains(logged[0], "ch-1") || !strings.Contains(logged[0], "hello") {
+		t.Errorf("Expected the destination and the content to be logged: %s", logged[0])
+	}
+	if !strings.Contains(logged[2], "user-1") || !strings.Contains(logged[2], "private") {
+		t.Errorf("Expected the destination and the content to be logged: %s", logged[2])
+	}
+	if sent := adapter.Stats().Total.SendSucceeded; sent != uint64(len(outputs)) {
+		t.Errorf("Expected every logged message to be counted as sent, got %d", sent)
+	}
+}
+
+func TestAdapter_EditMessage_DryRun(t *testing.T) {
+	mock := &mockSession{
+		channelMessageEditFunc: func(_ string, _ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			t.Error("ChannelMessageEdit should not be called in dry-run mode")
+			return &discordgo.Message{}, nil
+		},
+	}
+	logger := &capturingLogger{}
+	config := NewConfig()
+	config.DryRun = true
+	adapter := &Adapter{config: config, session: mock, logger: logger}
+
+	if err := adapter.EditMessage(context.Background(), ChannelID("ch-1"), "msg-1", "edited"); err != nil {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+	if !logger.has("INFO", "[dry run] Edit message msg-1 in ch-1: edited") {
+		t.Errorf("Expected the edit to be logged: %v", logger.entries)
+	}
+}
+
+func TestAdapter_DryRun_InternalSends(t *testing.T) {
+	setup := func(t *testing.T) (*Adapter, *capturingLogger) {
+		fail := func(method string) {
+			t.Errorf("%s should not be called in dry-run mode", method)
+		}
+		mock := &mockSession{
+			channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				fail("ChannelMessageSend")
+				return &discordgo.Message{}, nil
+			},
+			channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				fail("ChannelMessageSendComplex")
+				return &discordgo.Message{}, nil
+			},
+			channelEditComplexFunc: func(_ string, _ *discordgo.ChannelEdit, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				fail("ChannelEditComplex")
+				return &discordgo.Channel{}, nil
+			},
+			interactionRespondFunc: func(_ *discordgo.Interaction, _ *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+				fail("InteractionRespond")
+				return nil
+			},
+			guildFunc: func(guildID string, _ ...discordgo.RequestOption) (*discordgo.Guild, error) {
+				return &discordgo.Guild{ID: guildID, OwnerID: "user-1"}, nil
+			},
+		}
+		logger := &capturingLogger{}
+		config := NewConfig()
+		config.DryRun = true
+		return &Adapter{config: config, session: mock, logger: logger}, logger
+	}
+	s := &discordgo.Session{State: discordgo.NewState()}
+	newMessage := func(content string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ID:        "msg-1",
+				ChannelID: "ch-1",
+				GuildID:   "guild-1",
+				Content:   content,
+				Author:    &discordgo.User{ID: "user-1"},
+			},
+		}
+	}
+	enqueue := func(sarah.Input) error { return nil }
+
+	t.Run("auto response", func(t *testing.T) {
+		adapter, logger := setup(t)
+		adapter.config.AutoResponses = map[*regexp.Regexp]string{regexp.MustCompile(`docs`): "Docs are here."}
+
+		adapter.handleMessage(context.Background(), s, newMessage("docs"), enqueue)
+
+		if !logger.has("INFO", "[dry run] Message to ch-1: Docs are here.") {
+			t.Errorf("Expected the auto response to be logged: %v", logger.entries)
+		}
+	})
+
+	t.Run("guild toggle reply", func(t *testing.T) {
+		adapter, logger := setup(t)
+		adapter.config.EnableGuildToggle = true
+		adapter.guildEnabledStore = NewInMemoryGuildEnabledStore()
+
+		adapter.handleMessage(context.Background(), s, newMessage("/disable"), enqueue)
+
+		if !logger.has("INFO", "[dry run] Message to ch-1: The bot is now disabled in this server.") {
+			t.Errorf("Expected the reply to be logged: %v", logger.entries)
+		}
+	})
+
+	t.Run("thread closing", func(t *testing.T) {
+		adapter, logger := setup(t)
+		adapter.config.ArchiveThreadOnCompletion = true
+		adapter.config.ThreadClosingMessage = "Closing this thread."
+		adapter.threads.add("ch-1")
+
+		adapter.handleMessage(context.Background(), s, newMessage(adapter.config.AbortCommand), enqueue)
+
+		if !logger.has("INFO", "[dry run] Message to ch-1: Closing this thread.") {
+			t.Errorf("Expected the closing message to be logged: %v", logger.entries)
+		}
+		if !logger.has("INFO", "[dry run] Archive thread ch-1") {
+			t.Errorf("Expected the archival to be logged: %v", logger.entries)
+		}
+	})
+
+	t.Run("help menu selection", func(t *testing.T) {
+		adapter, logger := setup(t)
+		menuID := adapter.helpMenus.add(newHelps(1))
+
+		adapter.handleHelpMenuSelection(context.Background(), newSelection(helpMenuCustomIDPrefix+menuID+":0", "0"))
+
+		if !logger.has("INFO", "[dry run] Message to") {
+			t.Errorf("Expected the answer to be logged: %v", logger.entries)
+		}
+	})
+}
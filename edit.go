@@ -0,0 +1,156 @@
+package discord
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// EditInput is a sarah.Input implementation that represents an edited message.
+// This is passed to go-sarah only when Config.HandleEdits is true.
+// The message is the edited content as it is, so commands see the content without the prefix or the mention stripped.
+type EditInput struct {
+	Event     *discordgo.MessageUpdate
+	senderKey string
+	text      string
+	sentAt    time.Time
+	channelID ChannelID
+	guildID   string
+	messageID string
+
+	originalContent string
+	hasOriginal     bool
+	correlationID   string
+}
+
+var _ sarah.Input = (*EditInput)(nil)
+
+// SenderKey returns a unique key representing the author in the channel.
+func (i *EditInput) SenderKey() string {
+	return i.senderKey
+}
+
+// Message returns the edited content.
+func (i *EditInput) Message() string {
+	return i.text
+}
+
+// SentAt returns when the message was edited.
+func (i *EditInput) SentAt() time.Time {
+	return i.sentAt
+}
+
+// ReplyTo returns the channel where the message was edited.
+func (i *EditInput) ReplyTo() sarah.OutputDestination {
+	return i.channelID
+}
+
+// MessageID returns the ID of the edited message.
+func (i *EditInput) MessageID() string {
+	return i.messageID
+}
+
+// GuildID returns the ID of the guild where the message was edited, or an empty string in a direct message.
+func (i *EditInput) GuildID() string {
+	return i.guildID
+}
+
+// OriginalContent returns the content before the edit.
+// The original content is only available when the message is in the state cache, so false is returned otherwise.
+func (i *EditInput) OriginalContent() (string, bool) {
+	return i.originalContent, i.hasOriginal
+}
+
+// CorrelationID returns the ID that correlates the logs and outgoing messages caused by this input.
+func (i *EditInput) CorrelationID() string {
+	return i.correlationID
+}
+
+// MessageUpdateToInput converts the given message update to *EditInput.
+func MessageUpdateToInput(m *discordgo.MessageUpdate) (*EditInput, error) {
+	if m.Message == nil || m.Author == nil {
+		return nil, ErrNoAuthor
+	}
+
+	sentAt := time.Now()
+	if m.EditedTimestamp != nil {
+		sentAt = *m.EditedTimestamp
+	}
+
+	input := &EditInput{
+		Event:     m,
+		senderKey: senderKeyOf(m.ChannelID, m.Author.ID),
+		text:      m.Content,
+		sentAt:    sentAt,
+		channelID: ChannelID(m.ChannelID),
+		guildID:   m.GuildID,
+		messageID: m.ID,
+
+		correlationID: newCorrelationID(),
+	}
+	if m.BeforeUpdate != nil {
+		input.originalContent = m.BeforeUpdate.Content
+		input.hasOriginal = true
+	}
+	return input, nil
+}
+
+// handleMessageUpdate passes the edited message to go-sarah.
+// Updates that are not edits by the author, e.g., link previews being attached, are ignored,
+// and so are edits received after the given context is canceled.
+func (a *Adapter) handleMessageUpdate(ctx context.Context, s *discordgo.Session, m *discordgo.MessageUpdate, enqueueInput func(sarah.Input) error) {
+	if m.Message == nil || m.EditedTimestamp == nil {
+		return
+	}
+
+	a.messageReceived()
+
+	if ctx.Err() != nil {
+		a.log().Debugf("Skipping edited message %s received during shutdown", m.ID)
+		a.messageDropped(ctx.Err())
+		return
+	}
+
+	input, err := MessageUpdateToInput(m)
+	if err != nil {
+		a.log().Debugf("Skipping message update: %+v", err)
+		a.messageDropped(err)
+		return
+	}
+
+	if botID := a.selfID(s); botID != "" && m.Author.ID == botID {
+		a.messageDropped(nil)
+		return
+	}
+
+	if a.userBlocked(m.Author.ID) {
+		a.log().Debugf("[%s] Ignoring edited message %s from blocked user %s", input.correlationID, m.ID, m.Author.ID)
+		a.messageDropped(nil)
+		return
+	}
+
+	if a.config.IgnoreBots && m.Author.Bot {
+		a.messageDropped(nil)
+		return
+	}
+
+	if !a.channelAllowed(m.ChannelID) {
+		a.messageDropped(nil)
+		return
+	}
+
+	if !a.guildIDEnabled(m.GuildID) {
+		a.messageDropped(nil)
+		return
+	}
+
+	if err := enqueueInput(input); err != nil {
+		a.enqueueFailed(input, input.correlationID, "edited message", err)
+		a.messageDropped(err)
+		return
+	}
+	a.log().Debugf("[%s] Enqueued edited message %s from %s", input.correlationID, m.ID, input.senderKey)
+	a.messageEnqueued()
+}
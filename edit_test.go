@@ -0,0 +1,163 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newMessageUpdate(authorID string, content string) *discordgo.MessageUpdate {
+	edited := time.Unix(100, 0)
+	return &discordgo.MessageUpdate{
+		Message: &discordgo.Message{
+			ID:              "msg-1",
+			ChannelID:       "ch-1",
+			GuildID:         "guild-1",
+			Content:         content,
+			Author:          &discordgo.User{ID: authorID},
+			EditedTimestamp: &edited,
+		},
+	}
+}
+
+func TestMessageUpdateToInput(t *testing.T) {
+	t.Run("with original content", func(t *testing.T) {
+		m := newMessageUpdate("user-1", "hello, world")
+		m.BeforeUpdate = &discordgo.Message{Content: "helo, world"}
+
+		input, err := MessageUpdateToInput(m)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if input.Message() != "hello, world" || input.MessageID() != "msg-1" || input.GuildID() != "guild-1" {
+			t.Errorf("Unexpected input: %#v", input)
+		}
+		if original, ok := input.OriginalContent(); !ok || original != "helo, world" {
+			t.Errorf("Unexpected original content: %q", original)
+		}
+		if input.SenderKey() != senderKeyOf("ch-1", "user-1") || input.ReplyTo() != ChannelID("ch-1") {
+			t.Errorf("Unexpected sender or destination: %s, %#v", input.SenderKey(), input.ReplyTo())
+		}
+		if !input.SentAt().Equal(time.Unix(100, 0)) {
+			t.Errorf("Expected the edited time, got %s", input.SentAt())
+		}
+	})
+
+	t.Run("without original content", func(t *testing.T) {
+		input, err := MessageUpdateToInput(newMessageUpdate("user-1", "hello"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if _, ok := input.OriginalContent(); ok {
+			t.Error("Expected no original content")
+		}
+	})
+
+	t.Run("without author", func(t *testing.T) {
+		m := newMessageUpdate("user-1", "hello")
+		m.Author = nil
+
+		if _, err := MessageUpdateToInput(m); !errors.Is(err, ErrNoAuthor) {
+			t.Errorf("Expected ErrNoAuthor, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_handleMessageUpdate(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	notEdited := newMessageUpdate("user-1", "hello")
+	notEdited.EditedTimestamp = nil
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		update   *discordgo.MessageUpdate
+		ctx      context.Context
+		config   func(*Config)
+		enqueued bool
+	}{
+		{name: "edit by user", update: newMessageUpdate("user-1", "hello"), enqueued: true},
+		{name: "edit by the bot itself", update: newMessageUpdate("bot-1", "hello"), enqueued: false},
+		{name: "update without edit", update: notEdited, enqueued: false},
+		{name: "edit in a blocked channel", update: newMessageUpdate("user-1", "hello"), config: func(c *Config) { c.BlockedChannels = []string{"ch-1"} }, enqueued: false},
+		{name: "edit by a blocked user", update: newMessageUpdate("user-1", "hello"), config: func(c *Config) { c.BlockedUsers = []string{"user-1"} }, enqueued: false},
+		{name: "edit during shutdown", update: newMessageUpdate("user-1", "hello"), ctx: canceled, enqueued: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.HandleEdits = true
+			if tt.config != nil {
+				tt.config(config)
+			}
+			adapter := &Adapter{config: config, session: &mockSession{}}
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			var enqueued sarah.Input
+			adapter.handleMessageUpdate(ctx, s, tt.update, func(input sarah.Input) error {
+				enqueued = input
+				return nil
+			})
+
+			if (enqueued != nil) != tt.enqueued {
+				t.Fatalf("Expected enqueued to be %t", tt.enqueued)
+			}
+			if enqueued != nil {
+				if _, ok := enqueued.(*EditInput); !ok {
+					t.Errorf("Expected *EditInput, got %T", enqueued)
+				}
+			}
+		})
+	}
+
+	t.Run("edit in a disabled guild", func(t *testing.T) {
+		config := NewConfig()
+		config.HandleEdits = true
+		store := NewInMemoryGuildEnabledStore()
+		_ = store.SetEnabled("guild-1", false)
+		adapter := &Adapter{config: config, session: &mockSession{}, guildEnabledStore: store}
+
+		adapter.handleMessageUpdate(context.Background(), s, newMessageUpdate("user-1", "hello"), func(sarah.Input) error {
+			t.Error("Expected the edit not to be enqueued")
+			return nil
+		})
+	})
+
+	t.Run("handler is registered on Run only when enabled", func(t *testing.T) {
+		for _, handleEdits := range []bool{false, true} {
+			var registered bool
+			mock := &mockSession{
+				addHandlerFunc: func(handler interface{}) func() {
+					if _, ok := handler.(func(*discordgo.Session, *discordgo.MessageUpdate)); ok {
+						registered = true
+					}
+					return func() {}
+				},
+				openFunc: func() error {
+					return errors.New("stop here")
+				},
+			}
+			config := NewConfig()
+			config.HandleEdits = handleEdits
+			adapter := &Adapter{config: config, session: mock}
+
+			adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+			if registered != handleEdits {
+				t.Errorf("Expected registration to be %t", handleEdits)
+			}
+		}
+	})
+}
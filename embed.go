@@ -0,0 +1,74 @@
+package discord
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxEmbedsPerMessage is the maximum number of embeds Discord accepts in a single message.
+const MaxEmbedsPerMessage = 10
+
+// splitEmbeds splits the given message into messages that have up to limit embeds each, preserving the order.
+// The first message keeps everything else such as the content, files, and components,
+// while the following messages only carry the rest of the embeds.
+// The given message is returned as it is when it does not exceed the limit.
+func splitEmbeds(data *discordgo.MessageSend, limit int) []*discordgo.MessageSend {
+	if limit <= 0 || limit > MaxEmbedsPerMessage {
+		limit = MaxEmbedsPerMessage
+	}
+
+	if len(data.Embeds) <= limit {
+		return []*discordgo.MessageSend{data}
+	}
+
+	first := *data
+	first.Embeds = data.Embeds[:limit]
+	messages := []*discordgo.MessageSend{&first}
+
+	for rest := data.Embeds[limit:]; len(rest) > 0; {
+		n := min(limit, len(rest))
+		messages = append(messages, &discordgo.MessageSend{
+			Embeds:          rest[:n],
+			AllowedMentions: data.AllowedMentions,
+		})
+		rest = rest[n:]
+	}
+
+	return messages
+}
+
+// RespWithEmbeds attaches the given embeds to the response.
+// The text content, if any, is sent along with the embeds in the same message.
+// NewResponse returns ErrTooManyEmbeds when the response has more than MaxEmbedsPerMessage embeds in total.
+func RespWithEmbeds(embeds ...*discordgo.MessageEmbed) RespOption {
+	return func(options *respOptions) {
+		options.embeds = append(options.embeds, embeds...)
+	}
+}
+
+// withEmbeds converts the given response content to a *discordgo.MessageSend with the given embeds.
+// The given *discordgo.MessageSend is copied so the caller's value is not modified.
+func withEmbeds(content any, embeds []*discordgo.MessageEmbed) (any, error) {
+	switch typed := content.(type) {
+	case string:
+		if len(embeds) > MaxEmbedsPerMessage {
+			return nil, fmt.Errorf("%w: %d", ErrTooManyEmbeds, len(embeds))
+		}
+		return &discordgo.MessageSend{
+			Content: typed,
+			Embeds:  embeds,
+		}, nil
+
+	case *discordgo.MessageSend:
+		if n := len(typed.Embeds) + len(embeds); n > MaxEmbedsPerMessage {
+			return nil, fmt.Errorf("%w: %d", ErrTooManyEmbeds, n)
+		}
+		copied := *typed
+		copied.Embeds = append(append([]*discordgo.MessageEmbed{}, typed.Embeds...), embeds...)
+		return &copied, nil
+
+	default:
+		return content, nil
+	}
+}
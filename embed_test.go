@@ -0,0 +1,145 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newEmbeds(n int) []*discordgo.MessageEmbed {
+	embeds := make([]*discordgo.MessageEmbed, n)
+	for i := range embeds {
+		embeds[i] = &discordgo.MessageEmbed{Title: strconv.Itoa(i)}
+	}
+	return embeds
+}
+
+func TestAdapter_SendMessage_EmbedSpillover(t *testing.T) {
+	var sent []*discordgo.MessageSend
+	mock := &mockSession{
+		channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, data)
+			return &discordgo.Message{}, nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+
+	content := &discordgo.MessageSend{
+		Content: "results",
+		Embeds:  newEmbeds(23),
+		Files:   []*discordgo.File{{Name: "report.txt"}},
+	}
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), content))
+
+	if len(sent) != 3 {
+		t.Fatalf("Expected 3 messages, got %d", len(sent))
+	}
+
+	expectedCounts := []int{10, 10, 3}
+	index := 0
+	for i, msg := range sent {
+		if len(msg.Embeds) != expectedCounts[i] {
+			t.Errorf("Expected %d embeds in message %d, got %d", expectedCounts[i], i, len(msg.Embeds))
+		}
+		for _, embed := range msg.Embeds {
+			if embed.Title != strconv.Itoa(index) {
+				t.Errorf("Expected embed %d, got %s", index, embed.Title)
+			}
+			index++
+		}
+	}
+
+	if sent[0].Content != "results" || len(sent[0].Files) != 1 {
+		t.Errorf("Expected the content and files on the first message: %#v", sent[0])
+	}
+	for _, msg := range sent[1:] {
+		if msg.Content != "" || len(msg.Files) != 0 {
+			t.Errorf("Expected only embeds on the following messages: %#v", msg)
+		}
+	}
+
+	if len(content.Embeds) != 23 {
+		t.Error("Expected the original message to be kept")
+	}
+}
+
+func TestSplitEmbeds(t *testing.T) {
+	tests := []struct {
+		name     string
+		embeds   int
+		limit    int
+		expected []int
+	}{
+		{name: "no embed", embeds: 0, limit: 10, expected: []int{0}},
+		{name: "within limit", embeds: 10, limit: 10, expected: []int{10}},
+		{name: "exceeds limit", embeds: 11, limit: 10, expected: []int{10, 1}},
+		{name: "custom limit", embeds: 5, limit: 2, expected: []int{2, 2, 1}},
+		{name: "invalid limit", embeds: 12, limit: 0, expected: []int{10, 2}},
+		{name: "limit over Discord's", embeds: 12, limit: 20, expected: []int{10, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := splitEmbeds(&discordgo.MessageSend{Embeds: newEmbeds(tt.embeds)}, tt.limit)
+
+			if len(messages) != len(tt.expected) {
+				t.Fatalf("Expected %d messages, got %d", len(tt.expected), len(messages))
+			}
+			for i, msg := range messages {
+				if len(msg.Embeds) != tt.expected[i] {
+					t.Errorf("Expected %d embeds in message %d, got %d", tt.expected[i], i, len(msg.Embeds))
+				}
+			}
+		})
+	}
+}
+
+func TestRespWithEmbeds(t *testing.T) {
+	t.Run("single embed with content", func(t *testing.T) {
+		response, err := NewResponse(newReplyInput("guild-1"), "status", RespWithEmbeds(&discordgo.MessageEmbed{Title: "OK"}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		data, ok := response.Content.(*discordgo.MessageSend)
+		if !ok {
+			t.Fatalf("Expected *discordgo.MessageSend, got %T", response.Content)
+		}
+		if data.Content != "status" || len(data.Embeds) != 1 || data.Embeds[0].Title != "OK" {
+			t.Errorf("Unexpected message: %#v", data)
+		}
+	})
+
+	t.Run("multiple embeds are appended to the existing ones", func(t *testing.T) {
+		given := &discordgo.MessageSend{Content: "status", Embeds: newEmbeds(1)}
+		response, err := NewResponse(newReplyInput("guild-1"), given, RespWithEmbeds(newEmbeds(3)...))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		data := response.Content.(*discordgo.MessageSend)
+		if len(data.Embeds) != 4 || data.Content != "status" {
+			t.Errorf("Unexpected message: %#v", data)
+		}
+		if len(given.Embeds) != 1 {
+			t.Error("The given message should not be modified")
+		}
+	})
+
+	t.Run("over the limit", func(t *testing.T) {
+		_, err := NewResponse(newReplyInput("guild-1"), "status", RespWithEmbeds(newEmbeds(MaxEmbedsPerMessage+1)...))
+		if !errors.Is(err, ErrTooManyEmbeds) {
+			t.Errorf("Expected ErrTooManyEmbeds, got %+v", err)
+		}
+
+		given := &discordgo.MessageSend{Embeds: newEmbeds(MaxEmbedsPerMessage)}
+		_, err = NewResponse(newReplyInput("guild-1"), given, RespWithEmbeds(newEmbeds(1)...))
+		if !errors.Is(err, ErrTooManyEmbeds) {
+			t.Errorf("Expected ErrTooManyEmbeds in total, got %+v", err)
+		}
+	})
+}
@@ -0,0 +1,237 @@
+package discord
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// emojiCacheTTL is the duration in which the fetched emoji list of a guild or the application is reused.
+const emojiCacheTTL = 10 * time.Minute
+
+var (
+	// customEmojiPattern matches a whole custom emoji in the message format such as "<:name:id>" or "<a:name:id>".
+	customEmojiPattern = regexp.MustCompile(`^<(a?):([A-Za-z0-9_~]+):([0-9]+)>$`)
+
+	// emojiAPINamePattern matches a custom emoji in the form of "name:id" or "a:name:id".
+	emojiAPINamePattern = regexp.MustCompile(`^(a:)?([A-Za-z0-9_~]+):([0-9]+)$`)
+
+	// emojiNamePattern matches a custom emoji name optionally surrounded by colons such as ":name:".
+	emojiNamePattern = regexp.MustCompile(`^:?([A-Za-z0-9_~]{2,32}):?$`)
+
+	// emojiShortcodePattern finds ":name:" in text, excluding the ones that are part of "<:name:id>" or words like "12:30:45".
+	emojiShortcodePattern = regexp.MustCompile(`(?:^|[^<\w]):([A-Za-z0-9_~]{2,32}):`)
+)
+
+// ResolveEmoji returns the given emoji in the message format, which is "<:name:id>" for a custom emoji.
+//
+// The emoji is one of the following:
+//
+//   - A custom emoji in the message format, which is returned as-is.
+//   - A custom emoji in the form of "name:id", which is converted to the message format.
+//   - A custom emoji name such as "name" or ":name:", which is looked up in the given guild and then in the application's emojis.
+//   - Anything else, such as a unicode emoji, which is returned as-is.
+//
+// An error wrapping ErrUnknownEmoji is returned when the name is not found, so the caller does not send text that Discord renders as-is.
+// Leave guildID empty to only look up the application's emojis.
+func (a *Adapter) ResolveEmoji(guildID string, nameOrToken string) (string, error) {
+	emoji := strings.TrimSpace(nameOrToken)
+
+	if customEmojiPattern.MatchString(emoji) {
+		return emoji, nil
+	}
+
+	if match := emojiAPINamePattern.FindStringSubmatch(emoji); match != nil {
+		return (&discordgo.Emoji{Name: match[2], ID: match[3], Animated: match[1] != ""}).MessageFormat(), nil
+	}
+
+	match := emojiNamePattern.FindStringSubmatch(emoji)
+	if match == nil {
+		return emoji, nil
+	}
+
+	found, err := a.lookupEmoji(guildID, match[1])
+	if err != nil {
+		return "", err
+	}
+	return found.MessageFormat(), nil
+}
+
+// lookupEmoji looks up the custom emoji with the given name in the guild and then in the application's emojis.
+func (a *Adapter) lookupEmoji(guildID string, name string) (*discordgo.Emoji, error) {
+	if guildID != "" {
+		emojis, err := a.emojis.get(guildID, time.Now(), func() ([]*discordgo.Emoji, error) {
+			if state := a.state(); state != nil {
+				if guild, err := state.Guild(guildID); err == nil && len(guild.Emojis) > 0 {
+					return guild.Emojis, nil
+				}
+			}
+			return a.session.GuildEmojis(guildID, a.requestOptions()...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch emojis of guild %s: %w", guildID, err)
+		}
+		if emoji := findEmoji(emojis, name); emoji != nil {
+			return emoji, nil
+		}
+	}
+
+	if appID := a.applicationID(); appID != "" {
+		emojis, err := a.emojis.get(applicationEmojiCacheKey(appID), time.Now(), func() ([]*discordgo.Emoji, error) {
+			return a.session.ApplicationEmojis(appID, a.requestOptions()...)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch emojis of application %s: %w", appID, err)
+		}
+		if emoji := findEmoji(emojis, name); emoji != nil {
+			return emoji, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnknownEmoji, name)
+}
+
+// applicationID returns the bot's application ID, which is also the bot user's ID.
+func (a *Adapter) applicationID() string {
+	if id, ok := a.appID.Load().(string); ok && id != "" {
+		return id
+	}
+
+	if state := a.state(); state != nil {
+		state.RLock()
+		defer state.RUnlock()
+		if state.Application != nil && state.Application.ID != "" {
+			return state.Application.ID
+		}
+		if state.User != nil {
+			return state.User.ID
+		}
+	}
+
+	return ""
+}
+
+// handleReady remembers the application ID to look up the application's emojis.
+func (a *Adapter) handleReady(r *discordgo.Ready) {
+	if r.Application != nil && r.Application.ID != "" {
+		a.appID.Store(r.Application.ID)
+	}
+}
+
+// reactionEmoji returns the given emoji in the form that the reaction endpoints accept.
+// Custom emoji names are resolved in the guild of the given channel.
+func (a *Adapter) reactionEmoji(channelID string, emoji string) (string, error) {
+	trimmed := strings.TrimSpace(emoji)
+	if match := customEmojiPattern.FindStringSubmatch(trimmed); match != nil {
+		return match[2] + ":" + match[3], nil
+	}
+	if match := emojiAPINamePattern.FindStringSubmatch(trimmed); match != nil {
+		return match[2] + ":" + match[3], nil
+	}
+	if !emojiNamePattern.MatchString(trimmed) {
+		return emoji, nil
+	}
+
+	resolved, err := a.ResolveEmoji(a.guildIDOf(channelID), trimmed)
+	if err != nil {
+		return "", err
+	}
+	match := customEmojiPattern.FindStringSubmatch(resolved)
+	return match[2] + ":" + match[3], nil
+}
+
+// resolveContentEmojis replaces ":name:" in the given text with the custom emoji available in the guild of the given channel.
+func (a *Adapter) resolveContentEmojis(channelID string, text string) (string, error) {
+	matches := emojiShortcodePattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	guildID := a.guildIDOf(channelID)
+	var builder strings.Builder
+	last := 0
+	for _, match := range matches {
+		// match[2] and match[3] point to the name, which is surrounded by colons.
+		start, end := match[2]-1, match[3]+1
+		emoji, err := a.ResolveEmoji(guildID, text[start:end])
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(text[last:start])
+		builder.WriteString(emoji)
+		last = end
+	}
+	builder.WriteString(text[last:])
+
+	return builder.String(), nil
+}
+
+// guildIDOf returns the ID of the guild that the given channel belongs to, or an empty string for a direct message.
+func (a *Adapter) guildIDOf(channelID string) string {
+	channel, err := a.channel(channelID)
+	if err != nil {
+		return ""
+	}
+	return channel.GuildID
+}
+
+func findEmoji(emojis []*discordgo.Emoji, name string) *discordgo.Emoji {
+	for _, emoji := range emojis {
+		if emoji != nil && emoji.Name == name && emoji.ID != "" {
+			return emoji
+		}
+	}
+	return nil
+}
+
+func applicationEmojiCacheKey(appID string) string {
+	return "application:" + appID
+}
+
+// emojiCache caches the emoji lists of guilds and the application.
+// The zero value is ready to use.
+type emojiCache struct {
+	mutex   sync.Mutex
+	entries map[string]*emojiCacheEntry
+}
+
+type emojiCacheEntry struct {
+	emojis    []*discordgo.Emoji
+	fetchedAt time.Time
+}
+
+// get returns the cached emojis for the given key, or fetches and caches them when not cached or expired.
+func (c *emojiCache) get(key string, now time.Time, fetch func() ([]*discordgo.Emoji, error)) ([]*discordgo.Emoji, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	c.mutex.Unlock()
+	if ok && now.Sub(entry.fetchedAt) < emojiCacheTTL {
+		return entry.emojis, nil
+	}
+
+	emojis, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]*emojiCacheEntry{}
+	}
+	c.entries[key] = &emojiCacheEntry{emojis: emojis, fetchedAt: now}
+
+	return emojis, nil
+}
+
+// forget discards the cached emojis for the given key.
+func (c *emojiCache) forget(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.entries, key)
+}
@@ -0,0 +1,216 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newEmojiSession(fetches *int) *mockSession {
+	return &mockSession{
+		guildEmojisFunc: func(guildID string, _ ...discordgo.RequestOption) ([]*discordgo.Emoji, error) {
+			*fetches++
+			if guildID != "guild-1" {
+				return nil, nil
+			}
+			return []*discordgo.Emoji{
+				{ID: "111111111111111111", Name: "sarah"},
+				{ID: "222222222222222222", Name: "party", Animated: true},
+			}, nil
+		},
+		applicationEmojisFunc: func(appID string, _ ...discordgo.RequestOption) ([]*discordgo.Emoji, error) {
+			if appID != "app-1" {
+				return nil, errors.New("unexpected application")
+			}
+			return []*discordgo.Emoji{{ID: "333333333333333333", Name: "bot_logo"}}, nil
+		},
+		channelFunc: func(channelID string, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+			if channelID == "dm-1" {
+				return &discordgo.Channel{ID: channelID, Type: discordgo.ChannelTypeDM}, nil
+			}
+			return &discordgo.Channel{ID: channelID, GuildID: "guild-1"}, nil
+		},
+	}
+}
+
+func TestAdapter_ResolveEmoji(t *testing.T) {
+	tests := []struct {
+		name     string
+		guildID  string
+		emoji    string
+		expected string
+		err      error
+	}{
+		{name: "unicode", guildID: "guild-1", emoji: "👍", expected: "👍"},
+		{name: "message format", guildID: "guild-1", emoji: "<:custom:444444444444444444>", expected: "<:custom:444444444444444444>"},
+		{name: "name and ID", guildID: "guild-1", emoji: "custom:444444444444444444", expected: "<:custom:444444444444444444>"},
+		{name: "animated name and ID", guildID: "guild-1", emoji: "a:custom:444444444444444444", expected: "<a:custom:444444444444444444>"},
+		{name: "guild emoji name", guildID: "guild-1", emoji: "sarah", expected: "<:sarah:111111111111111111>"},
+		{name: "guild emoji shortcode", guildID: "guild-1", emoji: ":party:", expected: "<a:party:222222222222222222>"},
+		{name: "application emoji", guildID: "guild-1", emoji: ":bot_logo:", expected: "<:bot_logo:333333333333333333>"},
+		{name: "application emoji without guild", guildID: "", emoji: "bot_logo", expected: "<:bot_logo:333333333333333333>"},
+		{name: "unknown name", guildID: "guild-1", emoji: ":unknown:", err: ErrUnknownEmoji},
+		{name: "guild emoji without guild", guildID: "", emoji: "sarah", err: ErrUnknownEmoji},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetches := 0
+			adapter := &Adapter{config: NewConfig(), session: newEmojiSession(&fetches)}
+			adapter.appID.Store("app-1")
+
+			resolved, err := adapter.ResolveEmoji(tt.guildID, tt.emoji)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Expected %v, got %+v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if resolved != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, resolved)
+			}
+		})
+	}
+
+	t.Run("emojis are cached", func(t *testing.T) {
+		fetches := 0
+		adapter := &Adapter{config: NewConfig(), session: newEmojiSession(&fetches)}
+
+		for range 3 {
+			if _, err := adapter.ResolveEmoji("guild-1", "sarah"); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+		}
+
+		if fetches != 1 {
+			t.Errorf("Expected emojis to be fetched once, got %d", fetches)
+		}
+	})
+
+	t.Run("fetch failure", func(t *testing.T) {
+		mock := &mockSession{
+			guildEmojisFunc: func(_ string, _ ...discordgo.RequestOption) ([]*discordgo.Emoji, error) {
+				return nil, errors.New("forbidden")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		_, err := adapter.ResolveEmoji("guild-1", "sarah")
+		if err == nil || errors.Is(err, ErrUnknownEmoji) {
+			t.Errorf("Expected fetch error, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_handleReady(t *testing.T) {
+	adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+	adapter.handleReady(&discordgo.Ready{Application: &discordgo.Application{ID: "app-1"}})
+
+	if adapter.applicationID() != "app-1" {
+		t.Errorf("Expected application ID to be stored, got %q", adapter.applicationID())
+	}
+}
+
+func TestAdapter_AddReactions_EmojiName(t *testing.T) {
+	var reacted []string
+	fetches := 0
+	mock := newEmojiSession(&fetches)
+	mock.messageReactionAddFunc = func(_ string, _ string, emojiID string, _ ...discordgo.RequestOption) error {
+		reacted = append(reacted, emojiID)
+		return nil
+	}
+	config := NewConfig()
+	config.ReactionInterval = time.Millisecond
+	adapter := &Adapter{config: config, session: mock}
+
+	err := adapter.AddReactions(context.Background(), ChannelID("ch-1"), "msg-1", "👍", "sarah", "<a:party:222222222222222222>")
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	expected := []string{"👍", "sarah:111111111111111111", "party:222222222222222222"}
+	if len(reacted) != len(expected) {
+		t.Fatalf("Unexpected reactions: %#v", reacted)
+	}
+	for i := range expected {
+		if reacted[i] != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], reacted[i])
+		}
+	}
+
+	err = adapter.AddReactions(context.Background(), ChannelID("ch-1"), "msg-1", "unknown")
+	if !errors.Is(err, ErrUnknownEmoji) {
+		t.Errorf("Expected ErrUnknownEmoji, got %+v", err)
+	}
+}
+
+func TestAdapter_SendMessage_ResolveEmojiInContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		sent     bool
+	}{
+		{name: "shortcode", content: "Hello :sarah:!", expected: "Hello <:sarah:111111111111111111>!", sent: true},
+		{name: "existing token and time", content: "<:sarah:111111111111111111> at 12:30:45", expected: "<:sarah:111111111111111111> at 12:30:45", sent: true},
+		{name: "unknown name", content: "Hello :unknown:", sent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			fetches := 0
+			mock := newEmojiSession(&fetches)
+			mock.channelMessageSendFunc = func(_ string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sent = append(sent, content)
+				return &discordgo.Message{}, nil
+			}
+			config := NewConfig()
+			config.ResolveEmojiInContent = true
+			adapter := &Adapter{config: config, session: mock}
+
+			adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), tt.content))
+
+			if !tt.sent {
+				if len(sent) != 0 {
+					t.Errorf("Unexpected message: %#v", sent)
+				}
+				return
+			}
+			if len(sent) != 1 || sent[0] != tt.expected {
+				t.Errorf("Expected %q, got %#v", tt.expected, sent)
+			}
+		})
+	}
+
+	t.Run("rich content is copied", func(t *testing.T) {
+		var sent *discordgo.MessageSend
+		fetches := 0
+		mock := newEmojiSession(&fetches)
+		mock.channelMessageSendComplexFunc = func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = data
+			return &discordgo.Message{}, nil
+		}
+		config := NewConfig()
+		config.ResolveEmojiInContent = true
+		adapter := &Adapter{config: config, session: mock}
+
+		original := &discordgo.MessageSend{Content: ":sarah:"}
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), original))
+
+		if sent == nil || sent.Content != "<:sarah:111111111111111111>" {
+			t.Errorf("Unexpected content: %#v", sent)
+		}
+		if original.Content != ":sarah:" {
+			t.Errorf("Original content should not be modified: %q", original.Content)
+		}
+	})
+}
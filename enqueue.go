@@ -0,0 +1,24 @@
+package discord
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// WithEnqueueErrorHandler creates an AdapterOption that observes the inputs that failed to be enqueued, e.g., when go-sarah's queue is full,
+// so operators can alert on the drops or apply backpressure.
+// The handler is called in place of the default error log, from discordgo's event handlers, so it must not block.
+func WithEnqueueErrorHandler(handler func(sarah.Input, error)) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.enqueueErrorHandler = handler
+	}
+}
+
+// enqueueFailed reports the input that failed to be enqueued to the handler given via WithEnqueueErrorHandler, or logs the failure by default.
+// The kind describes the input in the log, e.g., "edited message".
+func (a *Adapter) enqueueFailed(input sarah.Input, correlationID string, kind string, err error) {
+	if a.enqueueErrorHandler != nil {
+		a.enqueueErrorHandler(input, err)
+		return
+	}
+	a.log().Errorf("[%s] Failed to enqueue %s: %+v", correlationID, kind, err)
+}
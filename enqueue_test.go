@@ -0,0 +1,98 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestWithEnqueueErrorHandler(t *testing.T) {
+	handler := func(sarah.Input, error) {}
+	adapter := &Adapter{}
+	WithEnqueueErrorHandler(handler)(adapter)
+
+	if adapter.enqueueErrorHandler == nil {
+		t.Error("Expected the handler to be set")
+	}
+}
+
+func TestAdapter_handleMessage_EnqueueErrorHandler(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+	queueFull := errors.New("queue is full")
+
+	newMessage := func(content string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ID:        "msg-1",
+				ChannelID: "ch-1",
+				Content:   content,
+				Author:    &discordgo.User{ID: "user-1"},
+			},
+		}
+	}
+
+	t.Run("handler receives the dropped input", func(t *testing.T) {
+		logger := &capturingLogger{}
+		var droppedInput sarah.Input
+		var droppedErr error
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}, logger: logger}
+		WithEnqueueErrorHandler(func(input sarah.Input, err error) {
+			droppedInput = input
+			droppedErr = err
+		})(adapter)
+
+		adapter.handleMessage(context.Background(), s, newMessage("hello"), func(sarah.Input) error { return queueFull })
+
+		typed, ok := droppedInput.(*Input)
+		if !ok || typed.MessageID() != "msg-1" {
+			t.Errorf("Unexpected input: %#v", droppedInput)
+		}
+		if !errors.Is(droppedErr, queueFull) {
+			t.Errorf("Unexpected error: %+v", droppedErr)
+		}
+		for _, entry := range logger.entries {
+			if strings.HasPrefix(entry, "ERROR") {
+				t.Errorf("Expected the handler to replace the error log: %s", entry)
+			}
+		}
+		if adapter.Stats().Total.Dropped != 1 {
+			t.Errorf("Expected the message to be counted as dropped: %#v", adapter.Stats().Total)
+		}
+	})
+
+	t.Run("handler receives the help input", func(t *testing.T) {
+		var droppedInput sarah.Input
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+		WithEnqueueErrorHandler(func(input sarah.Input, _ error) {
+			droppedInput = input
+		})(adapter)
+
+		adapter.handleMessage(context.Background(), s, newMessage(adapter.config.HelpCommand), func(sarah.Input) error { return queueFull })
+
+		if _, ok := droppedInput.(*sarah.HelpInput); !ok {
+			t.Errorf("Expected *sarah.HelpInput, got %T", droppedInput)
+		}
+	})
+
+	t.Run("failure is logged by default", func(t *testing.T) {
+		logger := &capturingLogger{}
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}, logger: logger}
+
+		adapter.handleMessage(context.Background(), s, newMessage("hello"), func(sarah.Input) error { return queueFull })
+
+		logged := false
+		for _, entry := range logger.entries {
+			if strings.HasPrefix(entry, "ERROR") && strings.Contains(entry, "queue is full") {
+				logged = true
+			}
+		}
+		if !logged {
+			t.Errorf("Expected the failure to be logged: %v", logger.entries)
+		}
+	})
+}
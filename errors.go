@@ -7,3 +7,48 @@ var ErrEmptyToken = errors.New("token must be set or a session must be provided
 
 // ErrNoAuthor indicates that the given message has no author.
 var ErrNoAuthor = errors.New("message has no author")
+
+// ErrStreamClosed indicates that a write was attempted on a closed StreamWriter.
+var ErrStreamClosed = errors.New("stream is already closed")
+
+// ErrDirectMessage indicates that the requested operation is only available for messages sent in a guild.
+var ErrDirectMessage = errors.New("message was not sent in a guild")
+
+// ErrInvalidMessageURL indicates that the given string is not a valid message jump URL.
+var ErrInvalidMessageURL = errors.New("invalid message URL")
+
+// ErrMessageNotFound indicates that the requested message does not exist.
+var ErrMessageNotFound = errors.New("message not found")
+
+// ErrMessageInaccessible indicates that the bot does not have access to the requested message.
+var ErrMessageInaccessible = errors.New("message is not accessible")
+
+// ErrInviteNotAttributed indicates that the invite used by a joined member could not be determined.
+var ErrInviteNotAttributed = errors.New("invite could not be attributed")
+
+// ErrUnknownEmoji indicates that no custom emoji with the given name is available to the bot.
+var ErrUnknownEmoji = errors.New("unknown emoji")
+
+// ErrTooManyEmbeds indicates that a response has more embeds than Discord accepts in a single message.
+var ErrTooManyEmbeds = errors.New("too many embeds")
+
+// ErrEphemeralNotSupported indicates that an ephemeral response is requested for a message, which only interactions support.
+var ErrEphemeralNotSupported = errors.New("ephemeral responses are only supported for interactions")
+
+// ErrMessageTooOld indicates that a message is too old to be deleted in bulk.
+var ErrMessageTooOld = errors.New("message is too old to bulk delete")
+
+// ErrTooManyPins indicates that the channel already has as many pinned messages as Discord allows.
+var ErrTooManyPins = errors.New("maximum number of pinned messages reached")
+
+// ErrSendQueueFull indicates that a message is dropped because too many messages are waiting to be sent to the same channel.
+var ErrSendQueueFull = errors.New("too many messages pending for the channel")
+
+// ErrShuttingDown indicates that a message is dropped because the Adapter is shutting down.
+var ErrShuttingDown = errors.New("adapter is shutting down")
+
+// ErrInvalidConfig indicates that the configuration is not coherent.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ErrUnknownApplication indicates that the application ID is not known because the session is not opened yet.
+var ErrUnknownApplication = errors.New("application ID is not known until the session is opened")
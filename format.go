@@ -0,0 +1,232 @@
+package discord
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	mentionTokenPattern   = regexp.MustCompile(`^<(@!?|@&|#)(\d+)>`)
+	emojiTokenPattern     = regexp.MustCompile(`^<a?:(\w+):\d+>`)
+	timestampTokenPattern = regexp.MustCompile(`^<t:(-?\d+)(?::[tTdDfFR])?>`)
+	maskedLinkPattern     = regexp.MustCompile(`^\[([^\[\]]+)\]\((<?)(https?://[^\s()<>]+)(>?)\)`)
+	headingPattern        = regexp.MustCompile(`^(#{1,3}|-#) `)
+)
+
+// inlineDelimiters lists the inline formatting delimiters in the order they are tried.
+// Longer delimiters come first so "***" and "**" are not mistaken for "*".
+var inlineDelimiters = []string{"***", "**", "__", "~~", "||", "*", "_"}
+
+// StripDiscordFormatting converts the given Discord message content to plain text.
+// This is useful to log or relay messages to other platforms.
+//
+// Bold, italics, underline, strikethrough, spoilers, headings, block quotes, and code are removed while their texts are kept.
+// Escaped characters are unescaped, and masked links are converted to "text (url)".
+// Mention tokens are converted to readable text: "<@123>" to "@123", "<@&123>" to "@&123", and "<#123>" to "#123".
+// Custom emojis are converted to ":name:" and timestamps are converted to UTC time.
+// Unbalanced formatting characters are left as they are.
+func StripDiscordFormatting(content string) string {
+	var builder strings.Builder
+	stripFormatting(&builder, content)
+	return builder.String()
+}
+
+func stripFormatting(builder *strings.Builder, s string) {
+	for i := 0; i < len(s); {
+		rest := s[i:]
+
+		if i == 0 || s[i-1] == '\n' {
+			if n := lineStartMarkerLength(rest); n > 0 {
+				i += n
+				continue
+			}
+		}
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && isEscapable(rest[1]):
+			builder.WriteByte(rest[1])
+			i += 2
+			continue
+
+		case strings.HasPrefix(rest, "```"):
+			if end := strings.Index(rest[3:], "```"); end >= 0 {
+				builder.WriteString(codeBlockBody(rest[3 : 3+end]))
+				i += 3 + end + 3
+				continue
+			}
+
+		case rest[0] == '`':
+			if n, body, ok := inlineCode(rest); ok {
+				builder.WriteString(body)
+				i += n
+				continue
+			}
+
+		case rest[0] == '<':
+			if n, text, ok := convertToken(rest); ok {
+				builder.WriteString(text)
+				i += n
+				continue
+			}
+
+		case rest[0] == '[':
+			if m := maskedLinkPattern.FindStringSubmatch(rest); m != nil {
+				builder.WriteString(m[1] + " (" + m[3] + ")")
+				i += len(m[0])
+				continue
+			}
+		}
+
+		if n, inner, ok := inlineFormatting(s, i); ok {
+			stripFormatting(builder, inner)
+			i += n
+			continue
+		}
+
+		builder.WriteByte(rest[0])
+		i++
+	}
+}
+
+// lineStartMarkerLength returns the length of a heading or block quote marker at the beginning of a line.
+func lineStartMarkerLength(line string) int {
+	if strings.HasPrefix(line, ">>> ") {
+		return 4
+	}
+	if strings.HasPrefix(line, "> ") {
+		return 2
+	}
+	if m := headingPattern.FindString(line); m != "" {
+		return len(m)
+	}
+	return 0
+}
+
+// isEscapable tells if the given character is escaped by a preceding backslash in Discord's markdown.
+func isEscapable(c byte) bool {
+	return strings.IndexByte("\\*_~`|>#-[]()<:", c) >= 0
+}
+
+// codeBlockBody returns the content of a code block without the language identifier.
+func codeBlockBody(body string) string {
+	if newline := strings.IndexByte(body, '\n'); newline >= 0 {
+		lang := body[:newline]
+		if lang == "" || !strings.ContainsAny(lang, " \t") {
+			return body[newline+1:]
+		}
+	}
+	return body
+}
+
+// inlineCode parses an inline code span that is enclosed by backticks of the same length.
+func inlineCode(s string) (int, string, bool) {
+	n := 0
+	for n < len(s) && s[n] == '`' {
+		n++
+	}
+
+	delimiter := s[:n]
+	end := strings.Index(s[n:], delimiter)
+	if end <= 0 {
+		return 0, "", false
+	}
+
+	body := s[n : n+end]
+	if len(body) > 2 && body[0] == ' ' && body[len(body)-1] == ' ' {
+		body = body[1 : len(body)-1]
+	}
+	return n + end + n, body, true
+}
+
+// convertToken converts a mention, custom emoji, or timestamp token to readable text.
+func convertToken(s string) (int, string, bool) {
+	if m := mentionTokenPattern.FindStringSubmatch(s); m != nil {
+		prefix := m[1]
+		if prefix == "@!" {
+			prefix = "@"
+		}
+		return len(m[0]), prefix + m[2], true
+	}
+
+	if m := emojiTokenPattern.FindStringSubmatch(s); m != nil {
+		return len(m[0]), ":" + m[1] + ":", true
+	}
+
+	if m := timestampTokenPattern.FindStringSubmatch(s); m != nil {
+		sec, err := strconv.ParseInt(m[1], 10, 64)
+		if err == nil {
+			return len(m[0]), time.Unix(sec, 0).UTC().Format("2006-01-02 15:04:05 MST"), true
+		}
+	}
+
+	return 0, "", false
+}
+
+// inlineFormatting parses inline formatting that starts at s[i] and returns its length and inner text.
+func inlineFormatting(s string, i int) (int, string, bool) {
+	for _, delimiter := range inlineDelimiters {
+		if !strings.HasPrefix(s[i:], delimiter) {
+			continue
+		}
+
+		// Underscores only work as delimiters at word boundaries so snake_case_words are kept.
+		if delimiter == "_" && i > 0 && isWordChar(s[i-1]) {
+			return 0, "", false
+		}
+
+		start := i + len(delimiter)
+		if len(delimiter) == 1 && start < len(s) && s[start] == delimiter[0] {
+			continue
+		}
+
+		end := findClosingDelimiter(s, start, delimiter)
+		if end < 0 {
+			continue
+		}
+
+		return end + len(delimiter) - i, s[start:end], true
+	}
+
+	return 0, "", false
+}
+
+// findClosingDelimiter finds the index of the delimiter that closes the formatting opened right before from.
+// -1 is returned when the formatting is not closed.
+func findClosingDelimiter(s string, from int, delimiter string) int {
+	single := len(delimiter) == 1
+	for j := from; j < len(s); j++ {
+		if s[j] == '\\' {
+			j++
+			continue
+		}
+
+		if !strings.HasPrefix(s[j:], delimiter) {
+			continue
+		}
+
+		// A single-character delimiter must not be a part of a longer run such as "**" in "*a **b** c*".
+		if single && j+1 < len(s) && s[j+1] == delimiter[0] {
+			j++
+			continue
+		}
+
+		// The formatting must contain something.
+		if j == from {
+			return -1
+		}
+
+		if delimiter == "_" && j+1 < len(s) && isWordChar(s[j+1]) {
+			continue
+		}
+
+		return j
+	}
+
+	return -1
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
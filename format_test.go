@@ -0,0 +1,66 @@
+package discord
+
+import "testing"
+
+func TestStripDiscordFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain text", input: "hello world", expected: "hello world"},
+		{name: "multibyte text", input: "**こんにちは**世界", expected: "こんにちは世界"},
+		{name: "bold", input: "**bold**", expected: "bold"},
+		{name: "italic with asterisk", input: "*italic*", expected: "italic"},
+		{name: "italic with underscore", input: "_italic_", expected: "italic"},
+		{name: "bold italic", input: "***both***", expected: "both"},
+		{name: "underline", input: "__underline__", expected: "underline"},
+		{name: "strikethrough", input: "~~gone~~", expected: "gone"},
+		{name: "spoiler", input: "||secret||", expected: "secret"},
+		{name: "nested bold in italic", input: "*a **b** c*", expected: "a b c"},
+		{name: "nested italic in bold", input: "**a *b* c**", expected: "a b c"},
+		{name: "nested spoiler and bold", input: "||**x** y||", expected: "x y"},
+		{name: "underline with italic", input: "__*x*__", expected: "x"},
+		{name: "inline code keeps content", input: "run `**not bold**` now", expected: "run **not bold** now"},
+		{name: "double backtick code", input: "``a ` b``", expected: "a ` b"},
+		{name: "code block with language", input: "```go\nfmt.Println(\"*x*\")\n```", expected: "fmt.Println(\"*x*\")\n"},
+		{name: "code block without language", input: "```\n**raw**```", expected: "**raw**"},
+		{name: "single line code block", input: "```x = 1```", expected: "x = 1"},
+		{name: "escaped characters", input: `\*not italic\* and \_no\_`, expected: "*not italic* and _no_"},
+		{name: "escaped closing delimiter", input: `**a\*b**`, expected: "a*b"},
+		{name: "escaped backslash", input: `a\\b`, expected: `a\b`},
+		{name: "backslash before normal character", input: `a\b`, expected: `a\b`},
+		{name: "snake case is kept", input: "snake_case_word", expected: "snake_case_word"},
+		{name: "heading", input: "# Title\n## Sub\n-# small", expected: "Title\nSub\nsmall"},
+		{name: "hash without space is kept", input: "#hashtag", expected: "#hashtag"},
+		{name: "block quote", input: "> quoted\nnormal", expected: "quoted\nnormal"},
+		{name: "multi-line block quote", input: ">>> a\nb", expected: "a\nb"},
+		{name: "masked link", input: "[docs](https://example.com/x)", expected: "docs (https://example.com/x)"},
+		{name: "masked link without embed", input: "[docs](<https://example.com>)", expected: "docs (https://example.com)"},
+		{name: "user mention", input: "hi <@123>", expected: "hi @123"},
+		{name: "nickname mention", input: "hi <@!123>", expected: "hi @123"},
+		{name: "role mention", input: "<@&456> ping", expected: "@&456 ping"},
+		{name: "channel mention", input: "see <#789>", expected: "see #789"},
+		{name: "custom emoji", input: "nice <:thumbs:111>", expected: "nice :thumbs:"},
+		{name: "animated emoji", input: "<a:dance:222>", expected: ":dance:"},
+		{name: "timestamp", input: "<t:0:R>", expected: "1970-01-01 00:00:00 UTC"},
+		{name: "mention inside bold", input: "**<@123>**", expected: "@123"},
+		{name: "unclosed bold", input: "**unclosed", expected: "**unclosed"},
+		{name: "unclosed code", input: "`unclosed", expected: "`unclosed"},
+		{name: "unclosed code block", input: "```unclosed", expected: "```unclosed"},
+		{name: "lone asterisks", input: "2 * 3 = 6", expected: "2 * 3 = 6"},
+		{name: "empty delimiters", input: "****", expected: "****"},
+		{name: "malformed mention", input: "<@abc>", expected: "<@abc>"},
+		{name: "less than sign", input: "a < b", expected: "a < b"},
+		{name: "empty", input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StripDiscordFormatting(tt.input)
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
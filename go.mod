@@ -4,12 +4,12 @@ go 1.25.6
 
 require (
 	github.com/bwmarrin/discordgo v0.29.0
+	github.com/gorilla/websocket v1.5.3
 	github.com/oklahomer/go-kasumi v0.0.0-20220203122045-3db87696aa9c
 	github.com/oklahomer/go-sarah/v4 v4.0.4
 )
 
 require (
-	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/patrickmn/go-cache v2.1.0+incompatible // indirect
 	github.com/robfig/cron/v3 v3.0.1 // indirect
 	golang.org/x/crypto v0.48.0 // indirect
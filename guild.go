@@ -0,0 +1,160 @@
+package discord
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// GuildEnabledStore defines an interface that stores whether the bot is enabled in each guild.
+// When a guild is disabled, messages sent in that guild are dropped before reaching go-sarah.
+// Messages sent in direct messages are not subject to this store.
+type GuildEnabledStore interface {
+	// IsEnabled tells if the bot is enabled in the given guild.
+	IsEnabled(guildID string) bool
+
+	// SetEnabled enables or disables the bot in the given guild.
+	SetEnabled(guildID string, enabled bool) error
+}
+
+// GuildForgetter is an optional interface that a GuildEnabledStore implements
+// to discard the state of a guild that the bot was removed from.
+type GuildForgetter interface {
+	// Forget discards the state of the given guild.
+	Forget(guildID string) error
+}
+
+type inMemoryGuildEnabledStore struct {
+	mutex    sync.RWMutex
+	disabled map[string]struct{}
+}
+
+var _ GuildEnabledStore = (*inMemoryGuildEnabledStore)(nil)
+var _ GuildForgetter = (*inMemoryGuildEnabledStore)(nil)
+
+// NewInMemoryGuildEnabledStore creates a GuildEnabledStore that keeps the state in memory.
+// Every guild is enabled until explicitly disabled, and the state is lost when the process exits.
+func NewInMemoryGuildEnabledStore() GuildEnabledStore {
+	return &inMemoryGuildEnabledStore{
+		disabled: map[string]struct{}{},
+	}
+}
+
+func (s *inMemoryGuildEnabledStore) IsEnabled(guildID string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	_, disabled := s.disabled[guildID]
+	return !disabled
+}
+
+func (s *inMemoryGuildEnabledStore) SetEnabled(guildID string, enabled bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if enabled {
+		delete(s.disabled, guildID)
+	} else {
+		s.disabled[guildID] = struct{}{}
+	}
+	return nil
+}
+
+func (s *inMemoryGuildEnabledStore) Forget(guildID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.disabled, guildID)
+	return nil
+}
+
+// WithGuildEnabledStore creates an AdapterOption with the given GuildEnabledStore.
+// If this option is not given and Config.EnableGuildToggle is true, NewAdapter uses an in-memory store.
+func WithGuildEnabledStore(store GuildEnabledStore) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.guildEnabledStore = store
+	}
+}
+
+// guildEnabled tells if the given input should be handled in terms of the guild's enabled state.
+// It also handles the guild toggle commands, in which case false is returned so the command is not passed to go-sarah.
+func (a *Adapter) guildEnabled(input *Input) bool {
+	guildID := input.Event.GuildID
+	if a.guildEnabledStore == nil || guildID == "" {
+		return true
+	}
+
+	if a.config.EnableGuildToggle {
+		trimmed := strings.TrimSpace(input.Message())
+		if a.config.GuildEnableCommand != "" && trimmed == a.config.GuildEnableCommand {
+			a.toggleGuild(input, true)
+			return false
+		}
+		if a.config.GuildDisableCommand != "" && trimmed == a.config.GuildDisableCommand {
+			a.toggleGuild(input, false)
+			return false
+		}
+	}
+
+	return a.guildEnabledStore.IsEnabled(guildID)
+}
+
+// guildIDEnabled tells if the bot is enabled in the given guild.
+// The bot is always enabled in a direct message and without GuildEnabledStore.
+func (a *Adapter) guildIDEnabled(guildID string) bool {
+	return a.guildEnabledStore == nil || guildID == "" || a.guildEnabledStore.IsEnabled(guildID)
+}
+
+// toggleGuild enables or disables the bot in the input's guild if the author has the Manage Server permission.
+func (a *Adapter) toggleGuild(input *Input, enabled bool) {
+	guildID := input.Event.GuildID
+	channelID := string(input.channelID)
+
+	var reply string
+	permitted, err := input.AuthorCan(a, discordgo.PermissionManageGuild)
+	if err != nil {
+		a.log().Errorf("[%s] Failed to compute permissions of %s: %+v", input.correlationID, input.SenderKey(), err)
+		reply = "Failed to check your permissions."
+	} else if !permitted {
+		reply = "You need the Manage Server permission to do this."
+	} else if err := a.guildEnabledStore.SetEnabled(guildID, enabled); err != nil {
+		a.log().Errorf("[%s] Failed to update enabled state of guild %s: %+v", input.correlationID, guildID, err)
+		reply = "Failed to update the bot's state in this server."
+	} else if enabled {
+		reply = "The bot is now enabled in this server."
+	} else {
+		reply = "The bot is now disabled in this server."
+	}
+
+	err = a.SendMessageWithError(input.Context(), sarah.NewOutputMessage(input.channelID, reply))
+	if err != nil {
+		a.log().Errorf("[%s] Failed to send message to %s: %+v", input.correlationID, channelID, err)
+	}
+}
+
+// handleGuildDelete discards the state of the guild when the bot is removed from it.
+// Discord also sends GuildDelete when a guild becomes unavailable due to an outage,
+// in which case the state is kept because the guild becomes available again.
+func (a *Adapter) handleGuildDelete(g *discordgo.GuildDelete) {
+	if g.Guild == nil || g.Unavailable {
+		a.log().Debugf("Guild is unavailable due to an outage. Keeping its state: %#v", g.Guild)
+		return
+	}
+
+	if forgetter, ok := a.guildEnabledStore.(GuildForgetter); ok {
+		if err := forgetter.Forget(g.ID); err != nil {
+			a.log().Errorf("Failed to forget enabled state of guild %s: %+v", g.ID, err)
+		}
+	}
+
+	// Discord deletes the guild's commands along with the bot's membership, so only the cached state is discarded.
+	a.emojis.forget(g.ID)
+	a.channels.forgetGuild(g.ID)
+	a.guildCommands.take(g.ID)
+
+	if a.config.OnGuildLeave != nil {
+		a.config.OnGuildLeave(g.ID)
+	}
+}
@@ -0,0 +1,326 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestNewInMemoryGuildEnabledStore(t *testing.T) {
+	store := NewInMemoryGuildEnabledStore()
+
+	if !store.IsEnabled("guild-1") {
+		t.Error("Expected guilds to be enabled by default")
+	}
+
+	if err := store.SetEnabled("guild-1", false); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if store.IsEnabled("guild-1") {
+		t.Error("Expected guild-1 to be disabled")
+	}
+	if !store.IsEnabled("guild-2") {
+		t.Error("Expected guild-2 to stay enabled")
+	}
+
+	if err := store.SetEnabled("guild-1", true); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if !store.IsEnabled("guild-1") {
+		t.Error("Expected guild-1 to be enabled again")
+	}
+}
+
+func TestWithGuildEnabledStore(t *testing.T) {
+	store := NewInMemoryGuildEnabledStore()
+	adapter := &Adapter{}
+
+	WithGuildEnabledStore(store)(adapter)
+
+	if adapter.guildEnabledStore != store {
+		t.Error("WithGuildEnabledStore should set the store on the adapter")
+	}
+}
+
+func TestNewAdapter_GuildEnabledStore(t *testing.T) {
+	t.Run("toggle enabled without store", func(t *testing.T) {
+		config := NewConfig()
+		config.EnableGuildToggle = true
+
+		adapter, err := NewAdapter(config, WithSession(&discordgo.Session{}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if adapter.guildEnabledStore == nil {
+			t.Error("Expected an in-memory store to be set")
+		}
+	})
+
+	t.Run("toggle disabled without store", func(t *testing.T) {
+		adapter, err := NewAdapter(NewConfig(), WithSession(&discordgo.Session{}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if adapter.guildEnabledStore != nil {
+			t.Error("Expected no store to be set")
+		}
+	})
+}
+
+func TestAdapter_handleMessage_GuildEnabled(t *testing.T) {
+	guildID := "guild-1"
+	channelID := "ch-1"
+	ownerID := "owner-1"
+	memberID := "member-1"
+
+	s := &discordgo.Session{State: discordgo.NewState()}
+	guildFunc := func(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+		return &discordgo.Guild{ID: guildID, OwnerID: ownerID}, nil
+	}
+
+	newMessage := func(authorID, guildID, content string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: channelID,
+				GuildID:   guildID,
+				Content:   content,
+				Timestamp: time.Now(),
+				Author:    &discordgo.User{ID: authorID},
+			},
+		}
+	}
+
+	t.Run("message from disabled guild is dropped", func(t *testing.T) {
+		store := NewInMemoryGuildEnabledStore()
+		_ = store.SetEnabled(guildID, false)
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}, guildEnabledStore: store}
+
+		var received sarah.Input
+		adapter.handleMessage(context.Background(), s, newMessage(memberID, guildID, "hello"), func(input sarah.Input) error {
+			received = input
+			return nil
+		})
+
+		if received != nil {
+			t.Error("Message from disabled guild should be dropped")
+		}
+	})
+
+	t.Run("direct message is not affected", func(t *testing.T) {
+		store := NewInMemoryGuildEnabledStore()
+		_ = store.SetEnabled(guildID, false)
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}, guildEnabledStore: store}
+
+		var received sarah.Input
+		adapter.handleMessage(context.Background(), s, newMessage(memberID, "", "hello"), func(input sarah.Input) error {
+			received = input
+			return nil
+		})
+
+		if received == nil {
+			t.Error("Direct message should be enqueued")
+		}
+	})
+
+	t.Run("owner can disable and re-enable", func(t *testing.T) {
+		store := NewInMemoryGuildEnabledStore()
+		config := NewConfig()
+		config.EnableGuildToggle = true
+
+		var replies []string
+		mock := &mockSession{
+			guildFunc: guildFunc,
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				replies = append(replies, content)
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: config, session: mock, guildEnabledStore: store}
+
+		var received sarah.Input
+		enqueue := func(input sarah.Input) error {
+			received = input
+			return nil
+		}
+
+		adapter.handleMessage(context.Background(), s, newMessage(ownerID, guildID, "/disable"), enqueue)
+		if store.IsEnabled(guildID) {
+			t.Error("Expected guild to be disabled")
+		}
+
+		adapter.handleMessage(context.Background(), s, newMessage(ownerID, guildID, "/enable"), enqueue)
+		if !store.IsEnabled(guildID) {
+			t.Error("Expected guild to be enabled")
+		}
+
+		if received != nil {
+			t.Error("Toggle commands should not be enqueued")
+		}
+		if len(replies) != 2 {
+			t.Errorf("Expected 2 replies, got %d", len(replies))
+		}
+	})
+
+	t.Run("member without permission cannot toggle", func(t *testing.T) {
+		store := NewInMemoryGuildEnabledStore()
+		config := NewConfig()
+		config.EnableGuildToggle = true
+
+		var reply string
+		mock := &mockSession{
+			guildFunc: guildFunc,
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				reply = content
+				return nil, fmt.Errorf("send failed")
+			},
+		}
+		adapter := &Adapter{config: config, session: mock, guildEnabledStore: store}
+
+		adapter.handleMessage(context.Background(), s, newMessage(memberID, guildID, "/disable"), func(input sarah.Input) error { return nil })
+
+		if !store.IsEnabled(guildID) {
+			t.Error("Guild should stay enabled")
+		}
+		if reply == "" {
+			t.Error("Expected a rejection reply")
+		}
+	})
+
+	t.Run("toggle commands are regular input when toggle is disabled", func(t *testing.T) {
+		store := NewInMemoryGuildEnabledStore()
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}, guildEnabledStore: store}
+
+		var received sarah.Input
+		adapter.handleMessage(context.Background(), s, newMessage(ownerID, guildID, "/disable"), func(input sarah.Input) error {
+			received = input
+			return nil
+		})
+
+		if received == nil {
+			t.Error("Expected the message to be enqueued")
+		}
+		if !store.IsEnabled(guildID) {
+			t.Error("Guild should stay enabled")
+		}
+	})
+
+	t.Run("permission check failure is reported", func(t *testing.T) {
+		store := NewInMemoryGuildEnabledStore()
+		config := NewConfig()
+		config.EnableGuildToggle = true
+
+		var reply string
+		mock := &mockSession{
+			guildFunc: func(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+				return nil, errors.New("unknown guild")
+			},
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				reply = content
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: config, session: mock, guildEnabledStore: store}
+
+		adapter.handleMessage(context.Background(), s, newMessage(ownerID, guildID, "/disable"), func(input sarah.Input) error { return nil })
+
+		if !store.IsEnabled(guildID) {
+			t.Error("Guild should stay enabled")
+		}
+		if reply == "" {
+			t.Error("Expected a failure reply")
+		}
+	})
+}
+
+func TestAdapter_handleGuildDelete(t *testing.T) {
+	setup := func() (*Adapter, *[]string) {
+		var left []string
+		config := NewConfig()
+		config.OnGuildLeave = func(guildID string) {
+			left = append(left, guildID)
+		}
+		store := NewInMemoryGuildEnabledStore()
+		_ = store.SetEnabled("guild-1", false)
+		return &Adapter{config: config, session: &mockSession{}, guildEnabledStore: store}, &left
+	}
+
+	t.Run("removal discards the guild state", func(t *testing.T) {
+		adapter, left := setup()
+		now := time.Now()
+		_, _ = adapter.emojis.get("guild-1", now, func() ([]*discordgo.Emoji, error) { return nil, nil })
+		for _, channel := range []*discordgo.Channel{{ID: "ch-1", GuildID: "guild-1"}, {ID: "ch-2", GuildID: "guild-2"}} {
+			_, _ = adapter.channels.get(channel.ID, time.Minute, now, func() (*discordgo.Channel, error) { return channel, nil })
+		}
+		adapter.guildCommands.add("guild-1", "cmd-1")
+
+		adapter.handleGuildDelete(&discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "guild-1"}})
+
+		if len(*left) != 1 || (*left)[0] != "guild-1" {
+			t.Errorf("Expected OnGuildLeave to be called with guild-1, got %v", *left)
+		}
+		if !adapter.guildEnabledStore.IsEnabled("guild-1") {
+			t.Error("Expected the disabled state to be discarded")
+		}
+		if _, ok := adapter.emojis.entries["guild-1"]; ok {
+			t.Error("Expected the cached emojis to be discarded")
+		}
+		if _, ok := adapter.channels.entries["ch-1"]; ok {
+			t.Error("Expected the cached channel of the guild to be discarded")
+		}
+		if _, ok := adapter.channels.entries["ch-2"]; !ok {
+			t.Error("Expected the cached channel of another guild to be kept")
+		}
+		if ids := adapter.guildCommands.take("guild-1"); len(ids) != 0 {
+			t.Errorf("Expected the registered commands to be discarded, got %v", ids)
+		}
+	})
+
+	t.Run("outage keeps the guild state", func(t *testing.T) {
+		adapter, left := setup()
+
+		adapter.handleGuildDelete(&discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "guild-1", Unavailable: true}})
+
+		if len(*left) != 0 {
+			t.Errorf("Expected OnGuildLeave not to be called, got %v", *left)
+		}
+		if adapter.guildEnabledStore.IsEnabled("guild-1") {
+			t.Error("Expected the disabled state to be kept")
+		}
+	})
+
+	t.Run("without callback or store", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		// Should not panic.
+		adapter.handleGuildDelete(&discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "guild-1"}})
+	})
+
+	t.Run("handler is registered on Run", func(t *testing.T) {
+		var registered bool
+		mock := &mockSession{
+			addHandlerFunc: func(handler interface{}) func() {
+				if _, ok := handler.(func(*discordgo.Session, *discordgo.GuildDelete)); ok {
+					registered = true
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				return errors.New("stop here")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+		if !registered {
+			t.Error("Expected a GuildDelete handler to be registered")
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RegisterGuildCommands creates the given application commands in the guild one by one, e.g., for a test bot,
+// since guild commands are available immediately while global commands may take up to an hour to propagate.
+// Unlike RegisterApplicationCommands, the commands registered before are kept.
+// The created commands are remembered so CleanupGuildCommands can remove them, e.g., on shutdown.
+func (a *Adapter) RegisterGuildCommands(ctx context.Context, guildID string, cmds []*discordgo.ApplicationCommand) error {
+	if guildID == "" {
+		return errors.New("guild ID is required to register guild commands")
+	}
+
+	appID := a.applicationID()
+	if appID == "" {
+		return ErrUnknownApplication
+	}
+
+	for _, cmd := range cmds {
+		created, err := a.session.ApplicationCommandCreate(appID, guildID, cmd, a.requestOptions(discordgo.WithContext(ctx))...)
+		if err != nil {
+			return fmt.Errorf("failed to register command %s in guild %s: %w", cmd.Name, guildID, err)
+		}
+		a.guildCommands.add(guildID, created.ID)
+	}
+
+	return nil
+}
+
+// CleanupGuildCommands deletes the commands that RegisterGuildCommands created in the guild, so stale commands are not left after the bot stops.
+// Pass a context that is not canceled yet since the one given to go-sarah is canceled on shutdown.
+// The commands that failed to be deleted are kept to be retried by the next call.
+func (a *Adapter) CleanupGuildCommands(ctx context.Context, guildID string) error {
+	appID := a.applicationID()
+	if appID == "" {
+		return ErrUnknownApplication
+	}
+
+	var errs []error
+	for _, cmdID := range a.guildCommands.take(guildID) {
+		err := a.session.ApplicationCommandDelete(appID, guildID, cmdID, a.requestOptions(discordgo.WithContext(ctx))...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete command %s in guild %s: %w", cmdID, guildID, err))
+			a.guildCommands.add(guildID, cmdID)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// guildCommandRegistry remembers the IDs of the commands registered to each guild. The zero value is ready to use.
+type guildCommandRegistry struct {
+	mutex sync.Mutex
+	ids   map[string][]string // Guild ID to command IDs
+}
+
+func (r *guildCommandRegistry) add(guildID string, cmdID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.ids == nil {
+		r.ids = map[string][]string{}
+	}
+	r.ids[guildID] = append(r.ids[guildID], cmdID)
+}
+
+// take returns the command IDs registered to the guild and forgets them.
+func (r *guildCommandRegistry) take(guildID string) []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	ids := r.ids[guildID]
+	delete(r.ids, guildID)
+	return ids
+}
@@ -0,0 +1,146 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAdapter_RegisterGuildCommands(t *testing.T) {
+	t.Run("commands are created in the guild", func(t *testing.T) {
+		var created []string
+		mock := &mockSession{
+			applicationCommandCreateFunc: func(appID string, guildID string, cmd *discordgo.ApplicationCommand, _ ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+				if appID != "app-1" || guildID != "guild-1" {
+					t.Errorf("Unexpected target: %s in %s", appID, guildID)
+				}
+				created = append(created, cmd.Name)
+				return &discordgo.ApplicationCommand{ID: "cmd-" + cmd.Name, Name: cmd.Name}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		adapter.appID.Store("app-1")
+
+		cmds := []*discordgo.ApplicationCommand{{Name: "echo"}, {Name: "ping"}}
+		if err := adapter.RegisterGuildCommands(context.Background(), "guild-1", cmds); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if !slices.Equal(created, []string{"echo", "ping"}) {
+			t.Errorf("Unexpected commands: %v", created)
+		}
+	})
+
+	t.Run("guild ID is required", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+		adapter.appID.Store("app-1")
+
+		if err := adapter.RegisterGuildCommands(context.Background(), "", []*discordgo.ApplicationCommand{{Name: "echo"}}); err == nil {
+			t.Error("Expected an error without a guild ID")
+		}
+	})
+
+	t.Run("an error is returned before the session is opened", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		err := adapter.RegisterGuildCommands(context.Background(), "guild-1", nil)
+		if !errors.Is(err, ErrUnknownApplication) {
+			t.Errorf("Expected ErrUnknownApplication, got %+v", err)
+		}
+	})
+
+	t.Run("an API error is returned", func(t *testing.T) {
+		apiErr := errors.New("api error")
+		mock := &mockSession{
+			applicationCommandCreateFunc: func(_ string, _ string, _ *discordgo.ApplicationCommand, _ ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+				return nil, apiErr
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		adapter.appID.Store("app-1")
+
+		err := adapter.RegisterGuildCommands(context.Background(), "guild-1", []*discordgo.ApplicationCommand{{Name: "echo"}})
+		if !errors.Is(err, apiErr) {
+			t.Errorf("Expected the API error, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_CleanupGuildCommands(t *testing.T) {
+	newAdapter := func(deleteFunc func(appID string, guildID string, cmdID string, options ...discordgo.RequestOption) error) *Adapter {
+		mock := &mockSession{
+			applicationCommandCreateFunc: func(_ string, guildID string, cmd *discordgo.ApplicationCommand, _ ...discordgo.RequestOption) (*discordgo.ApplicationCommand, error) {
+				return &discordgo.ApplicationCommand{ID: guildID + "/" + cmd.Name, Name: cmd.Name}, nil
+			},
+			applicationCommandDeleteFunc: deleteFunc,
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		adapter.appID.Store("app-1")
+
+		cmds := []*discordgo.ApplicationCommand{{Name: "echo"}, {Name: "ping"}}
+		for _, guildID := range []string{"guild-1", "guild-2"} {
+			if err := adapter.RegisterGuildCommands(context.Background(), guildID, cmds); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+		}
+		return adapter
+	}
+
+	t.Run("only the commands registered to the guild are deleted", func(t *testing.T) {
+		var deleted []string
+		adapter := newAdapter(func(appID string, guildID string, cmdID string, _ ...discordgo.RequestOption) error {
+			if appID != "app-1" || guildID != "guild-1" {
+				t.Errorf("Unexpected target: %s in %s", appID, guildID)
+			}
+			deleted = append(deleted, cmdID)
+			return nil
+		})
+
+		if err := adapter.CleanupGuildCommands(context.Background(), "guild-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if !slices.Equal(deleted, []string{"guild-1/echo", "guild-1/ping"}) {
+			t.Errorf("Unexpected deletions: %v", deleted)
+		}
+
+		// The deleted commands are forgotten.
+		deleted = nil
+		if err := adapter.CleanupGuildCommands(context.Background(), "guild-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if len(deleted) != 0 {
+			t.Errorf("Expected nothing to be deleted again, got %v", deleted)
+		}
+	})
+
+	t.Run("failed deletions are retried", func(t *testing.T) {
+		apiErr := errors.New("api error")
+		fail := true
+		var deleted []string
+		adapter := newAdapter(func(_ string, _ string, cmdID string, _ ...discordgo.RequestOption) error {
+			if fail && cmdID == "guild-1/echo" {
+				return apiErr
+			}
+			deleted = append(deleted, cmdID)
+			return nil
+		})
+
+		err := adapter.CleanupGuildCommands(context.Background(), "guild-1")
+		if !errors.Is(err, apiErr) {
+			t.Errorf("Expected the API error, got %+v", err)
+		}
+		if !slices.Equal(deleted, []string{"guild-1/ping"}) {
+			t.Errorf("Expected the other command to be deleted, got %v", deleted)
+		}
+
+		fail = false
+		if err := adapter.CleanupGuildCommands(context.Background(), "guild-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if !slices.Equal(deleted, []string{"guild-1/ping", "guild-1/echo"}) {
+			t.Errorf("Expected the failed command to be deleted on retry, got %v", deleted)
+		}
+	})
+}
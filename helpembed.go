@@ -0,0 +1,112 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+const (
+	// maxEmbedFields is the maximum number of fields Discord accepts in a single embed.
+	maxEmbedFields = 25
+
+	// maxEmbedFieldNameLength is the maximum number of characters Discord accepts in an embed field's name.
+	maxEmbedFieldNameLength = 256
+
+	// maxEmbedFieldValueLength is the maximum number of characters Discord accepts in an embed field's value.
+	maxEmbedFieldValueLength = 1024
+
+	// maxEmbedsTextLength is the maximum number of characters Discord accepts across all embeds in a single message.
+	maxEmbedsTextLength = 6000
+
+	// helpEmbedTitle is the title of the first help embed.
+	helpEmbedTitle = "Commands"
+)
+
+// buildHelpEmbeds renders the given helps as embeds with a field per command.
+// A new embed starts when the fields or the characters reach Discord's limits.
+func buildHelpEmbeds(helps sarah.CommandHelps) []*discordgo.MessageEmbed {
+	var embeds []*discordgo.MessageEmbed
+	var current *discordgo.MessageEmbed
+	length := 0
+
+	for _, help := range helps {
+		field := &discordgo.MessageEmbedField{
+			Name:  truncateRunes(help.Identifier, maxEmbedFieldNameLength),
+			Value: truncateRunes(help.Instruction, maxEmbedFieldValueLength),
+		}
+		// Discord rejects a field with an empty name or value.
+		if field.Name == "" {
+			field.Name = "-"
+		}
+		if field.Value == "" {
+			field.Value = "-"
+		}
+		fieldLength := utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+
+		if current == nil || len(current.Fields) >= maxEmbedFields || length+fieldLength > maxEmbedsTextLength {
+			current = &discordgo.MessageEmbed{}
+			if len(embeds) == 0 {
+				current.Title = helpEmbedTitle
+			}
+			embeds = append(embeds, current)
+			length = utf8.RuneCountInString(current.Title)
+		}
+
+		current.Fields = append(current.Fields, field)
+		length += fieldLength
+	}
+
+	return embeds
+}
+
+// buildHelpEmbedMessages groups the given embeds into messages within the embed count and character limits of a single message.
+func buildHelpEmbedMessages(embeds []*discordgo.MessageEmbed, limit int) []*discordgo.MessageSend {
+	if limit <= 0 || limit > MaxEmbedsPerMessage {
+		limit = MaxEmbedsPerMessage
+	}
+
+	var messages []*discordgo.MessageSend
+	var current *discordgo.MessageSend
+	length := 0
+	for _, embed := range embeds {
+		embedLength := embedTextLength(embed)
+		if current == nil || len(current.Embeds) >= limit || length+embedLength > maxEmbedsTextLength {
+			current = &discordgo.MessageSend{}
+			messages = append(messages, current)
+			length = 0
+		}
+		current.Embeds = append(current.Embeds, embed)
+		length += embedLength
+	}
+
+	return messages
+}
+
+// embedTextLength returns the number of characters in the given embed that count toward Discord's limit.
+func embedTextLength(embed *discordgo.MessageEmbed) int {
+	length := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description)
+	for _, field := range embed.Fields {
+		length += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	if embed.Footer != nil {
+		length += utf8.RuneCountInString(embed.Footer.Text)
+	}
+	if embed.Author != nil {
+		length += utf8.RuneCountInString(embed.Author.Name)
+	}
+	return length
+}
+
+// sendHelpEmbeds sends the given helps as embeds. The given options are applied after the defaults.
+func (a *Adapter) sendHelpEmbeds(ctx context.Context, channelID string, helps sarah.CommandHelps, options ...discordgo.RequestOption) error {
+	for _, data := range buildHelpEmbedMessages(buildHelpEmbeds(helps), a.config.MaxEmbedsPerMessage) {
+		if err := a.sendComplex(ctx, channelID, data, options...); err != nil {
+			return fmt.Errorf("failed to send help embeds: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newCommandHelps(count int, instructionLength int) *sarah.CommandHelps {
+	helps := sarah.CommandHelps{}
+	for i := range count {
+		helps = append(helps, &sarah.CommandHelp{
+			Identifier:  fmt.Sprintf("command%d", i),
+			Instruction: strings.Repeat("x", instructionLength),
+		})
+	}
+	return &helps
+}
+
+func TestBuildHelpEmbeds(t *testing.T) {
+	t.Run("fields", func(t *testing.T) {
+		embeds := buildHelpEmbeds(sarah.CommandHelps{
+			{Identifier: "echo", Instruction: ".echo foo"},
+			{Identifier: "noop", Instruction: ""},
+		})
+
+		if len(embeds) != 1 {
+			t.Fatalf("Expected 1 embed, got %d", len(embeds))
+		}
+		if embeds[0].Title != helpEmbedTitle {
+			t.Errorf("Unexpected title: %q", embeds[0].Title)
+		}
+		if len(embeds[0].Fields) != 2 {
+			t.Fatalf("Expected 2 fields, got %d", len(embeds[0].Fields))
+		}
+		if embeds[0].Fields[0].Name != "echo" || embeds[0].Fields[0].Value != ".echo foo" {
+			t.Errorf("Unexpected field: %#v", embeds[0].Fields[0])
+		}
+		if embeds[0].Fields[1].Value == "" {
+			t.Error("Empty instruction should be replaced")
+		}
+	})
+
+	t.Run("overflow produces a second embed", func(t *testing.T) {
+		embeds := buildHelpEmbeds(*newCommandHelps(30, 10))
+
+		if len(embeds) != 2 {
+			t.Fatalf("Expected 2 embeds, got %d", len(embeds))
+		}
+		if len(embeds[0].Fields) != maxEmbedFields || len(embeds[1].Fields) != 5 {
+			t.Errorf("Unexpected field counts: %d, %d", len(embeds[0].Fields), len(embeds[1].Fields))
+		}
+		if embeds[1].Title != "" {
+			t.Errorf("Only the first embed should have the title: %q", embeds[1].Title)
+		}
+	})
+
+	t.Run("long instructions stay within the character limit", func(t *testing.T) {
+		embeds := buildHelpEmbeds(*newCommandHelps(10, 2000))
+
+		for i, embed := range embeds {
+			if embedTextLength(embed) > maxEmbedsTextLength {
+				t.Errorf("Embed %d is too long: %d", i, embedTextLength(embed))
+			}
+			for _, field := range embed.Fields {
+				if len(field.Value) > maxEmbedFieldValueLength {
+					t.Errorf("Field is too long: %d", len(field.Value))
+				}
+			}
+		}
+
+		for i, message := range buildHelpEmbedMessages(embeds, MaxEmbedsPerMessage) {
+			length := 0
+			for _, embed := range message.Embeds {
+				length += embedTextLength(embed)
+			}
+			if length > maxEmbedsTextLength {
+				t.Errorf("Message %d is too long: %d", i, length)
+			}
+		}
+	})
+}
+
+func TestAdapter_SendMessage_HelpAsEmbed(t *testing.T) {
+	var sent []*discordgo.MessageSend
+	mock := &mockSession{
+		channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, data)
+			return &discordgo.Message{}, nil
+		},
+		channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			t.Error("Help should not be sent as plain text")
+			return &discordgo.Message{}, nil
+		},
+	}
+	config := NewConfig()
+	config.HelpAsEmbed = true
+	adapter := &Adapter{config: config, session: mock}
+
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), newCommandHelps(30, 10)))
+
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(sent))
+	}
+	if len(sent[0].Embeds) != 2 {
+		t.Errorf("Expected 2 embeds, got %d", len(sent[0].Embeds))
+	}
+}
+
+func TestAdapter_SendMessage_HelpAsEmbed_CommonSendPath(t *testing.T) {
+	var attempts int
+	var sent *discordgo.MessageSend
+	mock := &mockSession{
+		channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, newStatusError(http.StatusBadGateway)
+			}
+			sent = data
+			return &discordgo.Message{}, nil
+		},
+	}
+	mentions := &discordgo.MessageAllowedMentions{}
+	config := NewConfig()
+	config.HelpAsEmbed = true
+	config.SendMaxRetries = 1
+	config.SendRetryInterval = time.Millisecond
+	config.DefaultAllowedMentions = mentions
+	adapter := &Adapter{config: config, session: mock}
+
+	err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), newCommandHelps(3, 10)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected the transient failure to be retried, got %d attempts", attempts)
+	}
+	if sent == nil || sent.AllowedMentions != mentions {
+		t.Errorf("Expected DefaultAllowedMentions to be applied: %#v", sent)
+	}
+}
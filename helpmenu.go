@@ -0,0 +1,166 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+const (
+	// helpMenuCustomIDPrefix prefixes the custom IDs of help menus so their interactions are told from others.
+	helpMenuCustomIDPrefix = "go-sarah-discord:help:"
+
+	// maxSelectMenuOptions is the maximum number of options Discord accepts in a select menu.
+	maxSelectMenuOptions = 25
+
+	// maxActionRows is the maximum number of action rows Discord accepts in a message.
+	maxActionRows = 5
+
+	// maxSelectMenuTextLength is the maximum number of characters in a select menu option's label, value, and description.
+	maxSelectMenuTextLength = 100
+
+	// maxHelpMenus is the number of recent help menus whose selections can be answered.
+	maxHelpMenus = 100
+
+	// expiredHelpMenuMessage is sent when the selected help menu is no longer remembered, e.g., after a restart.
+	expiredHelpMenuMessage = "This help menu has expired. Please ask for help again."
+)
+
+// helpLine renders the given help as a line of text.
+func helpLine(help *sarah.CommandHelp) string {
+	return fmt.Sprintf("**%s**: %s", help.Identifier, help.Instruction)
+}
+
+// buildHelpMenus renders the given helps as messages with select menus of command identifiers.
+// Each select menu has up to 25 commands and each message has up to 5 menus.
+// The value of each option is the index of the help so the selection can be mapped back to its instruction.
+func buildHelpMenus(menuID string, helps sarah.CommandHelps) []*discordgo.MessageSend {
+	var messages []*discordgo.MessageSend
+	var rows []discordgo.MessageComponent
+
+	for start := 0; start < len(helps); start += maxSelectMenuOptions {
+		end := min(start+maxSelectMenuOptions, len(helps))
+
+		options := make([]discordgo.SelectMenuOption, 0, end-start)
+		for i := start; i < end; i++ {
+			options = append(options, discordgo.SelectMenuOption{
+				Label:       truncateRunes(helps[i].Identifier, maxSelectMenuTextLength),
+				Value:       strconv.Itoa(i),
+				Description: commandDescription(helps[i].Instruction),
+			})
+		}
+
+		rows = append(rows, discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{
+					CustomID:    helpMenuCustomIDPrefix + menuID + ":" + strconv.Itoa(start/maxSelectMenuOptions),
+					Placeholder: "Choose a command",
+					Options:     options,
+				},
+			},
+		})
+
+		if len(rows) == maxActionRows || end == len(helps) {
+			messages = append(messages, &discordgo.MessageSend{Components: rows})
+			rows = nil
+		}
+	}
+
+	if len(messages) > 0 {
+		messages[0].Content = "Choose a command to see how to use it."
+	}
+	return messages
+}
+
+// sendHelpMenus sends the given helps as select menus and remembers them to answer the selections.
+func (a *Adapter) sendHelpMenus(ctx context.Context, channelID string, helps sarah.CommandHelps, options ...discordgo.RequestOption) error {
+	menuID := a.helpMenus.add(helps)
+	for _, data := range buildHelpMenus(menuID, helps) {
+		if err := a.sendComplex(ctx, channelID, data, options...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleHelpMenuSelection answers a selection in a help menu with the instruction of the selected command.
+// Other interactions are ignored, and so are the selections filtered out as handleInteraction does or received after the given context is canceled.
+func (a *Adapter) handleHelpMenuSelection(ctx context.Context, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent {
+		return
+	}
+
+	data := i.MessageComponentData()
+	rest, ok := strings.CutPrefix(data.CustomID, helpMenuCustomIDPrefix)
+	if !ok {
+		return
+	}
+
+	if ctx.Err() != nil {
+		a.log().Debugf("Skipping help menu selection %s received during shutdown", i.ID)
+		return
+	}
+
+	if reason := a.interactionIgnored(i); reason != "" {
+		a.log().Debugf("Ignoring help menu selection %s %s", i.ID, reason)
+		return
+	}
+
+	menuID, _, _ := strings.Cut(rest, ":")
+	content := expiredHelpMenuMessage
+	if helps, ok := a.helpMenus.get(menuID); ok && len(data.Values) > 0 {
+		if index, err := strconv.Atoi(data.Values[0]); err == nil && index >= 0 && index < len(helps) {
+			content = helpLine(helps[index])
+		}
+	}
+
+	response := &interactionResponse{Content: content, options: &interactionRespOptions{ephemeral: true}}
+	err := a.SendMessageWithError(ctx, sarah.NewOutputMessage(&InteractionDestination{Interaction: i.Interaction}, response))
+	if err != nil {
+		a.log().Errorf("Failed to respond to help menu selection: %+v", err)
+	}
+}
+
+// helpMenuRegistry remembers the helps of recently sent help menus.
+// The oldest menu is forgotten when more than maxHelpMenus menus are sent.
+// The zero value is ready to use.
+type helpMenuRegistry struct {
+	mutex sync.Mutex
+	seq   uint64
+	menus map[string]sarah.CommandHelps
+	order []string
+}
+
+func (r *helpMenuRegistry) add(helps sarah.CommandHelps) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.menus == nil {
+		r.menus = map[string]sarah.CommandHelps{}
+	}
+
+	r.seq++
+	id := strconv.FormatUint(r.seq, 36)
+	r.menus[id] = helps
+	r.order = append(r.order, id)
+
+	if len(r.order) > maxHelpMenus {
+		delete(r.menus, r.order[0])
+		r.order = r.order[1:]
+	}
+
+	return id
+}
+
+func (r *helpMenuRegistry) get(id string) (sarah.CommandHelps, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	helps, ok := r.menus[id]
+	return helps, ok
+}
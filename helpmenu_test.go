@@ -0,0 +1,182 @@
+package discord
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newHelps(n int) sarah.CommandHelps {
+	helps := make(sarah.CommandHelps, n)
+	for i := range helps {
+		helps[i] = &sarah.CommandHelp{Identifier: "cmd" + strconv.Itoa(i), Instruction: "Instruction " + strconv.Itoa(i)}
+	}
+	return helps
+}
+
+func newSelection(customID string, values ...string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionMessageComponent,
+			Data: discordgo.MessageComponentInteractionData{
+				CustomID:      customID,
+				ComponentType: discordgo.SelectMenuComponent,
+				Values:        values,
+			},
+		},
+	}
+}
+
+func TestBuildHelpMenus(t *testing.T) {
+	t.Run("menus are split by the option and row limits", func(t *testing.T) {
+		messages := buildHelpMenus("1", newHelps(130))
+
+		if len(messages) != 2 {
+			t.Fatalf("Expected 2 messages, got %d", len(messages))
+		}
+		if len(messages[0].Components) != 5 || len(messages[1].Components) != 1 {
+			t.Errorf("Unexpected rows: %d and %d", len(messages[0].Components), len(messages[1].Components))
+		}
+		if messages[0].Content == "" || messages[1].Content != "" {
+			t.Error("Expected the guidance only on the first message")
+		}
+
+		last := messages[1].Components[0].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+		if last.CustomID != helpMenuCustomIDPrefix+"1:5" {
+			t.Errorf("Unexpected custom ID: %s", last.CustomID)
+		}
+		if len(last.Options) != 5 || last.Options[0].Value != "125" || last.Options[0].Label != "cmd125" {
+			t.Errorf("Unexpected options: %#v", last.Options)
+		}
+	})
+
+	t.Run("long texts are truncated", func(t *testing.T) {
+		helps := sarah.CommandHelps{{Identifier: strings.Repeat("a", 150), Instruction: strings.Repeat("b", 150)}}
+		option := buildHelpMenus("1", helps)[0].Components[0].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu).Options[0]
+
+		if len(option.Label) != maxSelectMenuTextLength || len([]rune(option.Description)) != maxSelectMenuTextLength {
+			t.Errorf("Expected texts to be truncated: %#v", option)
+		}
+	})
+}
+
+func TestAdapter_HelpAsSelectMenu(t *testing.T) {
+	var sent []*discordgo.MessageSend
+	var responses []*discordgo.InteractionResponse
+	mock := &mockSession{
+		channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, data)
+			return &discordgo.Message{}, nil
+		},
+		interactionRespondFunc: func(interaction *discordgo.Interaction, resp *discordgo.InteractionResponse, options ...discordgo.RequestOption) error {
+			responses = append(responses, resp)
+			return nil
+		},
+	}
+	config := NewConfig()
+	config.HelpAsSelectMenu = true
+	adapter := &Adapter{config: config, session: mock}
+
+	helps := newHelps(3)
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), &helps))
+
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(sent))
+	}
+	menu := sent[0].Components[0].(discordgo.ActionsRow).Components[0].(discordgo.SelectMenu)
+
+	t.Run("selection is answered with the instruction", func(t *testing.T) {
+		responses = nil
+		adapter.handleHelpMenuSelection(context.Background(), newSelection(menu.CustomID, "2"))
+
+		if len(responses) != 1 {
+			t.Fatalf("Expected 1 response, got %d", len(responses))
+		}
+		if responses[0].Data.Content != "**cmd2**: Instruction 2" {
+			t.Errorf("Unexpected content: %q", responses[0].Data.Content)
+		}
+		if responses[0].Data.Flags&discordgo.MessageFlagsEphemeral == 0 {
+			t.Error("Expected an ephemeral response")
+		}
+	})
+
+	t.Run("unknown menu is answered as expired", func(t *testing.T) {
+		responses = nil
+		adapter.handleHelpMenuSelection(context.Background(), newSelection(helpMenuCustomIDPrefix+"unknown:0", "0"))
+
+		if len(responses) != 1 || responses[0].Data.Content != expiredHelpMenuMessage {
+			t.Errorf("Unexpected responses: %#v", responses)
+		}
+	})
+
+	t.Run("filtered selections are ignored", func(t *testing.T) {
+		responses = nil
+		blocked := newSelection(menu.CustomID, "2")
+		blocked.User = &discordgo.User{ID: "user-1"}
+		adapter.config.BlockedUsers = []string{"user-1"}
+		defer func() {
+			adapter.config.BlockedUsers = nil
+			adapter.blockedUsers = idSet{}
+		}()
+		adapter.handleHelpMenuSelection(context.Background(), blocked)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		adapter.handleHelpMenuSelection(ctx, newSelection(menu.CustomID, "2"))
+
+		if len(responses) != 0 {
+			t.Errorf("Expected no response, got %#v", responses)
+		}
+	})
+
+	t.Run("other interactions are ignored", func(t *testing.T) {
+		responses = nil
+		adapter.handleHelpMenuSelection(context.Background(), newSelection("other:1", "0"))
+		adapter.handleHelpMenuSelection(context.Background(), &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{Type: discordgo.InteractionPing}})
+
+		if len(responses) != 0 {
+			t.Errorf("Expected no response, got %#v", responses)
+		}
+	})
+}
+
+func TestAdapter_HelpAsSelectMenu_DefaultAllowedMentions(t *testing.T) {
+	var sent []*discordgo.MessageSend
+	mock := &mockSession{
+		channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sent = append(sent, data)
+			return &discordgo.Message{}, nil
+		},
+	}
+	mentions := &discordgo.MessageAllowedMentions{}
+	config := NewConfig()
+	config.HelpAsSelectMenu = true
+	config.DefaultAllowedMentions = mentions
+	adapter := &Adapter{config: config, session: mock}
+
+	helps := newHelps(3)
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), &helps))
+
+	if len(sent) != 1 || sent[0].AllowedMentions != mentions {
+		t.Errorf("Expected DefaultAllowedMentions to be applied: %#v", sent)
+	}
+}
+
+func TestHelpMenuRegistry(t *testing.T) {
+	registry := &helpMenuRegistry{}
+	first := registry.add(newHelps(1))
+	for range maxHelpMenus {
+		registry.add(newHelps(1))
+	}
+
+	if _, ok := registry.get(first); ok {
+		t.Error("Expected the oldest menu to be forgotten")
+	}
+	if len(registry.menus) != maxHelpMenus {
+		t.Errorf("Expected %d menus, got %d", maxHelpMenus, len(registry.menus))
+	}
+}
@@ -0,0 +1,39 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// intentsInUse returns the gateway intents that the session identifies with.
+// The session given via WithSession is configured by the caller, so its intents take precedence over WithIntents and Config.Intents.
+func (a *Adapter) intentsInUse() discordgo.Intent {
+	if s, ok := a.session.(*discordgo.Session); ok && s.Identify.Intents != 0 {
+		return s.Identify.Intents
+	}
+	if a.intents != nil {
+		return *a.intents
+	}
+	return a.config.Intents
+}
+
+// warnMissingMessageContent warns that commands in guild messages never match without the MessageContent intent,
+// since Discord then delivers the messages with empty content, which is otherwise hard to tell.
+func (a *Adapter) warnMissingMessageContent() {
+	intents := a.intentsInUse()
+	if intents&discordgo.IntentsGuildMessages == 0 || intents&discordgo.IntentsMessageContent != 0 {
+		return
+	}
+	a.log().Warnf("Intents do not include MessageContent. Guild messages arrive with empty content unless they mention the bot, so commands never match them.")
+}
+
+// hintEmptyContent logs a hint when a message arrives with nothing in it even though the MessageContent intent is requested,
+// which means the intent is not enabled for the bot in the Developer Portal.
+func (a *Adapter) hintEmptyContent(m *discordgo.MessageCreate) {
+	if m.Content != "" || len(m.Attachments) > 0 || len(m.Embeds) > 0 || len(m.StickerItems) > 0 {
+		return
+	}
+	if a.intentsInUse()&discordgo.IntentsMessageContent == 0 {
+		return
+	}
+	a.log().Debugf("Message %s has no content. Enable the Message Content Intent for the bot in the Developer Portal if this is unexpected.", m.ID)
+}
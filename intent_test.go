@@ -0,0 +1,116 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_Run_MessageContentWarning(t *testing.T) {
+	tests := []struct {
+		name    string
+		intents discordgo.Intent
+		warned  bool
+	}{
+		{name: "default intents", intents: NewConfig().Intents, warned: false},
+		{name: "without MessageContent", intents: discordgo.IntentsGuildMessages | discordgo.IntentsDirectMessages, warned: true},
+		{name: "direct messages only", intents: discordgo.IntentsDirectMessages, warned: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &capturingLogger{}
+			mock := &mockSession{
+				openFunc: func() error {
+					return errors.New("stop here")
+				},
+			}
+			config := NewConfig()
+			config.Intents = tt.intents
+			adapter := &Adapter{config: config, session: mock, logger: l}
+
+			adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+			if warned := l.has("WARN", "MessageContent"); warned != tt.warned {
+				t.Errorf("Expected the warning to be %t: %v", tt.warned, l.entries)
+			}
+		})
+	}
+
+	t.Run("WithIntents overrides Config.Intents", func(t *testing.T) {
+		l := &capturingLogger{}
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{openFunc: func() error { return errors.New("stop here") }}, logger: l}
+		WithIntents(discordgo.IntentsGuildMessages)(adapter)
+
+		adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+		if !l.has("WARN", "MessageContent") {
+			t.Errorf("Expected the warning: %v", l.entries)
+		}
+	})
+}
+
+func TestAdapter_handleMessage_EmptyContentHint(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name    string
+		intents discordgo.Intent
+		message *discordgo.Message
+		hinted  bool
+	}{
+		{
+			name:    "empty message with the intent",
+			intents: NewConfig().Intents,
+			message: &discordgo.Message{ID: "msg-1", ChannelID: "ch-1", Author: &discordgo.User{ID: "user-1"}},
+			hinted:  true,
+		},
+		{
+			name:    "message with content",
+			intents: NewConfig().Intents,
+			message: &discordgo.Message{ID: "msg-1", ChannelID: "ch-1", Content: "hello", Author: &discordgo.User{ID: "user-1"}},
+			hinted:  false,
+		},
+		{
+			name:    "attachment only",
+			intents: NewConfig().Intents,
+			message: &discordgo.Message{ID: "msg-1", ChannelID: "ch-1", Attachments: []*discordgo.MessageAttachment{{ID: "file-1"}}, Author: &discordgo.User{ID: "user-1"}},
+			hinted:  false,
+		},
+		{
+			name:    "empty message without the intent",
+			intents: discordgo.IntentsGuildMessages,
+			message: &discordgo.Message{ID: "msg-1", ChannelID: "ch-1", Author: &discordgo.User{ID: "user-1"}},
+			hinted:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &capturingLogger{}
+			config := NewConfig()
+			config.Intents = tt.intents
+			adapter := &Adapter{config: config, session: &mockSession{}, logger: l}
+
+			adapter.handleMessage(context.Background(), s, &discordgo.MessageCreate{Message: tt.message}, func(sarah.Input) error { return nil })
+
+			if hinted := l.has("DEBUG", "Message Content Intent"); hinted != tt.hinted {
+				t.Errorf("Expected the hint to be %t: %v", tt.hinted, l.entries)
+			}
+		})
+	}
+}
+
+func TestAdapter_intentsInUse(t *testing.T) {
+	session := &discordgo.Session{}
+	session.Identify.Intents = discordgo.IntentsGuildMessages
+	adapter := &Adapter{config: NewConfig(), session: session}
+
+	if adapter.intentsInUse() != discordgo.IntentsGuildMessages {
+		t.Errorf("Expected the session's intents to take precedence, got %d", adapter.intentsInUse())
+	}
+}
@@ -0,0 +1,475 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// RegisterApplicationCommands registers the given application commands, replacing the ones registered before.
+// Commands are registered to the given guild, or globally when guildID is empty.
+// Guild commands are available immediately, so they suit development, while global commands may take a while to propagate.
+func (a *Adapter) RegisterApplicationCommands(ctx context.Context, guildID string, commands []*discordgo.ApplicationCommand) ([]*discordgo.ApplicationCommand, error) {
+	appID := a.applicationID()
+	if appID == "" {
+		return nil, ErrUnknownApplication
+	}
+
+	registered, err := a.session.ApplicationCommandBulkOverwrite(appID, guildID, commands, a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register application commands: %w", err)
+	}
+	return registered, nil
+}
+
+// InteractionInput is a sarah.Input implementation that represents a received slash command or button click.
+// The message of a slash command is the command name followed by its string options in the declared order, e.g., "echo hello",
+// so the command can be matched the same way as a message with the prefix stripped.
+type InteractionInput struct {
+	Event     *discordgo.InteractionCreate
+	senderKey string
+	text      string
+	sentAt    time.Time
+	options   map[string]string
+
+	destination   *InteractionDestination
+	correlationID string
+}
+
+var _ sarah.Input = (*InteractionInput)(nil)
+
+// SenderKey returns a unique key representing the sender in the channel.
+// This is the same key as Input.SenderKey, so a conversation can continue between messages and slash commands.
+func (i *InteractionInput) SenderKey() string {
+	return i.senderKey
+}
+
+// Message returns the command name followed by its string options, or the custom ID of the clicked button.
+func (i *InteractionInput) Message() string {
+	return i.text
+}
+
+// SentAt returns when the interaction was received.
+func (i *InteractionInput) SentAt() time.Time {
+	return i.sentAt
+}
+
+// ReplyTo returns the interaction to respond to.
+func (i *InteractionInput) ReplyTo() sarah.OutputDestination {
+	return i.destination
+}
+
+// CommandName returns the name of the invoked command, or an empty string for a button click.
+func (i *InteractionInput) CommandName() string {
+	if i.Event.Type != discordgo.InteractionApplicationCommand {
+		return ""
+	}
+	return i.Event.ApplicationCommandData().Name
+}
+
+// CustomID returns the custom ID of the clicked button, or an empty string for a slash command.
+func (i *InteractionInput) CustomID() string {
+	if i.Event.Type != discordgo.InteractionMessageComponent {
+		return ""
+	}
+	return i.Event.MessageComponentData().CustomID
+}
+
+// Option returns the value of the given string option.
+func (i *InteractionInput) Option(name string) (string, bool) {
+	value, ok := i.options[name]
+	return value, ok
+}
+
+// CorrelationID returns the ID that correlates the logs and outgoing messages caused by this input.
+func (i *InteractionInput) CorrelationID() string {
+	return i.correlationID
+}
+
+// InteractionDestination is a sarah.OutputDestination that responds to an interaction.
+// Discord requires the first response to an interaction within 3 seconds.
+type InteractionDestination struct {
+	Interaction *discordgo.Interaction
+
+	mutex     sync.Mutex
+	responded bool
+}
+
+var _ sarah.OutputDestination = (*InteractionDestination)(nil)
+
+// InteractionToInput converts a slash command or a button click to *InteractionInput.
+// Only CHAT_INPUT commands are supported, and options other than strings are not included in the message.
+// The message of a button click is the button's custom ID.
+func InteractionToInput(i *discordgo.InteractionCreate) (*InteractionInput, error) {
+	var text string
+	options := map[string]string{}
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		data := i.ApplicationCommandData()
+		if data.CommandType != discordgo.ChatApplicationCommand {
+			return nil, fmt.Errorf("application command type %d is not supported", data.CommandType)
+		}
+
+		words := []string{data.Name}
+		for _, option := range data.Options {
+			if option.Type != discordgo.ApplicationCommandOptionString {
+				continue
+			}
+			options[option.Name] = option.StringValue()
+			words = append(words, option.StringValue())
+		}
+		text = strings.Join(words, " ")
+
+	case discordgo.InteractionMessageComponent:
+		data := i.MessageComponentData()
+		if data.ComponentType != discordgo.ButtonComponent {
+			return nil, fmt.Errorf("component type %d is not supported", data.ComponentType)
+		}
+		text = data.CustomID
+
+	default:
+		return nil, fmt.Errorf("interaction type %s is not supported", i.Type)
+	}
+
+	user := interactionUser(i)
+	if user == nil {
+		return nil, ErrNoAuthor
+	}
+
+	// The sender key is in the same format as the message's, so a button click continues the conversational context
+	// that a message of the same user in the channel started.
+	return &InteractionInput{
+		Event:     i,
+		senderKey: senderKeyOf(interactionChannelID(i), user.ID),
+		text:      text,
+		sentAt:    time.Now(),
+		options:   options,
+
+		destination:   &InteractionDestination{Interaction: i.Interaction},
+		correlationID: newCorrelationID(),
+	}, nil
+}
+
+// interactionUser returns the user who triggered the interaction, or nil if unknown.
+// Member is set for an interaction in a guild, and User is set for one in a direct message.
+func interactionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+// interactionIgnored tells why the interaction should be ignored in terms of Config.BlockedUsers, the channel lists,
+// and the guild's enabled state. An empty string is returned when the interaction should be handled.
+func (a *Adapter) interactionIgnored(i *discordgo.InteractionCreate) string {
+	if user := interactionUser(i); user != nil && a.userBlocked(user.ID) {
+		return fmt.Sprintf("from blocked user %s", user.ID)
+	}
+
+	if channelID := interactionChannelID(i); !a.channelAllowed(channelID) {
+		return fmt.Sprintf("in channel %s", channelID)
+	}
+
+	if !a.guildIDEnabled(i.GuildID) {
+		return fmt.Sprintf("in disabled guild %s", i.GuildID)
+	}
+
+	return ""
+}
+
+// interactionChannelID returns the ID of the channel where the interaction is triggered.
+// A component interaction tells the channel via the message it is attached to when the interaction itself lacks it.
+func interactionChannelID(i *discordgo.InteractionCreate) string {
+	if i.ChannelID == "" && i.Message != nil {
+		return i.Message.ChannelID
+	}
+	return i.ChannelID
+}
+
+// handleInteraction passes the received slash command or button click to go-sarah.
+// Selections on the help menu are handled by handleHelpMenuSelection instead.
+// The interaction is filtered by the blocked users, the channel filters and the guild's enabled state as a message is.
+func (a *Adapter) handleInteraction(ctx context.Context, i *discordgo.InteractionCreate, enqueueInput func(sarah.Input) error) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+	case discordgo.InteractionMessageComponent:
+		if strings.HasPrefix(i.MessageComponentData().CustomID, helpMenuCustomIDPrefix) {
+			return
+		}
+	default:
+		return
+	}
+
+	a.messageReceived()
+
+	if ctx.Err() != nil {
+		a.log().Debugf("Skipping interaction %s received during shutdown", i.ID)
+		a.messageDropped(ctx.Err())
+		return
+	}
+
+	input, err := InteractionToInput(i)
+	if err != nil {
+		a.log().Debugf("Skipping interaction: %+v", err)
+		a.messageDropped(err)
+		return
+	}
+
+	if reason := a.interactionIgnored(i); reason != "" {
+		a.log().Debugf("[%s] Ignoring interaction %s %s", input.correlationID, i.ID, reason)
+		a.messageDropped(nil)
+		return
+	}
+
+	if err := enqueueInput(input); err != nil {
+		a.enqueueFailed(input, input.correlationID, "interaction", err)
+		a.messageDropped(err)
+		return
+	}
+	a.log().Debugf("[%s] Enqueued interaction %s from %s", input.correlationID, i.ID, input.senderKey)
+	a.messageEnqueued()
+}
+
+// InteractionRespOption customizes the response to an interaction.
+type InteractionRespOption func(*interactionRespOptions)
+
+type interactionRespOptions struct {
+	deferred  bool
+	ephemeral bool
+}
+
+// InteractionRespDeferred acknowledges the interaction without content so the command can take longer than Discord's 3 seconds.
+// Users see a loading state until the following response, which edits the deferred response.
+func InteractionRespDeferred() InteractionRespOption {
+	return func(options *interactionRespOptions) {
+		options.deferred = true
+	}
+}
+
+// InteractionRespEphemeral makes the response only visible to the user who invoked the command.
+// This has no effect when the interaction is already responded to, e.g., by a deferred response.
+func InteractionRespEphemeral() InteractionRespOption {
+	return func(options *interactionRespOptions) {
+		options.ephemeral = true
+	}
+}
+
+// RespEphemeral makes the response to an interaction only visible to the user who invoked the command.
+// Discord has no ephemeral messages outside interactions, so NewResponse returns ErrEphemeralNotSupported for a message input.
+func RespEphemeral() RespOption {
+	return func(options *respOptions) {
+		options.ephemeral = true
+	}
+}
+
+// interactionResponseOf creates a response to an interaction with NewResponse's options.
+func interactionResponseOf(input *InteractionInput, content any, options *respOptions) (*sarah.CommandResponse, error) {
+	userContext := options.userContext
+
+	// When the command is used in the wrong context, respond with the rejection message instead.
+	rejected := options.context != nil && input.Event != nil && !options.context.matchesGuild(input.Event.GuildID)
+	if rejected {
+		content = options.context.rejection
+		userContext = nil
+	}
+
+	if !rejected && len(options.embeds) > 0 {
+		embedded, err := withEmbeds(content, options.embeds)
+		if err != nil {
+			return nil, err
+		}
+		content = embedded
+	}
+
+	if !rejected && len(options.components) > 0 {
+		content = withComponents(content, options.components)
+	}
+
+	if !rejected && options.tts {
+		content = withTTS(content)
+	}
+
+	if options.allowedMentions != nil {
+		content = withAllowedMentions(content, options.allowedMentions)
+	}
+
+	response := &sarah.CommandResponse{
+		Content: &interactionResponse{
+			Content: content,
+			options: &interactionRespOptions{ephemeral: options.ephemeral},
+		},
+		UserContext: userContext,
+	}
+
+	if len(options.requestOptions) > 0 {
+		response.Content = &requestOptionsResponse{
+			Content: response.Content,
+			Options: options.requestOptions,
+		}
+	}
+
+	return response, nil
+}
+
+// interactionResponse is a response content that carries the options for an interaction.
+type interactionResponse struct {
+	Content any
+	options *interactionRespOptions
+}
+
+// NewInteractionResponse creates a *sarah.CommandResponse that responds to the given slash command.
+// The content parameter may be a string for plain text messages or a *discordgo.MessageSend for rich content.
+// When the interaction is already deferred by Adapter.RespondInteraction, the response edits the deferred one.
+func NewInteractionResponse[T ResponseContent](interaction sarah.Input, content T, options ...InteractionRespOption) (*sarah.CommandResponse, error) {
+	if _, ok := interaction.(*InteractionInput); !ok {
+		return nil, fmt.Errorf("%T is not a *discord.InteractionInput", interaction)
+	}
+
+	stash := &interactionRespOptions{}
+	for _, opt := range options {
+		opt(stash)
+	}
+
+	return &sarah.CommandResponse{
+		Content: &interactionResponse{
+			Content: content,
+			options: stash,
+		},
+	}, nil
+}
+
+// RespondInteraction responds to the given slash command outside the command's return value.
+// This is typically used with InteractionRespDeferred at the beginning of a long-running command,
+// whose returned response then replaces the loading state:
+//
+//	err := adapter.RespondInteraction(ctx, input, nil, discord.InteractionRespDeferred())
+//
+// The content may be nil when deferring, or otherwise a string or a *discordgo.MessageSend.
+func (a *Adapter) RespondInteraction(ctx context.Context, interaction *InteractionInput, content any, options ...InteractionRespOption) error {
+	stash := &interactionRespOptions{}
+	for _, opt := range options {
+		opt(stash)
+	}
+
+	return a.respondInteraction(ctx, interaction.destination, content, stash)
+}
+
+// respondInteraction responds to the interaction with the given content.
+// The first response is sent via InteractionRespond, and the following ones edit it.
+// Config.DefaultAllowedMentions applies unless the content specifies its own, and the given request options are applied after the defaults.
+func (a *Adapter) respondInteraction(ctx context.Context, destination *InteractionDestination, content any, options *interactionRespOptions, requestOptions ...discordgo.RequestOption) error {
+	if typed, ok := content.(*interactionResponse); ok {
+		content, options = typed.Content, typed.options
+	}
+	if options == nil {
+		options = &interactionRespOptions{}
+	}
+	requestOptions = a.requestOptions(append([]discordgo.RequestOption{discordgo.WithContext(ctx)}, requestOptions...)...)
+
+	destination.mutex.Lock()
+	defer destination.mutex.Unlock()
+
+	if !destination.responded && options.deferred {
+		err := a.session.InteractionRespond(destination.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Flags: ephemeralFlag(options)},
+		}, requestOptions...)
+		if err != nil {
+			return fmt.Errorf("failed to defer interaction %s: %w", destination.Interaction.ID, err)
+		}
+		destination.responded = true
+
+		if content == nil {
+			return nil
+		}
+	}
+
+	if destination.responded {
+		edit, err := interactionEdit(content)
+		if err != nil {
+			return err
+		}
+		if edit.AllowedMentions == nil {
+			edit.AllowedMentions = a.config.DefaultAllowedMentions
+		}
+
+		_, err = a.session.InteractionResponseEdit(destination.Interaction, edit, requestOptions...)
+		if err != nil {
+			return fmt.Errorf("failed to edit response to interaction %s: %w", destination.Interaction.ID, err)
+		}
+		return nil
+	}
+
+	data, err := interactionResponseData(content)
+	if err != nil {
+		return err
+	}
+	data.Flags |= ephemeralFlag(options)
+	if data.AllowedMentions == nil {
+		data.AllowedMentions = a.config.DefaultAllowedMentions
+	}
+
+	err = a.session.InteractionRespond(destination.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: data,
+	}, requestOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to respond to interaction %s: %w", destination.Interaction.ID, err)
+	}
+	destination.responded = true
+
+	return nil
+}
+
+func ephemeralFlag(options *interactionRespOptions) discordgo.MessageFlags {
+	if options.ephemeral {
+		return discordgo.MessageFlagsEphemeral
+	}
+	return 0
+}
+
+// interactionResponseData converts the given response content to the data of an interaction response.
+func interactionResponseData(content any) (*discordgo.InteractionResponseData, error) {
+	switch typed := content.(type) {
+	case string:
+		return &discordgo.InteractionResponseData{Content: typed}, nil
+
+	case *discordgo.MessageSend:
+		return &discordgo.InteractionResponseData{
+			TTS:             typed.TTS,
+			Content:         typed.Content,
+			Embeds:          typed.Embeds,
+			Components:      typed.Components,
+			Files:           typed.Files,
+			AllowedMentions: typed.AllowedMentions,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected content type for interaction: %T", content)
+	}
+}
+
+// interactionEdit converts the given response content to an edit of a deferred interaction response.
+func interactionEdit(content any) (*discordgo.WebhookEdit, error) {
+	switch typed := content.(type) {
+	case string:
+		return &discordgo.WebhookEdit{Content: &typed}, nil
+
+	case *discordgo.MessageSend:
+		return &discordgo.WebhookEdit{
+			Content:         &typed.Content,
+			Embeds:          &typed.Embeds,
+			Components:      &typed.Components,
+			Files:           typed.Files,
+			AllowedMentions: typed.AllowedMentions,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected content type for interaction: %T", content)
+	}
+}
@@ -0,0 +1,512 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newCommandInteraction(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{
+		Interaction: &discordgo.Interaction{
+			ID:        "interaction-1",
+			Type:      discordgo.InteractionApplicationCommand,
+			ChannelID: "ch-1",
+			GuildID:   "guild-1",
+			Member:    &discordgo.Member{User: &discordgo.User{ID: "user-1"}},
+			Data: discordgo.ApplicationCommandInteractionData{
+				Name:        name,
+				CommandType: discordgo.ChatApplicationCommand,
+				Options:     options,
+			},
+		},
+	}
+}
+
+func TestAdapter_RegisterApplicationCommands(t *testing.T) {
+	t.Run("commands are registered with the application ID", func(t *testing.T) {
+		var appID, guildID string
+		mock := &mockSession{
+			applicationCommandBulkOverwriteFunc: func(a string, g string, commands []*discordgo.ApplicationCommand, _ ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+				appID, guildID = a, g
+				return commands, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		adapter.appID.Store("app-1")
+
+		commands := []*discordgo.ApplicationCommand{{Name: "echo", Description: "Echo the given text"}}
+		registered, err := adapter.RegisterApplicationCommands(context.Background(), "guild-1", commands)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if len(registered) != 1 || appID != "app-1" || guildID != "guild-1" {
+			t.Errorf("Unexpected registration: %#v to %s in %s", registered, appID, guildID)
+		}
+	})
+
+	t.Run("an error is returned before the session is opened", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		_, err := adapter.RegisterApplicationCommands(context.Background(), "", nil)
+		if !errors.Is(err, ErrUnknownApplication) {
+			t.Errorf("Expected ErrUnknownApplication, got %+v", err)
+		}
+	})
+
+	t.Run("an API error is returned", func(t *testing.T) {
+		apiErr := errors.New("api error")
+		mock := &mockSession{
+			applicationCommandBulkOverwriteFunc: func(_ string, _ string, _ []*discordgo.ApplicationCommand, _ ...discordgo.RequestOption) ([]*discordgo.ApplicationCommand, error) {
+				return nil, apiErr
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		adapter.appID.Store("app-1")
+
+		_, err := adapter.RegisterApplicationCommands(context.Background(), "", nil)
+		if !errors.Is(err, apiErr) {
+			t.Errorf("Expected the API error, got %+v", err)
+		}
+	})
+}
+
+func TestInteractionToInput(t *testing.T) {
+	t.Run("string options follow the command name", func(t *testing.T) {
+		i := newCommandInteraction("echo",
+			&discordgo.ApplicationCommandInteractionDataOption{Name: "text", Type: discordgo.ApplicationCommandOptionString, Value: "hello"},
+			&discordgo.ApplicationCommandInteractionDataOption{Name: "times", Type: discordgo.ApplicationCommandOptionInteger, Value: float64(2)},
+			&discordgo.ApplicationCommandInteractionDataOption{Name: "suffix", Type: discordgo.ApplicationCommandOptionString, Value: "world"},
+		)
+
+		input, err := InteractionToInput(i)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if input.Message() != "echo hello world" {
+			t.Errorf("Unexpected message: %s", input.Message())
+		}
+		if input.SenderKey() != senderKeyOf("ch-1", "user-1") {
+			t.Errorf("Unexpected sender key: %s", input.SenderKey())
+		}
+		if input.CommandName() != "echo" {
+			t.Errorf("Unexpected command name: %s", input.CommandName())
+		}
+		if value, ok := input.Option("text"); !ok || value != "hello" {
+			t.Errorf("Unexpected option: %s", value)
+		}
+		if _, ok := input.Option("times"); ok {
+			t.Error("Non-string option should not be included")
+		}
+		destination, ok := input.ReplyTo().(*InteractionDestination)
+		if !ok || destination.Interaction != i.Interaction {
+			t.Errorf("Unexpected destination: %#v", input.ReplyTo())
+		}
+		if input.CorrelationID() == "" {
+			t.Error("Expected a correlation ID")
+		}
+	})
+
+	t.Run("the user is used in direct messages", func(t *testing.T) {
+		i := newCommandInteraction("ping")
+		i.Member = nil
+		i.User = &discordgo.User{ID: "user-2"}
+
+		input, err := InteractionToInput(i)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if input.SenderKey() != senderKeyOf("ch-1", "user-2") {
+			t.Errorf("Unexpected sender key: %s", input.SenderKey())
+		}
+	})
+
+	t.Run("unsupported interactions are rejected", func(t *testing.T) {
+		user := newCommandInteraction("user")
+		user.Data = discordgo.ApplicationCommandInteractionData{Name: "user", CommandType: discordgo.UserApplicationCommand}
+		anonymous := newCommandInteraction("ping")
+		anonymous.Member = nil
+
+		for _, i := range []*discordgo.InteractionCreate{newSelection("id"), user, anonymous} {
+			if _, err := InteractionToInput(i); err == nil {
+				t.Errorf("Expected an error for %#v", i.Data)
+			}
+		}
+	})
+}
+
+func TestAdapter_handleInteraction(t *testing.T) {
+	adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+	var inputs []sarah.Input
+	enqueue := func(input sarah.Input) error {
+		inputs = append(inputs, input)
+		return nil
+	}
+
+	adapter.handleInteraction(context.Background(), newSelection(helpMenuCustomIDPrefix+"1:0", "0"), enqueue)
+	adapter.handleInteraction(context.Background(), newCommandInteraction("ping"), enqueue)
+
+	if len(inputs) != 1 || inputs[0].Message() != "ping" {
+		t.Errorf("Unexpected inputs: %#v", inputs)
+	}
+}
+
+func TestAdapter_handleInteraction_Filters(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		config   func(*Config)
+		store    GuildEnabledStore
+		enqueued bool
+	}{
+		{name: "allowed", ctx: context.Background(), enqueued: true},
+		{name: "shutdown", ctx: canceled},
+		{name: "blocked user", ctx: context.Background(), config: func(c *Config) { c.BlockedUsers = []string{"user-1"} }},
+		{name: "blocked channel", ctx: context.Background(), config: func(c *Config) { c.BlockedChannels = []string{"ch-1"} }},
+		{name: "channel not allowed", ctx: context.Background(), config: func(c *Config) { c.AllowedChannels = []string{"ch-2"} }},
+		{
+			name: "disabled guild",
+			ctx:  context.Background(),
+			store: func() GuildEnabledStore {
+				store := NewInMemoryGuildEnabledStore()
+				_ = store.SetEnabled("guild-1", false)
+				return store
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			if tt.config != nil {
+				tt.config(config)
+			}
+			adapter := &Adapter{config: config, session: &mockSession{}, guildEnabledStore: tt.store}
+
+			var inputs []sarah.Input
+			adapter.handleInteraction(tt.ctx, newCommandInteraction("ping"), func(input sarah.Input) error {
+				inputs = append(inputs, input)
+				return nil
+			})
+
+			if enqueued := len(inputs) == 1; enqueued != tt.enqueued {
+				t.Errorf("Expected the interaction to be enqueued: %t", tt.enqueued)
+			}
+			stats := adapter.Stats().Total
+			if stats.Received != 1 {
+				t.Errorf("Expected the interaction to be counted as received: %+v", stats)
+			}
+			if (tt.enqueued && stats.Enqueued != 1) || (!tt.enqueued && stats.Dropped != 1) {
+				t.Errorf("Unexpected stats: %+v", stats)
+			}
+		})
+	}
+}
+
+func TestAdapter_SendMessage_Interaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		content any
+		want    string
+		sent    bool
+	}{
+		{name: "text", content: "pong", want: "pong", sent: true},
+		{name: "message", content: &discordgo.MessageSend{Content: "pong", Embeds: []*discordgo.MessageEmbed{{Title: "t"}}}, want: "pong", sent: true},
+		{name: "unsupported", content: 1, sent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response *discordgo.InteractionResponse
+			mock := &mockSession{
+				interactionRespondFunc: func(_ *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+					response = resp
+					return nil
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			input, err := InteractionToInput(newCommandInteraction("ping"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), tt.content))
+
+			if !tt.sent {
+				if response != nil || adapter.Stats().Total.SendFailed != 1 {
+					t.Errorf("Expected a failure, got %#v", response)
+				}
+				return
+			}
+			if response == nil || response.Type != discordgo.InteractionResponseChannelMessageWithSource || response.Data.Content != tt.want {
+				t.Errorf("Unexpected response: %#v", response)
+			}
+			if adapter.Stats().Total.SendSucceeded != 1 {
+				t.Error("Expected a successful send")
+			}
+		})
+	}
+}
+
+func TestNewInteractionResponse(t *testing.T) {
+	t.Run("the options are carried with the content", func(t *testing.T) {
+		input, _ := InteractionToInput(newCommandInteraction("ping"))
+
+		response, err := NewInteractionResponse(input, "pong", InteractionRespEphemeral())
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		typed, ok := response.Content.(*interactionResponse)
+		if !ok || typed.Content != "pong" || !typed.options.ephemeral {
+			t.Errorf("Unexpected content: %#v", response.Content)
+		}
+	})
+
+	t.Run("message input is rejected", func(t *testing.T) {
+		if _, err := NewInteractionResponse(&Input{}, "pong"); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestRespEphemeral(t *testing.T) {
+	t.Run("interaction response is ephemeral", func(t *testing.T) {
+		var responses []*discordgo.InteractionResponse
+		mock := &mockSession{
+			interactionRespondFunc: func(_ *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+				responses = append(responses, resp)
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		input, _ := InteractionToInput(newCommandInteraction("ping"))
+
+		response, err := NewResponse(input, "pong", RespEphemeral(), RespWithEmbeds(&discordgo.MessageEmbed{Title: "OK"}))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+		if len(responses) != 1 {
+			t.Fatalf("Expected a single response: %#v", responses)
+		}
+		data := responses[0].Data
+		if data.Flags&discordgo.MessageFlagsEphemeral == 0 || data.Content != "pong" || len(data.Embeds) != 1 {
+			t.Errorf("Unexpected response data: %#v", data)
+		}
+	})
+
+	t.Run("interaction response without the option", func(t *testing.T) {
+		input, _ := InteractionToInput(newCommandInteraction("ping"))
+
+		response, err := NewResponse(input, "pong")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		typed, ok := response.Content.(*interactionResponse)
+		if !ok || typed.options.ephemeral {
+			t.Errorf("Unexpected content: %#v", response.Content)
+		}
+	})
+
+	t.Run("message input is rejected", func(t *testing.T) {
+		_, err := NewResponse(newReplyInput("guild-1"), "pong", RespEphemeral())
+		if !errors.Is(err, ErrEphemeralNotSupported) {
+			t.Errorf("Expected ErrEphemeralNotSupported, got %+v", err)
+		}
+	})
+}
+
+func TestNewResponse_InteractionOptions(t *testing.T) {
+	respond := func(t *testing.T, config *Config, interaction *discordgo.InteractionCreate, options ...RespOption) (*discordgo.InteractionResponse, []discordgo.RequestOption) {
+		var response *discordgo.InteractionResponse
+		var requestOptions []discordgo.RequestOption
+		mock := &mockSession{
+			interactionRespondFunc: func(_ *discordgo.Interaction, resp *discordgo.InteractionResponse, opts ...discordgo.RequestOption) error {
+				response, requestOptions = resp, opts
+				return nil
+			},
+		}
+		adapter := &Adapter{config: config, session: mock}
+		input, _ := InteractionToInput(interaction)
+
+		res, err := NewResponse(input, "pong", options...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), res.Content)); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if response == nil {
+			t.Fatal("Expected a response")
+		}
+		return response, requestOptions
+	}
+	newDMInteraction := func() *discordgo.InteractionCreate {
+		interaction := newCommandInteraction("ping")
+		interaction.GuildID = ""
+		interaction.User, interaction.Member = interaction.Member.User, nil
+		return interaction
+	}
+
+	t.Run("allowed mentions", func(t *testing.T) {
+		mentions := &discordgo.MessageAllowedMentions{Users: []string{"user-2"}}
+		response, _ := respond(t, NewConfig(), newCommandInteraction("ping"), RespWithAllowedMentions(mentions))
+		if response.Data.AllowedMentions != mentions {
+			t.Errorf("Expected the allowed mentions to be set: %#v", response.Data.AllowedMentions)
+		}
+	})
+
+	t.Run("default allowed mentions", func(t *testing.T) {
+		config := NewConfig()
+		config.DefaultAllowedMentions = &discordgo.MessageAllowedMentions{}
+		response, _ := respond(t, config, newCommandInteraction("ping"))
+		if response.Data.AllowedMentions != config.DefaultAllowedMentions {
+			t.Errorf("Expected the default allowed mentions to be set: %#v", response.Data.AllowedMentions)
+		}
+	})
+
+	t.Run("TTS", func(t *testing.T) {
+		response, _ := respond(t, NewConfig(), newCommandInteraction("ping"), RespWithTTS())
+		if !response.Data.TTS || response.Data.Content != "pong" {
+			t.Errorf("Expected a TTS response: %#v", response.Data)
+		}
+	})
+
+	t.Run("guild only in a direct message", func(t *testing.T) {
+		response, _ := respond(t, NewConfig(), newDMInteraction(), RespGuildOnly("Guild only."))
+		if response.Data.Content != "Guild only." {
+			t.Errorf("Expected the rejection message, got %q", response.Data.Content)
+		}
+	})
+
+	t.Run("guild only in a guild", func(t *testing.T) {
+		response, _ := respond(t, NewConfig(), newCommandInteraction("ping"), RespGuildOnly("Guild only."))
+		if response.Data.Content != "pong" {
+			t.Errorf("Expected the response content, got %q", response.Data.Content)
+		}
+	})
+
+	t.Run("DM only in a guild", func(t *testing.T) {
+		response, _ := respond(t, NewConfig(), newCommandInteraction("ping"), RespDMOnly("DM only."))
+		if response.Data.Content != "DM only." {
+			t.Errorf("Expected the rejection message, got %q", response.Data.Content)
+		}
+	})
+
+	t.Run("request options", func(t *testing.T) {
+		_, requestOptions := respond(t, NewConfig(), newCommandInteraction("ping"), RespWithRequestOptions(discordgo.WithHeader("X-Trace-Id", "trace-1")))
+		if applyRequestOptions(requestOptions).Request.Header.Get("X-Trace-Id") != "trace-1" {
+			t.Error("Expected the request options to be applied")
+		}
+	})
+}
+
+func TestAdapter_RespondInteraction(t *testing.T) {
+	t.Run("immediate response", func(t *testing.T) {
+		var responses []*discordgo.InteractionResponse
+		edited := false
+		mock := &mockSession{
+			interactionRespondFunc: func(_ *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+				responses = append(responses, resp)
+				return nil
+			},
+			interactionResponseEditFunc: func(_ *discordgo.Interaction, _ *discordgo.WebhookEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				edited = true
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		input, _ := InteractionToInput(newCommandInteraction("ping"))
+
+		response, _ := NewInteractionResponse(input, "pong", InteractionRespEphemeral())
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+		if len(responses) != 1 || edited {
+			t.Fatalf("Expected a single response without edits: %#v", responses)
+		}
+		if responses[0].Type != discordgo.InteractionResponseChannelMessageWithSource || responses[0].Data.Content != "pong" || responses[0].Data.Flags != discordgo.MessageFlagsEphemeral {
+			t.Errorf("Unexpected response: %#v", responses[0])
+		}
+	})
+
+	t.Run("deferred response is edited by the following response", func(t *testing.T) {
+		var responses []*discordgo.InteractionResponse
+		var edits []*discordgo.WebhookEdit
+		mock := &mockSession{
+			interactionRespondFunc: func(_ *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+				responses = append(responses, resp)
+				return nil
+			},
+			interactionResponseEditFunc: func(_ *discordgo.Interaction, edit *discordgo.WebhookEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				edits = append(edits, edit)
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		input, _ := InteractionToInput(newCommandInteraction("ping"))
+
+		err := adapter.RespondInteraction(context.Background(), input, nil, InteractionRespDeferred())
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if len(responses) != 1 || responses[0].Type != discordgo.InteractionResponseDeferredChannelMessageWithSource || len(edits) != 0 {
+			t.Fatalf("Unexpected deferral: %#v", responses)
+		}
+
+		response, _ := NewInteractionResponse(input, &discordgo.MessageSend{Content: "pong"})
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+		if len(responses) != 1 || len(edits) != 1 || *edits[0].Content != "pong" {
+			t.Errorf("Expected the deferred response to be edited: %#v", edits)
+		}
+	})
+
+	t.Run("deferral with content is followed by an edit", func(t *testing.T) {
+		var types []discordgo.InteractionResponseType
+		var edit *discordgo.WebhookEdit
+		mock := &mockSession{
+			interactionRespondFunc: func(_ *discordgo.Interaction, resp *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+				types = append(types, resp.Type)
+				return nil
+			},
+			interactionResponseEditFunc: func(_ *discordgo.Interaction, e *discordgo.WebhookEdit, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				edit = e
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		input, _ := InteractionToInput(newCommandInteraction("ping"))
+
+		err := adapter.RespondInteraction(context.Background(), input, "working", InteractionRespDeferred())
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if len(types) != 1 || edit == nil || *edit.Content != "working" {
+			t.Errorf("Unexpected responses: %v and %#v", types, edit)
+		}
+	})
+
+	t.Run("failed deferral is returned", func(t *testing.T) {
+		apiErr := errors.New("api error")
+		mock := &mockSession{
+			interactionRespondFunc: func(_ *discordgo.Interaction, _ *discordgo.InteractionResponse, _ ...discordgo.RequestOption) error {
+				return apiErr
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		input, _ := InteractionToInput(newCommandInteraction("ping"))
+
+		err := adapter.RespondInteraction(context.Background(), input, nil, InteractionRespDeferred())
+		if !errors.Is(err, apiErr) {
+			t.Errorf("Expected the API error, got %+v", err)
+		}
+	})
+}
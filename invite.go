@@ -0,0 +1,161 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// GuildInvites returns the active invites of the given guild.
+// The bot requires the Manage Server permission in the guild.
+func (a *Adapter) GuildInvites(ctx context.Context, guildID string) ([]*discordgo.Invite, error) {
+	invites, err := a.session.GuildInvites(guildID, a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch invites of guild %s: %w", guildID, err)
+	}
+	return invites, nil
+}
+
+// InviteTracker attributes a guild member's join to the invite that was used.
+// Discord does not tell which invite a new member used, so the tracker caches the use counts of each guild's invites
+// and compares them with the latest counts when a member joins.
+//
+// The bot requires the Manage Server permission and the GuildMembers intent.
+// When members join at almost the same time, the joins may not be distinguished and ErrInviteNotAttributed is returned.
+type InviteTracker struct {
+	adapter *Adapter
+	mutex   sync.Mutex
+	uses    map[string]map[string]*discordgo.Invite // Guild ID to invite code to invite
+}
+
+// NewInviteTracker creates a new InviteTracker that fetches invites via the given Adapter.
+func NewInviteTracker(adapter *Adapter) *InviteTracker {
+	return &InviteTracker{
+		adapter: adapter,
+		uses:    map[string]map[string]*discordgo.Invite{},
+	}
+}
+
+// Prime caches the current use counts of the given guild's invites.
+// This must be called before a member joins the guild so the join can be attributed.
+func (t *InviteTracker) Prime(ctx context.Context, guildID string) error {
+	invites, err := t.adapter.GuildInvites(ctx, guildID)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.uses[guildID] = indexInvites(invites)
+
+	return nil
+}
+
+// Attribute fetches the latest invites of the given guild and returns the invite whose use count was incremented since the last call.
+// ErrInviteNotAttributed is returned when no single invite can be determined.
+// The cached use counts are updated in any case.
+func (t *InviteTracker) Attribute(ctx context.Context, guildID string) (*discordgo.Invite, error) {
+	// Serialize the attributions so each join is compared with the state right before it.
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	invites, err := t.adapter.GuildInvites(ctx, guildID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, primed := t.uses[guildID]
+	current := indexInvites(invites)
+	t.uses[guildID] = current
+
+	if !primed {
+		return nil, fmt.Errorf("invites of guild %s are not primed: %w", guildID, ErrInviteNotAttributed)
+	}
+
+	used := diffInviteUses(previous, current)
+	if len(used) != 1 {
+		return nil, fmt.Errorf("%d invites were possibly used in guild %s: %w", len(used), guildID, ErrInviteNotAttributed)
+	}
+	return used[0], nil
+}
+
+// Register registers event handlers that prime the invites of each guild the bot joins or connects to,
+// attribute each member's join, and discard the counts of a guild the bot is removed from.
+// The callback is called with the joined member and the used invite, or with an error when the join cannot be attributed.
+// The returned function removes the handlers.
+func (t *InviteTracker) Register(callback func(member *discordgo.Member, invite *discordgo.Invite, err error)) func() {
+	removeGuildCreate := t.adapter.session.AddHandler(func(_ *discordgo.Session, g *discordgo.GuildCreate) {
+		if err := t.Prime(context.Background(), g.ID); err != nil {
+			t.adapter.log().Warnf("Failed to prime invites of guild %s: %+v", g.ID, err)
+		}
+	})
+
+	removeMemberAdd := t.adapter.session.AddHandler(func(_ *discordgo.Session, m *discordgo.GuildMemberAdd) {
+		invite, err := t.Attribute(context.Background(), m.GuildID)
+		callback(m.Member, invite, err)
+	})
+
+	removeGuildDelete := t.adapter.session.AddHandler(func(_ *discordgo.Session, g *discordgo.GuildDelete) {
+		// Keep the counts while the guild is unavailable due to an outage.
+		if g.Guild != nil && !g.Unavailable {
+			t.forget(g.ID)
+		}
+	})
+
+	return func() {
+		removeGuildCreate()
+		removeMemberAdd()
+		removeGuildDelete()
+	}
+}
+
+// forget discards the cached use counts of the given guild.
+func (t *InviteTracker) forget(guildID string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.uses, guildID)
+}
+
+func indexInvites(invites []*discordgo.Invite) map[string]*discordgo.Invite {
+	indexed := make(map[string]*discordgo.Invite, len(invites))
+	for _, invite := range invites {
+		indexed[invite.Code] = invite
+	}
+	return indexed
+}
+
+// diffInviteUses returns the invites that were possibly used between the previous and current states.
+// An invite is returned as many times as its use count was incremented, so multiple joins make the attribution ambiguous.
+// An invite that disappeared is also regarded as used when it was one use away from its limit,
+// because Discord deletes an invite when it reaches its maximum uses.
+func diffInviteUses(previous map[string]*discordgo.Invite, current map[string]*discordgo.Invite) []*discordgo.Invite {
+	var used []*discordgo.Invite
+
+	for code, invite := range current {
+		before := 0
+		if prev, ok := previous[code]; ok {
+			before = prev.Uses
+		}
+
+		for i := before; i < invite.Uses; i++ {
+			used = append(used, invite)
+		}
+	}
+
+	for code, prev := range previous {
+		if _, ok := current[code]; ok {
+			continue
+		}
+
+		if prev.MaxUses > 0 && prev.Uses+1 == prev.MaxUses {
+			consumed := *prev
+			consumed.Uses = prev.MaxUses
+			used = append(used, &consumed)
+		}
+	}
+
+	return used
+}
@@ -0,0 +1,227 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAdapter_GuildInvites(t *testing.T) {
+	t.Run("invites are returned", func(t *testing.T) {
+		mock := &mockSession{
+			guildInvitesFunc: func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Invite, error) {
+				if guildID != "guild-1" {
+					t.Errorf("Unexpected guild: %s", guildID)
+				}
+				return []*discordgo.Invite{{Code: "abc"}}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		invites, err := adapter.GuildInvites(context.Background(), "guild-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if len(invites) != 1 || invites[0].Code != "abc" {
+			t.Errorf("Unexpected invites: %#v", invites)
+		}
+	})
+
+	t.Run("error is wrapped", func(t *testing.T) {
+		apiErr := errors.New("missing permissions")
+		mock := &mockSession{
+			guildInvitesFunc: func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Invite, error) {
+				return nil, apiErr
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		_, err := adapter.GuildInvites(context.Background(), "guild-1")
+		if !errors.Is(err, apiErr) {
+			t.Errorf("Expected the API error, got %+v", err)
+		}
+	})
+}
+
+func TestInviteTracker(t *testing.T) {
+	var invites []*discordgo.Invite
+	mock := &mockSession{
+		guildInvitesFunc: func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Invite, error) {
+			return invites, nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+	ctx := context.Background()
+
+	t.Run("not primed", func(t *testing.T) {
+		tracker := NewInviteTracker(adapter)
+		invites = []*discordgo.Invite{{Code: "a", Uses: 1}}
+
+		_, err := tracker.Attribute(ctx, "guild-1")
+		if !errors.Is(err, ErrInviteNotAttributed) {
+			t.Errorf("Expected ErrInviteNotAttributed, got %+v", err)
+		}
+	})
+
+	t.Run("incremented invite is attributed", func(t *testing.T) {
+		tracker := NewInviteTracker(adapter)
+		invites = []*discordgo.Invite{{Code: "a", Uses: 1}, {Code: "b", Uses: 5}}
+		if err := tracker.Prime(ctx, "guild-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		invites = []*discordgo.Invite{{Code: "a", Uses: 1}, {Code: "b", Uses: 6}}
+		invite, err := tracker.Attribute(ctx, "guild-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if invite.Code != "b" {
+			t.Errorf("Expected invite b, got %s", invite.Code)
+		}
+
+		// The next join is compared with the updated counts.
+		invites = []*discordgo.Invite{{Code: "a", Uses: 2}, {Code: "b", Uses: 6}}
+		invite, err = tracker.Attribute(ctx, "guild-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if invite.Code != "a" {
+			t.Errorf("Expected invite a, got %s", invite.Code)
+		}
+	})
+
+	t.Run("new invite is attributed", func(t *testing.T) {
+		tracker := NewInviteTracker(adapter)
+		invites = []*discordgo.Invite{}
+		_ = tracker.Prime(ctx, "guild-1")
+
+		invites = []*discordgo.Invite{{Code: "new", Uses: 1}}
+		invite, err := tracker.Attribute(ctx, "guild-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if invite.Code != "new" {
+			t.Errorf("Expected invite new, got %s", invite.Code)
+		}
+	})
+
+	t.Run("invite deleted on reaching its limit is attributed", func(t *testing.T) {
+		tracker := NewInviteTracker(adapter)
+		invites = []*discordgo.Invite{{Code: "once", Uses: 0, MaxUses: 1}, {Code: "other", Uses: 3}}
+		_ = tracker.Prime(ctx, "guild-1")
+
+		invites = []*discordgo.Invite{{Code: "other", Uses: 3}}
+		invite, err := tracker.Attribute(ctx, "guild-1")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if invite.Code != "once" || invite.Uses != 1 {
+			t.Errorf("Expected the consumed invite, got %#v", invite)
+		}
+	})
+
+	t.Run("ambiguous joins", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			before []*discordgo.Invite
+			after  []*discordgo.Invite
+		}{
+			{
+				name:   "no change",
+				before: []*discordgo.Invite{{Code: "a", Uses: 1}},
+				after:  []*discordgo.Invite{{Code: "a", Uses: 1}},
+			},
+			{
+				name:   "multiple invites used",
+				before: []*discordgo.Invite{{Code: "a", Uses: 1}, {Code: "b", Uses: 1}},
+				after:  []*discordgo.Invite{{Code: "a", Uses: 2}, {Code: "b", Uses: 2}},
+			},
+			{
+				name:   "one invite used twice",
+				before: []*discordgo.Invite{{Code: "a", Uses: 1}},
+				after:  []*discordgo.Invite{{Code: "a", Uses: 3}},
+			},
+			{
+				name:   "revoked invite",
+				before: []*discordgo.Invite{{Code: "a", Uses: 1, MaxUses: 10}},
+				after:  []*discordgo.Invite{},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tracker := NewInviteTracker(adapter)
+				invites = tt.before
+				_ = tracker.Prime(ctx, "guild-1")
+
+				invites = tt.after
+				_, err := tracker.Attribute(ctx, "guild-1")
+				if !errors.Is(err, ErrInviteNotAttributed) {
+					t.Errorf("Expected ErrInviteNotAttributed, got %+v", err)
+				}
+			})
+		}
+	})
+}
+
+func TestInviteTracker_Register(t *testing.T) {
+	var guildCreate func(*discordgo.Session, *discordgo.GuildCreate)
+	var memberAdd func(*discordgo.Session, *discordgo.GuildMemberAdd)
+	var guildDelete func(*discordgo.Session, *discordgo.GuildDelete)
+	invites := []*discordgo.Invite{{Code: "a", Uses: 1}}
+	mock := &mockSession{
+		addHandlerFunc: func(handler interface{}) func() {
+			switch h := handler.(type) {
+			case func(*discordgo.Session, *discordgo.GuildCreate):
+				guildCreate = h
+			case func(*discordgo.Session, *discordgo.GuildMemberAdd):
+				memberAdd = h
+			case func(*discordgo.Session, *discordgo.GuildDelete):
+				guildDelete = h
+			}
+			return func() {}
+		},
+		guildInvitesFunc: func(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Invite, error) {
+			return invites, nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+	tracker := NewInviteTracker(adapter)
+
+	var joined *discordgo.Member
+	var used *discordgo.Invite
+	tracker.Register(func(member *discordgo.Member, invite *discordgo.Invite, err error) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		joined = member
+		used = invite
+	})
+
+	if guildCreate == nil || memberAdd == nil || guildDelete == nil {
+		t.Fatal("Expected handlers to be registered")
+	}
+
+	guildCreate(nil, &discordgo.GuildCreate{Guild: &discordgo.Guild{ID: "guild-1"}})
+	invites = []*discordgo.Invite{{Code: "a", Uses: 2}}
+	memberAdd(nil, &discordgo.GuildMemberAdd{Member: &discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "user-1"}}})
+
+	if joined == nil || joined.User.ID != "user-1" {
+		t.Errorf("Unexpected member: %#v", joined)
+	}
+	if used == nil || used.Code != "a" {
+		t.Errorf("Unexpected invite: %#v", used)
+	}
+
+	guildDelete(nil, &discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "guild-1", Unavailable: true}})
+	if _, ok := tracker.uses["guild-1"]; !ok {
+		t.Error("Expected the counts to be kept during an outage")
+	}
+
+	guildDelete(nil, &discordgo.GuildDelete{Guild: &discordgo.Guild{ID: "guild-1"}})
+	if _, ok := tracker.uses["guild-1"]; ok {
+		t.Error("Expected the counts to be discarded on removal")
+	}
+}
@@ -0,0 +1,109 @@
+package discord
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// resolveInvocation resolves the command text of the given message and tells if the message explicitly invokes the bot.
+//
+// The resolution takes the following precedence:
+//
+//  1. If the message starts with Config.CommandPrefix, the prefix is stripped.
+//  2. If the message starts with the bot's mention, the mention is stripped along with the following prefix, if any.
+//  3. If the message is a direct message, the message is used as-is since it is addressed to the bot anyway.
+//  4. Otherwise, the message is used as-is but is not considered as an invocation.
+func (a *Adapter) resolveInvocation(s *discordgo.Session, m *discordgo.MessageCreate) (string, bool) {
+	if text, ok := a.stripInvocation(s, m.Content); ok {
+		return text, true
+	}
+
+	if m.GuildID == "" {
+		return m.Content, true
+	}
+
+	return m.Content, false
+}
+
+// stripInvocation strips Config.CommandPrefix or the bot's mention from the given text.
+// Unlike resolveInvocation, false is returned for a direct message without such a prefix.
+func (a *Adapter) stripInvocation(s *discordgo.Session, content string) (string, bool) {
+	trimmed := strings.TrimSpace(content)
+
+	if text, ok := a.stripPrefix(trimmed); ok {
+		return text, true
+	}
+
+	if text, ok := stripMention(trimmed, a.selfID(s)); ok {
+		if stripped, ok := a.stripPrefix(text); ok {
+			text = stripped
+		}
+		return text, true
+	}
+
+	return "", false
+}
+
+// stripMention strips the leading mention of the given user from the given text.
+func stripMention(text string, userID string) (string, bool) {
+	if userID == "" {
+		return text, false
+	}
+
+	for _, mention := range []string{"<@" + userID + ">", "<@!" + userID + ">"} {
+		if strings.HasPrefix(text, mention) {
+			return strings.TrimSpace(text[len(mention):]), true
+		}
+	}
+	return text, false
+}
+
+// StripBotMention returns the content of the input message without the bot's leading mention, e.g., "ping" for "@bot ping".
+// The trimmed content is returned as it is when the message does not start with the bot's mention.
+// Unlike Input.Message, Config.CommandPrefix is kept.
+func StripBotMention(input *Input) string {
+	if input.Event == nil || input.Event.Message == nil {
+		return ""
+	}
+
+	text, _ := stripMention(strings.TrimSpace(input.Event.Content), input.botID)
+	return text
+}
+
+// stripPrefix strips Config.CommandPrefix from the given text.
+func (a *Adapter) stripPrefix(text string) (string, bool) {
+	prefix := a.config.CommandPrefix
+	if prefix == "" || !strings.HasPrefix(text, prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(text[len(prefix):]), true
+}
+
+// fetchBotUser stores the bot's user ID so the bot's own messages are recognized even when the session state is disabled.
+// The session state is used instead when the fetch fails.
+func (a *Adapter) fetchBotUser(ctx context.Context) {
+	user, err := a.session.User("@me", a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		a.log().Warnf("Failed to fetch the bot user. Falling back to the session state: %+v", err)
+		return
+	}
+	a.botID.Store(user.ID)
+}
+
+// selfID returns the bot's user ID fetched on Run, or the one in the session state if not fetched.
+func (a *Adapter) selfID(s *discordgo.Session) string {
+	if id, ok := a.botID.Load().(string); ok && id != "" {
+		return id
+	}
+	return botUserID(s)
+}
+
+// botUserID returns the bot's user ID from the session state.
+func botUserID(s *discordgo.Session) string {
+	if s == nil || s.State == nil || s.State.User == nil {
+		return ""
+	}
+	return s.State.User.ID
+}
@@ -0,0 +1,344 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_resolveInvocation(t *testing.T) {
+	botID := "bot-user-123"
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: botID}
+
+	tests := []struct {
+		name    string
+		prefix  string
+		guildID string
+		content string
+		text    string
+		invoked bool
+	}{
+		{name: "prefix only", prefix: "!", guildID: "guild-1", content: "!ping", text: "ping", invoked: true},
+		{name: "prefix with spaces", prefix: "!", guildID: "guild-1", content: "  ! ping  ", text: "ping", invoked: true},
+		{name: "mention only", prefix: "!", guildID: "guild-1", content: "<@" + botID + "> ping", text: "ping", invoked: true},
+		{name: "nickname mention", prefix: "!", guildID: "guild-1", content: "<@!" + botID + "> ping", text: "ping", invoked: true},
+		{name: "mention followed by prefix", prefix: "!", guildID: "guild-1", content: "<@" + botID + "> !ping", text: "ping", invoked: true},
+		{name: "prefix takes precedence over mention", prefix: "!", guildID: "guild-1", content: "!<@" + botID + "> ping", text: "<@" + botID + "> ping", invoked: true},
+		{name: "mention without prefix configured", prefix: "", guildID: "guild-1", content: "<@" + botID + "> ping", text: "ping", invoked: true},
+		{name: "neither", prefix: "!", guildID: "guild-1", content: "ping", text: "ping", invoked: false},
+		{name: "other user's mention", prefix: "!", guildID: "guild-1", content: "<@someone> ping", text: "<@someone> ping", invoked: false},
+		{name: "mention in the middle", prefix: "!", guildID: "guild-1", content: "hey <@" + botID + ">", text: "hey <@" + botID + ">", invoked: false},
+		{name: "DM without prefix", prefix: "!", guildID: "", content: "ping", text: "ping", invoked: true},
+		{name: "DM with prefix", prefix: "!", guildID: "", content: "!ping", text: "ping", invoked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.CommandPrefix = tt.prefix
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "ch-1",
+					GuildID:   tt.guildID,
+					Content:   tt.content,
+					Author:    &discordgo.User{ID: "user-1"},
+				},
+			}
+
+			text, invoked := adapter.resolveInvocation(s, m)
+			if text != tt.text {
+				t.Errorf("Expected text %q, got %q", tt.text, text)
+			}
+			if invoked != tt.invoked {
+				t.Errorf("Expected invoked to be %t, got %t", tt.invoked, invoked)
+			}
+		})
+	}
+
+	t.Run("session without state", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+		m := &discordgo.MessageCreate{
+			Message: &discordgo.Message{GuildID: "guild-1", Content: "<@" + botID + "> ping"},
+		}
+
+		_, invoked := adapter.resolveInvocation(&discordgo.Session{}, m)
+		if invoked {
+			t.Error("Expected the mention not to be recognized without state")
+		}
+	})
+}
+
+func TestAdapter_handleMessage_Invocation(t *testing.T) {
+	botID := "bot-user-123"
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: botID}
+
+	newMessage := func(guildID, content string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "ch-1",
+				GuildID:   guildID,
+				Content:   content,
+				Timestamp: time.Now(),
+				Author:    &discordgo.User{ID: "user-1"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		requireInvocation bool
+		guildID           string
+		content           string
+		expected          interface{}
+		message           string
+	}{
+		{name: "prefixed message is stripped", requireInvocation: true, guildID: "guild-1", content: "!echo hi", expected: &Input{}, message: "echo hi"},
+		{name: "mentioned message is stripped", requireInvocation: true, guildID: "guild-1", content: "<@" + botID + "> echo hi", expected: &Input{}, message: "echo hi"},
+		{name: "non-invocation is dropped", requireInvocation: true, guildID: "guild-1", content: "echo hi", expected: nil},
+		{name: "non-invocation is enqueued raw", requireInvocation: false, guildID: "guild-1", content: "echo hi", expected: &Input{}, message: "echo hi"},
+		{name: "DM is enqueued without prefix", requireInvocation: true, guildID: "", content: "echo hi", expected: &Input{}, message: "echo hi"},
+		{name: "help after mention", requireInvocation: true, guildID: "guild-1", content: "<@" + botID + "> .help", expected: &sarah.HelpInput{}},
+		{name: "abort after prefix", requireInvocation: true, guildID: "guild-1", content: "!.abort", expected: &sarah.AbortInput{}},
+		{name: "help without prefix in guild", requireInvocation: false, guildID: "guild-1", content: ".help", expected: &Input{}, message: ".help"},
+		{name: "help without prefix in DM", requireInvocation: false, guildID: "", content: ".help", expected: &sarah.HelpInput{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.CommandPrefix = "!"
+			config.RequireInvocation = tt.requireInvocation
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			var received sarah.Input
+			adapter.handleMessage(context.Background(), s, newMessage(tt.guildID, tt.content), func(input sarah.Input) error {
+				received = input
+				return nil
+			})
+
+			switch tt.expected.(type) {
+			case nil:
+				if received != nil {
+					t.Errorf("Expected the message to be dropped, got %T", received)
+				}
+
+			case *Input:
+				typed, ok := received.(*Input)
+				if !ok {
+					t.Fatalf("Expected *Input, got %T", received)
+				}
+				if typed.Message() != tt.message {
+					t.Errorf("Expected message %q, got %q", tt.message, typed.Message())
+				}
+
+			case *sarah.HelpInput:
+				if _, ok := received.(*sarah.HelpInput); !ok {
+					t.Errorf("Expected *sarah.HelpInput, got %T", received)
+				}
+
+			case *sarah.AbortInput:
+				if _, ok := received.(*sarah.AbortInput); !ok {
+					t.Errorf("Expected *sarah.AbortInput, got %T", received)
+				}
+			}
+		})
+	}
+}
+
+func TestAdapter_handleMessage_PrefixedHelp(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name         string
+		helpCommand  string
+		content      string
+		help         bool
+		expectedText string
+	}{
+		{name: "prefixed help", helpCommand: "help", content: "!help", help: true},
+		{name: "unprefixed help", helpCommand: "help", content: "help", help: false, expectedText: "help"},
+		{name: "help including prefix", helpCommand: "!help", content: "!help", help: true},
+		{name: "regular command", helpCommand: "help", content: "!echo hi", help: false, expectedText: "echo hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.CommandPrefix = "!"
+			config.HelpCommand = tt.helpCommand
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			var received sarah.Input
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "ch-1",
+					GuildID:   "guild-1",
+					Content:   tt.content,
+					Author:    &discordgo.User{ID: "user-1"},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(input sarah.Input) error {
+				received = input
+				return nil
+			})
+
+			if tt.help {
+				if _, ok := received.(*sarah.HelpInput); !ok {
+					t.Errorf("Expected *sarah.HelpInput, got %T", received)
+				}
+				return
+			}
+
+			typed, ok := received.(*Input)
+			if !ok {
+				t.Fatalf("Expected *Input, got %T", received)
+			}
+			if typed.Message() != tt.expectedText {
+				t.Errorf("Expected message %q, got %q", tt.expectedText, typed.Message())
+			}
+			if typed.Invoked() != strings.HasPrefix(tt.content, "!") {
+				t.Errorf("Unexpected Invoked: %t", typed.Invoked())
+			}
+		})
+	}
+}
+
+func TestAdapter_selfID(t *testing.T) {
+	stateSession := func(id string) *discordgo.Session {
+		s := &discordgo.Session{State: discordgo.NewState()}
+		s.State.User = &discordgo.User{ID: id}
+		return s
+	}
+
+	t.Run("fetched user is used without state", func(t *testing.T) {
+		mock := &mockSession{
+			userFunc: func(userID string, _ ...discordgo.RequestOption) (*discordgo.User, error) {
+				if userID != "@me" {
+					t.Errorf("Unexpected user ID: %s", userID)
+				}
+				return &discordgo.User{ID: "bot-1"}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		adapter.fetchBotUser(context.Background())
+
+		if id := adapter.selfID(&discordgo.Session{}); id != "bot-1" {
+			t.Errorf("Expected the fetched ID, got %q", id)
+		}
+
+		enqueued := false
+		m := &discordgo.MessageCreate{Message: &discordgo.Message{ChannelID: "ch-1", Content: "hello", Author: &discordgo.User{ID: "bot-1"}}}
+		adapter.handleMessage(context.Background(), &discordgo.Session{}, m, func(sarah.Input) error {
+			enqueued = true
+			return nil
+		})
+		if enqueued {
+			t.Error("Expected the bot's own message to be ignored without state")
+		}
+	})
+
+	t.Run("state is used when the fetch fails", func(t *testing.T) {
+		mock := &mockSession{
+			userFunc: func(_ string, _ ...discordgo.RequestOption) (*discordgo.User, error) {
+				return nil, errors.New("api error")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+		adapter.fetchBotUser(context.Background())
+
+		if id := adapter.selfID(stateSession("bot-state")); id != "bot-state" {
+			t.Errorf("Expected the ID in the state, got %q", id)
+		}
+		if id := adapter.selfID(nil); id != "" {
+			t.Errorf("Expected no ID, got %q", id)
+		}
+	})
+
+	t.Run("bot user is fetched on Run", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mock := &mockSession{
+			userFunc: func(_ string, _ ...discordgo.RequestOption) (*discordgo.User, error) {
+				cancel()
+				return &discordgo.User{ID: "bot-1"}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		adapter.Run(ctx, func(sarah.Input) error { return nil }, func(error) {})
+
+		if id := adapter.selfID(nil); id != "bot-1" {
+			t.Errorf("Expected the fetched ID, got %q", id)
+		}
+	})
+}
+
+func TestAdapter_handleMessage_MentionsMe(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name       string
+		content    string
+		mentions   []*discordgo.User
+		mentionsMe bool
+		stripped   string
+	}{
+		{name: "leading mention", content: "<@bot-1> ping", mentions: []*discordgo.User{{ID: "bot-1"}}, mentionsMe: true, stripped: "ping"},
+		{name: "leading nickname mention", content: "<@!bot-1>  .ping", mentions: []*discordgo.User{{ID: "bot-1"}}, mentionsMe: true, stripped: ".ping"},
+		{name: "mention in the middle", content: "hey <@bot-1>", mentions: []*discordgo.User{{ID: "bot-1"}}, mentionsMe: true, stripped: "hey <@bot-1>"},
+		{name: "other user", content: "<@user-2> ping", mentions: []*discordgo.User{{ID: "user-2"}}, mentionsMe: false, stripped: "<@user-2> ping"},
+		{name: "no mention", content: "ping", mentionsMe: false, stripped: "ping"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+			var received sarah.Input
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "ch-1",
+					GuildID:   "guild-1",
+					Content:   tt.content,
+					Mentions:  tt.mentions,
+					Author:    &discordgo.User{ID: "user-1"},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(input sarah.Input) error {
+				received = input
+				return nil
+			})
+
+			input, ok := received.(*Input)
+			if !ok {
+				t.Fatalf("Expected *Input, got %T", received)
+			}
+			if input.MentionsMe() != tt.mentionsMe {
+				t.Errorf("Expected MentionsMe to be %t", tt.mentionsMe)
+			}
+			if stripped := StripBotMention(input); stripped != tt.stripped {
+				t.Errorf("Expected %q, got %q", tt.stripped, stripped)
+			}
+		})
+	}
+
+	t.Run("input not created by the adapter", func(t *testing.T) {
+		input, _ := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{Content: "<@bot-1> ping", Mentions: []*discordgo.User{{ID: "bot-1"}}, Author: &discordgo.User{ID: "user-1"}},
+		})
+
+		if input.MentionsMe() || StripBotMention(input) != "<@bot-1> ping" {
+			t.Error("Expected the bot not to be known")
+		}
+	})
+}
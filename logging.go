@@ -0,0 +1,51 @@
+package discord
+
+import (
+	"github.com/oklahomer/go-kasumi/logger"
+)
+
+// Logger defines an interface that the Adapter logs through.
+// Pass an implementation via WithLogger to route the Adapter's logs into your own logging stack.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+	Errorf(format string, args ...any)
+}
+
+// WithLogger creates an AdapterOption with the given Logger.
+// If this option is not given, the Adapter logs through go-kasumi's logger package.
+func WithLogger(l Logger) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.logger = l
+	}
+}
+
+// kasumiLogger is the default Logger that delegates to go-kasumi's package-level functions.
+type kasumiLogger struct{}
+
+var _ Logger = kasumiLogger{}
+
+func (kasumiLogger) Debugf(format string, args ...any) {
+	logger.Debugf(format, args...)
+}
+
+func (kasumiLogger) Infof(format string, args ...any) {
+	logger.Infof(format, args...)
+}
+
+func (kasumiLogger) Warnf(format string, args ...any) {
+	logger.Warnf(format, args...)
+}
+
+func (kasumiLogger) Errorf(format string, args ...any) {
+	logger.Errorf(format, args...)
+}
+
+// log returns the Logger given via WithLogger, or the default one.
+func (a *Adapter) log() Logger {
+	if a == nil || a.logger == nil {
+		return kasumiLogger{}
+	}
+	return a.logger
+}
@@ -0,0 +1,107 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+type capturingLogger struct {
+	mutex   sync.Mutex
+	entries []string
+}
+
+func (l *capturingLogger) record(level string, format string, args ...any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.entries = append(l.entries, level+": "+fmt.Sprintf(format, args...))
+}
+
+func (l *capturingLogger) Debugf(format string, args ...any) { l.record("DEBUG", format, args...) }
+func (l *capturingLogger) Infof(format string, args ...any)  { l.record("INFO", format, args...) }
+func (l *capturingLogger) Warnf(format string, args ...any)  { l.record("WARN", format, args...) }
+func (l *capturingLogger) Errorf(format string, args ...any) { l.record("ERROR", format, args...) }
+
+func (l *capturingLogger) has(level string, substr string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, entry := range l.entries {
+		if strings.HasPrefix(entry, level+": ") && strings.Contains(entry, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWithLogger(t *testing.T) {
+	l := &capturingLogger{}
+
+	adapter, err := NewAdapter(NewConfig(), WithSession(&discordgo.Session{}), WithLogger(l))
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	if adapter.log() != l {
+		t.Error("Expected the given logger to be used")
+	}
+}
+
+func TestAdapter_log(t *testing.T) {
+	t.Run("default logger", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig()}
+		if _, ok := adapter.log().(kasumiLogger); !ok {
+			t.Errorf("Expected the default logger, got %T", adapter.log())
+		}
+
+		var nilAdapter *Adapter
+		if _, ok := nilAdapter.log().(kasumiLogger); !ok {
+			t.Errorf("Expected the default logger for nil, got %T", nilAdapter.log())
+		}
+	})
+
+	t.Run("levels", func(t *testing.T) {
+		l := &capturingLogger{}
+		mock := &mockSession{
+			channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return nil, errors.New("api error")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock, logger: l}
+		s := &discordgo.Session{State: discordgo.NewState()}
+		s.State.User = &discordgo.User{ID: "bot-1"}
+
+		// A system message without an author is skipped at the debug level.
+		adapter.handleMessage(context.Background(), s, &discordgo.MessageCreate{Message: &discordgo.Message{ChannelID: "ch-1"}}, func(sarah.Input) error { return nil })
+		if !l.has("DEBUG", "Skipping message") {
+			t.Errorf("Expected a debug log: %v", l.entries)
+		}
+
+		// A failed enqueue is an error.
+		m := &discordgo.MessageCreate{Message: &discordgo.Message{ChannelID: "ch-1", Content: "hello", Author: &discordgo.User{ID: "user-1"}}}
+		adapter.handleMessage(context.Background(), s, m, func(sarah.Input) error { return errors.New("queue is full") })
+		if !l.has("ERROR", "queue is full") {
+			t.Errorf("Expected an error log: %v", l.entries)
+		}
+
+		// A failed send is an error.
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+		if !l.has("ERROR", "api error") {
+			t.Errorf("Expected an error log: %v", l.entries)
+		}
+
+		// A failed fetch of the bot user falls back with a warning.
+		mock.userFunc = func(_ string, _ ...discordgo.RequestOption) (*discordgo.User, error) {
+			return nil, errors.New("unauthorized")
+		}
+		adapter.fetchBotUser(context.Background())
+		if !l.has("WARN", "unauthorized") {
+			t.Errorf("Expected a warning log: %v", l.entries)
+		}
+	})
+}
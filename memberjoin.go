@@ -0,0 +1,143 @@
+package discord
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// MemberJoinInput is a sarah.Input implementation that represents a member joining a guild.
+// This is passed to go-sarah only when Config.HandleMemberJoin is true.
+// The message is empty since a join has no content, so handle this with a command whose matcher checks the input type.
+type MemberJoinInput struct {
+	Event       *discordgo.GuildMemberAdd
+	senderKey   string
+	sentAt      time.Time
+	destination sarah.OutputDestination
+
+	correlationID string
+}
+
+var _ sarah.Input = (*MemberJoinInput)(nil)
+
+// SenderKey returns a unique key representing the joined member in the guild.
+func (i *MemberJoinInput) SenderKey() string {
+	return i.senderKey
+}
+
+// Message returns an empty string since a join has no content.
+func (i *MemberJoinInput) Message() string {
+	return ""
+}
+
+// SentAt returns when the member joined.
+func (i *MemberJoinInput) SentAt() time.Time {
+	return i.sentAt
+}
+
+// ReplyTo returns the guild's system channel, where Discord posts its own welcome messages.
+// When the guild has no system channel, the response is sent to the joined member as a direct message.
+func (i *MemberJoinInput) ReplyTo() sarah.OutputDestination {
+	return i.destination
+}
+
+// GuildID returns the ID of the joined guild.
+func (i *MemberJoinInput) GuildID() string {
+	return i.Event.GuildID
+}
+
+// User returns the joined user.
+func (i *MemberJoinInput) User() *discordgo.User {
+	return i.Event.User
+}
+
+// CorrelationID returns the ID that correlates the logs and outgoing messages caused by this input.
+func (i *MemberJoinInput) CorrelationID() string {
+	return i.correlationID
+}
+
+// GuildMemberAddToInput converts the given member join to *MemberJoinInput.
+// Pass the guild's system channel ID, or an empty string when the guild has none.
+func GuildMemberAddToInput(m *discordgo.GuildMemberAdd, systemChannelID string) (*MemberJoinInput, error) {
+	if m.Member == nil || m.User == nil {
+		return nil, ErrNoAuthor
+	}
+
+	var destination sarah.OutputDestination = UserID(m.User.ID)
+	if systemChannelID != "" {
+		destination = ChannelID(systemChannelID)
+	}
+
+	sentAt := m.JoinedAt
+	if sentAt.IsZero() {
+		sentAt = time.Now()
+	}
+
+	return &MemberJoinInput{
+		Event:       m,
+		senderKey:   senderKeyOf(m.GuildID, m.User.ID),
+		sentAt:      sentAt,
+		destination: destination,
+
+		correlationID: newCorrelationID(),
+	}, nil
+}
+
+// handleMemberJoin passes the joined member to go-sarah.
+// Joins received after the given context is canceled are dropped.
+func (a *Adapter) handleMemberJoin(ctx context.Context, m *discordgo.GuildMemberAdd, enqueueInput func(sarah.Input) error) {
+	a.messageReceived()
+
+	if ctx.Err() != nil {
+		a.log().Debugf("Skipping member join to %s received during shutdown", m.GuildID)
+		a.messageDropped(ctx.Err())
+		return
+	}
+
+	if m.Member == nil || m.User == nil {
+		a.log().Debugf("Skipping member join without user: %#v", m)
+		a.messageDropped(nil)
+		return
+	}
+
+	if a.config.IgnoreBots && m.User.Bot {
+		a.messageDropped(nil)
+		return
+	}
+
+	if a.userBlocked(m.User.ID) {
+		a.log().Debugf("Ignoring member join of blocked user %s to %s", m.User.ID, m.GuildID)
+		a.messageDropped(nil)
+		return
+	}
+
+	if !a.guildIDEnabled(m.GuildID) {
+		a.messageDropped(nil)
+		return
+	}
+
+	// Fall back to the direct message rather than dropping the join when the guild is not available.
+	var systemChannelID string
+	if guild, err := a.guild(m.GuildID); err != nil {
+		a.log().Warnf("Failed to fetch guild %s to find its system channel: %+v", m.GuildID, err)
+	} else {
+		systemChannelID = guild.SystemChannelID
+	}
+
+	input, err := GuildMemberAddToInput(m, systemChannelID)
+	if err != nil {
+		a.log().Debugf("Skipping member join: %+v", err)
+		a.messageDropped(err)
+		return
+	}
+
+	if err := enqueueInput(input); err != nil {
+		a.enqueueFailed(input, input.correlationID, "member join", err)
+		a.messageDropped(err)
+		return
+	}
+	a.log().Debugf("[%s] Enqueued member join of %s to %s", input.correlationID, m.User.ID, m.GuildID)
+	a.messageEnqueued()
+}
@@ -0,0 +1,176 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newMemberAdd(userID string) *discordgo.GuildMemberAdd {
+	return &discordgo.GuildMemberAdd{
+		Member: &discordgo.Member{
+			GuildID: "guild-1",
+			User:    &discordgo.User{ID: userID},
+		},
+	}
+}
+
+func TestGuildMemberAddToInput(t *testing.T) {
+	t.Run("system channel", func(t *testing.T) {
+		input, err := GuildMemberAddToInput(newMemberAdd("user-1"), "ch-system")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if input.Message() != "" || input.GuildID() != "guild-1" || input.User().ID != "user-1" {
+			t.Errorf("Unexpected input: %#v", input)
+		}
+		if input.ReplyTo() != ChannelID("ch-system") {
+			t.Errorf("Expected the system channel, got %#v", input.ReplyTo())
+		}
+		if input.SenderKey() != senderKeyOf("guild-1", "user-1") {
+			t.Errorf("Unexpected sender key: %s", input.SenderKey())
+		}
+	})
+
+	t.Run("no system channel", func(t *testing.T) {
+		input, err := GuildMemberAddToInput(newMemberAdd("user-1"), "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if input.ReplyTo() != UserID("user-1") {
+			t.Errorf("Expected the joined user, got %#v", input.ReplyTo())
+		}
+	})
+
+	t.Run("without user", func(t *testing.T) {
+		if _, err := GuildMemberAddToInput(&discordgo.GuildMemberAdd{Member: &discordgo.Member{}}, ""); !errors.Is(err, ErrNoAuthor) {
+			t.Errorf("Expected ErrNoAuthor, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_handleMemberJoin(t *testing.T) {
+	tests := []struct {
+		name  string
+		guild func(string, ...discordgo.RequestOption) (*discordgo.Guild, error)
+		want  sarah.OutputDestination
+	}{
+		{
+			name: "system channel",
+			guild: func(guildID string, _ ...discordgo.RequestOption) (*discordgo.Guild, error) {
+				return &discordgo.Guild{ID: guildID, SystemChannelID: "ch-system"}, nil
+			},
+			want: ChannelID("ch-system"),
+		},
+		{
+			name: "no system channel",
+			guild: func(guildID string, _ ...discordgo.RequestOption) (*discordgo.Guild, error) {
+				return &discordgo.Guild{ID: guildID}, nil
+			},
+			want: UserID("user-1"),
+		},
+		{
+			name: "guild lookup failure",
+			guild: func(_ string, _ ...discordgo.RequestOption) (*discordgo.Guild, error) {
+				return nil, errors.New("api error")
+			},
+			want: UserID("user-1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := &Adapter{config: NewConfig(), session: &mockSession{guildFunc: tt.guild}}
+
+			var enqueued sarah.Input
+			adapter.handleMemberJoin(context.Background(), newMemberAdd("user-1"), func(input sarah.Input) error {
+				enqueued = input
+				return nil
+			})
+
+			input, ok := enqueued.(*MemberJoinInput)
+			if !ok {
+				t.Fatalf("Expected *MemberJoinInput, got %T", enqueued)
+			}
+			if input.ReplyTo() != tt.want {
+				t.Errorf("Expected %#v, got %#v", tt.want, input.ReplyTo())
+			}
+		})
+	}
+
+	t.Run("blocked users are ignored", func(t *testing.T) {
+		config := NewConfig()
+		config.BlockedUsers = []string{"user-1"}
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		adapter.handleMemberJoin(context.Background(), newMemberAdd("user-1"), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+	})
+
+	t.Run("bots are ignored", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+		m := newMemberAdd("bot-2")
+		m.User.Bot = true
+
+		adapter.handleMemberJoin(context.Background(), m, func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+	})
+
+	t.Run("joins to a disabled guild are ignored", func(t *testing.T) {
+		store := NewInMemoryGuildEnabledStore()
+		_ = store.SetEnabled("guild-1", false)
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}, guildEnabledStore: store}
+
+		adapter.handleMemberJoin(context.Background(), newMemberAdd("user-1"), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+	})
+
+	t.Run("joins during shutdown are ignored", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		adapter.handleMemberJoin(ctx, newMemberAdd("user-1"), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+		if adapter.Stats().Total.Dropped != 1 {
+			t.Errorf("Expected the join to be counted as dropped: %#v", adapter.Stats().Total)
+		}
+	})
+
+	t.Run("handler is registered on Run only when enabled", func(t *testing.T) {
+		for _, handleMemberJoin := range []bool{false, true} {
+			var registered bool
+			mock := &mockSession{
+				addHandlerFunc: func(handler interface{}) func() {
+					if _, ok := handler.(func(*discordgo.Session, *discordgo.GuildMemberAdd)); ok {
+						registered = true
+					}
+					return func() {}
+				},
+				openFunc: func() error {
+					return errors.New("stop here")
+				},
+			}
+			config := NewConfig()
+			config.HandleMemberJoin = handleMemberJoin
+			adapter := &Adapter{config: config, session: mock}
+
+			adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+			if registered != handleMemberJoin {
+				t.Errorf("Expected registration to be %t", handleMemberJoin)
+			}
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var snowflakePattern = regexp.MustCompile(`^\d+$`)
+
+// messageURLHosts lists the hosts that serve message jump URLs.
+var messageURLHosts = map[string]struct{}{
+	"discord.com":           {},
+	"www.discord.com":       {},
+	"ptb.discord.com":       {},
+	"canary.discord.com":    {},
+	"discordapp.com":        {},
+	"www.discordapp.com":    {},
+	"ptb.discordapp.com":    {},
+	"canary.discordapp.com": {},
+}
+
+// MessageLink represents the location of a message that a jump URL points to.
+type MessageLink struct {
+	// GuildID is the ID of the guild. This is empty for a message in a direct message channel.
+	GuildID string
+
+	// ChannelID is the ID of the channel.
+	ChannelID ChannelID
+
+	// MessageID is the ID of the message.
+	MessageID string
+}
+
+// ParseMessageURL parses a message jump URL such as https://discord.com/channels/{guild}/{channel}/{message}.
+// The "@me" form for direct messages and the legacy discordapp.com host are also supported.
+// ErrInvalidMessageURL is returned when the given string is not a message jump URL.
+func ParseMessageURL(rawURL string) (*MessageLink, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMessageURL, err)
+	}
+
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidMessageURL, u.Scheme)
+	}
+
+	if _, ok := messageURLHosts[strings.ToLower(u.Hostname())]; !ok {
+		return nil, fmt.Errorf("%w: unsupported host %q", ErrInvalidMessageURL, u.Host)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) != 4 || segments[0] != "channels" {
+		return nil, fmt.Errorf("%w: unexpected path %q", ErrInvalidMessageURL, u.Path)
+	}
+
+	guildID, channelID, messageID := segments[1], segments[2], segments[3]
+	if guildID == "@me" {
+		guildID = ""
+	} else if !snowflakePattern.MatchString(guildID) {
+		return nil, fmt.Errorf("%w: invalid guild ID %q", ErrInvalidMessageURL, guildID)
+	}
+
+	if !snowflakePattern.MatchString(channelID) {
+		return nil, fmt.Errorf("%w: invalid channel ID %q", ErrInvalidMessageURL, channelID)
+	}
+
+	if !snowflakePattern.MatchString(messageID) {
+		return nil, fmt.Errorf("%w: invalid message ID %q", ErrInvalidMessageURL, messageID)
+	}
+
+	return &MessageLink{
+		GuildID:   guildID,
+		ChannelID: ChannelID(channelID),
+		MessageID: messageID,
+	}, nil
+}
+
+// MessageFromURL fetches the message that the given jump URL points to.
+// See ParseMessageURL for the supported URL formats.
+// ErrMessageNotFound or ErrMessageInaccessible is returned when the message does not exist or the bot cannot read it.
+func (a *Adapter) MessageFromURL(ctx context.Context, rawURL string) (*discordgo.Message, error) {
+	link, err := ParseMessageURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := a.session.ChannelMessage(string(link.ChannelID), link.MessageID, a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return nil, messageError("fetch", link.ChannelID, link.MessageID, err)
+	}
+
+	return message, nil
+}
+
+// EditMessage replaces the content of the message that the bot sent before.
+// The edit is retried and rate limited as a send is, and is logged instead with Config.DryRun.
+// The error is returned as-is so the caller can retry; ErrMessageNotFound or ErrMessageInaccessible is returned
+// when the message no longer exists or the bot cannot edit it.
+func (a *Adapter) EditMessage(ctx context.Context, channelID ChannelID, messageID string, content string) error {
+	if a.config.DryRun {
+		a.log().Infof("[dry run] Edit message %s in %s: %s", messageID, channelID, content)
+		return nil
+	}
+
+	err := a.retrySend(ctx, func() error {
+		_, err := a.session.ChannelMessageEdit(string(channelID), messageID, content, a.requestOptions(discordgo.WithContext(ctx))...)
+		return err
+	})
+	if err != nil {
+		return messageError("edit", channelID, messageID, err)
+	}
+	return nil
+}
+
+// DeleteMessage deletes the given message.
+// The error is returned so the caller can tell an already deleted message, which wraps ErrMessageNotFound,
+// from a lack of permission, which wraps ErrMessageInaccessible.
+// Pass ModerationReason to record why the message was deleted in the audit log.
+func (a *Adapter) DeleteMessage(ctx context.Context, channelID ChannelID, messageID string, options ...ModerationOption) error {
+	err := a.session.ChannelMessageDelete(string(channelID), messageID, a.moderationRequestOptions(ctx, options)...)
+	if err != nil {
+		return messageError("delete", channelID, messageID, err)
+	}
+	return nil
+}
+
+// maxMessagesPerFetch is the maximum number of messages Discord returns in a single request.
+const maxMessagesPerFetch = 100
+
+// ChannelMessages returns up to limit messages in the channel, newest first, e.g., to summarize or review the recent conversation.
+// Only the messages sent before beforeID are returned unless beforeID is empty.
+// Discord returns at most 100 messages per request, so a larger limit is fetched page by page until the limit is reached or the history is exhausted.
+func (a *Adapter) ChannelMessages(ctx context.Context, channelID ChannelID, limit int, beforeID string) ([]*discordgo.Message, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive: %d", limit)
+	}
+
+	var messages []*discordgo.Message
+	for len(messages) < limit {
+		size := min(limit-len(messages), maxMessagesPerFetch)
+		page, err := a.session.ChannelMessages(string(channelID), size, beforeID, "", "", a.requestOptions(discordgo.WithContext(ctx))...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch messages in %s: %w", channelID, err)
+		}
+
+		messages = append(messages, page...)
+		if len(page) < size {
+			// No more messages are left.
+			break
+		}
+		beforeID = page[len(page)-1].ID
+	}
+
+	return messages, nil
+}
+
+// PinMessage pins the given message in the channel.
+// Discord allows 50 pinned messages per channel, so an error wrapping ErrTooManyPins is returned when the limit is reached.
+// ErrMessageNotFound or ErrMessageInaccessible is wrapped as well, as EditMessage does.
+// Pass ModerationReason to record why the message was pinned in the audit log.
+func (a *Adapter) PinMessage(ctx context.Context, channelID ChannelID, messageID string, options ...ModerationOption) error {
+	err := a.session.ChannelMessagePin(string(channelID), messageID, a.moderationRequestOptions(ctx, options)...)
+	if err != nil {
+		var restErr *discordgo.RESTError
+		if errors.As(err, &restErr) && restErr.Message != nil && restErr.Message.Code == discordgo.ErrCodeMaximumPinsReached {
+			return fmt.Errorf("%w: %w", ErrTooManyPins, err)
+		}
+		return messageError("pin", channelID, messageID, err)
+	}
+	return nil
+}
+
+// UnpinMessage unpins the given message in the channel.
+// ErrMessageNotFound or ErrMessageInaccessible is wrapped as EditMessage does.
+// Pass ModerationReason to record why the message was unpinned in the audit log.
+func (a *Adapter) UnpinMessage(ctx context.Context, channelID ChannelID, messageID string, options ...ModerationOption) error {
+	err := a.session.ChannelMessageUnpin(string(channelID), messageID, a.moderationRequestOptions(ctx, options)...)
+	if err != nil {
+		return messageError("unpin", channelID, messageID, err)
+	}
+	return nil
+}
+
+// maxBulkDeleteMessages is the maximum number of messages Discord deletes in a single bulk deletion.
+const maxBulkDeleteMessages = 100
+
+// maxBulkDeleteAge is the age of the oldest message that Discord deletes in a bulk deletion.
+const maxBulkDeleteAge = 14 * 24 * time.Hour
+
+// BulkDeleteMessages deletes the given messages, e.g., for a purge command.
+// The messages are deleted in batches of 100 as Discord accepts, and the deletion stops at the first failed batch.
+// Discord does not bulk delete messages older than 14 days, so an error wrapping ErrMessageTooOld is returned without deleting anything
+// when any of the messages is that old. Delete such messages one by one with DeleteMessage instead.
+// Pass ModerationReason to record why the messages were deleted in the audit log.
+func (a *Adapter) BulkDeleteMessages(ctx context.Context, channelID ChannelID, messageIDs []string, options ...ModerationOption) error {
+	var tooOld []string
+	for _, id := range messageIDs {
+		sentAt, err := discordgo.SnowflakeTimestamp(id)
+		if err != nil {
+			return fmt.Errorf("invalid message ID %q: %w", id, err)
+		}
+		if time.Since(sentAt) >= maxBulkDeleteAge {
+			tooOld = append(tooOld, id)
+		}
+	}
+	if len(tooOld) > 0 {
+		return fmt.Errorf("%w: %d messages in %s are older than 14 days: %s", ErrMessageTooOld, len(tooOld), channelID, strings.Join(tooOld, ", "))
+	}
+
+	for batch := range slices.Chunk(messageIDs, maxBulkDeleteMessages) {
+		err := a.session.ChannelMessagesBulkDelete(string(channelID), batch, a.moderationRequestOptions(ctx, options)...)
+		if err != nil {
+			return fmt.Errorf("failed to bulk delete %d messages in %s: %w", len(batch), channelID, err)
+		}
+	}
+	return nil
+}
+
+// messageError wraps the given error of a message operation.
+// ErrMessageNotFound or ErrMessageInaccessible is wrapped as well when Discord responds with 404 or 403.
+func messageError(action string, channelID ChannelID, messageID string, err error) error {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		switch restErr.Response.StatusCode {
+		case http.StatusNotFound:
+			return fmt.Errorf("%w: %w", ErrMessageNotFound, err)
+
+		case http.StatusForbidden:
+			return fmt.Errorf("%w: %w", ErrMessageInaccessible, err)
+		}
+	}
+	return fmt.Errorf("failed to %s message %s in %s: %w", action, messageID, channelID, err)
+}
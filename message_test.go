@@ -0,0 +1,534 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestParseMessageURL(t *testing.T) {
+	valid := []struct {
+		name string
+		url  string
+		link MessageLink
+	}{
+		{
+			name: "guild message",
+			url:  "https://discord.com/channels/111/222/333",
+			link: MessageLink{GuildID: "111", ChannelID: "222", MessageID: "333"},
+		},
+		{
+			name: "direct message",
+			url:  "https://discord.com/channels/@me/222/333",
+			link: MessageLink{GuildID: "", ChannelID: "222", MessageID: "333"},
+		},
+		{
+			name: "legacy host",
+			url:  "https://discordapp.com/channels/111/222/333",
+			link: MessageLink{GuildID: "111", ChannelID: "222", MessageID: "333"},
+		},
+		{
+			name: "canary host with trailing slash",
+			url:  "https://canary.discord.com/channels/111/222/333/",
+			link: MessageLink{GuildID: "111", ChannelID: "222", MessageID: "333"},
+		},
+		{
+			name: "surrounding spaces",
+			url:  "  https://ptb.discord.com/channels/111/222/333  ",
+			link: MessageLink{GuildID: "111", ChannelID: "222", MessageID: "333"},
+		},
+	}
+
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := ParseMessageURL(tt.url)
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if *link != tt.link {
+				t.Errorf("Expected %+v, got %+v", tt.link, *link)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name string
+		url  string
+	}{
+		{name: "empty", url: ""},
+		{name: "unparsable", url: "https://discord.com/%zz"},
+		{name: "no scheme", url: "discord.com/channels/111/222/333"},
+		{name: "unsupported scheme", url: "ftp://discord.com/channels/111/222/333"},
+		{name: "other host", url: "https://example.com/channels/111/222/333"},
+		{name: "look-alike host", url: "https://discord.com.example.com/channels/111/222/333"},
+		{name: "channel URL", url: "https://discord.com/channels/111/222"},
+		{name: "too many segments", url: "https://discord.com/channels/111/222/333/444"},
+		{name: "wrong path", url: "https://discord.com/invite/111/222/333"},
+		{name: "non-numeric guild", url: "https://discord.com/channels/abc/222/333"},
+		{name: "non-numeric channel", url: "https://discord.com/channels/111/abc/333"},
+		{name: "non-numeric message", url: "https://discord.com/channels/111/222/abc"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMessageURL(tt.url)
+			if !errors.Is(err, ErrInvalidMessageURL) {
+				t.Errorf("Expected ErrInvalidMessageURL, got %+v", err)
+			}
+		})
+	}
+}
+
+func TestAdapter_MessageFromURL(t *testing.T) {
+	newRESTError := func(status int) error {
+		return &discordgo.RESTError{Response: &http.Response{StatusCode: status, Status: http.StatusText(status)}}
+	}
+
+	t.Run("fetches the message", func(t *testing.T) {
+		mock := &mockSession{
+			channelMessageFunc: func(channelID, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				if channelID != "222" || messageID != "333" {
+					t.Errorf("Unexpected target: %s/%s", channelID, messageID)
+				}
+				return &discordgo.Message{ID: messageID, ChannelID: channelID, Content: "found"}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		message, err := adapter.MessageFromURL(context.Background(), "https://discord.com/channels/111/222/333")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if message.Content != "found" {
+			t.Errorf("Unexpected message: %+v", message)
+		}
+	})
+
+	t.Run("malformed URL", func(t *testing.T) {
+		mock := &mockSession{
+			channelMessageFunc: func(channelID, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				t.Error("ChannelMessage should not be called for malformed URL")
+				return nil, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		_, err := adapter.MessageFromURL(context.Background(), "https://discord.com/channels/111")
+		if !errors.Is(err, ErrInvalidMessageURL) {
+			t.Errorf("Expected ErrInvalidMessageURL, got %+v", err)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "unknown message", err: newRESTError(http.StatusNotFound), expected: ErrMessageNotFound},
+		{name: "inaccessible message", err: newRESTError(http.StatusForbidden), expected: ErrMessageInaccessible},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSession{
+				channelMessageFunc: func(channelID, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+					return nil, tt.err
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			_, err := adapter.MessageFromURL(context.Background(), "https://discord.com/channels/111/222/333")
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, err)
+			}
+
+			var restErr *discordgo.RESTError
+			if !errors.As(err, &restErr) {
+				t.Error("Expected the original error to be wrapped")
+			}
+		})
+	}
+
+	t.Run("other error", func(t *testing.T) {
+		fetchErr := errors.New("network error")
+		mock := &mockSession{
+			channelMessageFunc: func(channelID, messageID string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return nil, fetchErr
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		_, err := adapter.MessageFromURL(context.Background(), "https://discord.com/channels/111/222/333")
+		if !errors.Is(err, fetchErr) {
+			t.Errorf("Expected the fetch error to be wrapped, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_EditMessage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var edited string
+		mock := &mockSession{
+			channelMessageEditFunc: func(channelID string, messageID string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				if channelID != "ch-1" || messageID != "msg-1" {
+					t.Errorf("Unexpected target: %s/%s", channelID, messageID)
+				}
+				edited = content
+				return &discordgo.Message{ID: messageID}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.EditMessage(context.Background(), ChannelID("ch-1"), "msg-1", "50%"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if edited != "50%" {
+			t.Errorf("Expected content %q, got %q", "50%", edited)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "deleted message", err: &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusNotFound}}, expected: ErrMessageNotFound},
+		{name: "forbidden", err: &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}, expected: ErrMessageInaccessible},
+		{name: "other failure", err: errors.New("timeout"), expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSession{
+				channelMessageEditFunc: func(_ string, _ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+					return nil, tt.err
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			err := adapter.EditMessage(context.Background(), ChannelID("ch-1"), "msg-1", "50%")
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected the original error to be wrapped, got %+v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %+v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestAdapter_DeleteMessage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var deletedChannelID, deletedMessageID string
+		mock := &mockSession{
+			channelMessageDeleteFunc: func(channelID string, messageID string, _ ...discordgo.RequestOption) error {
+				deletedChannelID = channelID
+				deletedMessageID = messageID
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.DeleteMessage(context.Background(), ChannelID("ch-1"), "msg-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if deletedChannelID != "ch-1" || deletedMessageID != "msg-1" {
+			t.Errorf("Unexpected target: %s/%s", deletedChannelID, deletedMessageID)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "already deleted", err: &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusNotFound}}, expected: ErrMessageNotFound},
+		{name: "missing permission", err: &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}, expected: ErrMessageInaccessible},
+		{name: "other failure", err: errors.New("timeout"), expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSession{
+				channelMessageDeleteFunc: func(_ string, _ string, _ ...discordgo.RequestOption) error {
+					return tt.err
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			err := adapter.DeleteMessage(context.Background(), ChannelID("ch-1"), "msg-1")
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected the original error to be wrapped, got %+v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %+v", tt.expected, err)
+			}
+			if tt.expected == nil && (errors.Is(err, ErrMessageNotFound) || errors.Is(err, ErrMessageInaccessible)) {
+				t.Errorf("Unexpected classification: %+v", err)
+			}
+		})
+	}
+}
+
+func TestAdapter_PinMessage(t *testing.T) {
+	tooManyPins := &discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusBadRequest},
+		Message:  &discordgo.APIErrorMessage{Code: discordgo.ErrCodeMaximumPinsReached, Message: "Maximum number of pins reached (50)"},
+	}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "pinned", err: nil, expected: nil},
+		{name: "too many pins", err: tooManyPins, expected: ErrTooManyPins},
+		{name: "unknown message", err: &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusNotFound}}, expected: ErrMessageNotFound},
+		{name: "missing permission", err: &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}, expected: ErrMessageInaccessible},
+		{name: "other failure", err: errors.New("timeout"), expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockSession{
+				channelMessagePinFunc: func(channelID string, messageID string, _ ...discordgo.RequestOption) error {
+					if channelID != "ch-1" || messageID != "msg-1" {
+						t.Errorf("Unexpected target: %s/%s", channelID, messageID)
+					}
+					return tt.err
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			err := adapter.PinMessage(context.Background(), ChannelID("ch-1"), "msg-1")
+			if tt.err == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %+v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected the original error to be wrapped, got %+v", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %+v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestAdapter_UnpinMessage(t *testing.T) {
+	t.Run("unpinned", func(t *testing.T) {
+		unpinned := false
+		mock := &mockSession{
+			channelMessageUnpinFunc: func(channelID string, messageID string, _ ...discordgo.RequestOption) error {
+				unpinned = channelID == "ch-1" && messageID == "msg-1"
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.UnpinMessage(context.Background(), ChannelID("ch-1"), "msg-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if !unpinned {
+			t.Error("Expected the message to be unpinned")
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		restErr := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+		mock := &mockSession{
+			channelMessageUnpinFunc: func(_ string, _ string, _ ...discordgo.RequestOption) error {
+				return restErr
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		err := adapter.UnpinMessage(context.Background(), ChannelID("ch-1"), "msg-1")
+		if !errors.Is(err, restErr) || !errors.Is(err, ErrMessageInaccessible) {
+			t.Errorf("Unexpected error: %+v", err)
+		}
+	})
+}
+
+// snowflakeAt returns a message ID that Discord would assign to a message sent at the given time.
+func snowflakeAt(sentAt time.Time, sequence int) string {
+	const discordEpoch = 1420070400000
+	return strconv.FormatInt((sentAt.UnixMilli()-discordEpoch)<<22|int64(sequence), 10)
+}
+
+func TestAdapter_BulkDeleteMessages(t *testing.T) {
+	t.Run("messages are deleted in batches of 100", func(t *testing.T) {
+		var batches [][]string
+		mock := &mockSession{
+			channelMessagesBulkDeleteFunc: func(channelID string, messages []string, _ ...discordgo.RequestOption) error {
+				if channelID != "ch-1" {
+					t.Errorf("Unexpected channel: %s", channelID)
+				}
+				batches = append(batches, messages)
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		ids := make([]string, 250)
+		for i := range ids {
+			ids[i] = snowflakeAt(time.Now().Add(-time.Hour), i)
+		}
+
+		if err := adapter.BulkDeleteMessages(context.Background(), ChannelID("ch-1"), ids); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if len(batches) != 3 || len(batches[0]) != 100 || len(batches[1]) != 100 || len(batches[2]) != 50 {
+			t.Fatalf("Unexpected batches: %d", len(batches))
+		}
+		if batches[2][49] != ids[249] {
+			t.Errorf("Expected the messages to be deleted in the given order")
+		}
+	})
+
+	t.Run("old messages", func(t *testing.T) {
+		mock := &mockSession{
+			channelMessagesBulkDeleteFunc: func(_ string, _ []string, _ ...discordgo.RequestOption) error {
+				t.Error("ChannelMessagesBulkDelete should not be called")
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		old := snowflakeAt(time.Now().Add(-15*24*time.Hour), 0)
+		ids := []string{snowflakeAt(time.Now(), 0), old}
+
+		err := adapter.BulkDeleteMessages(context.Background(), ChannelID("ch-1"), ids)
+		if !errors.Is(err, ErrMessageTooOld) {
+			t.Fatalf("Expected ErrMessageTooOld, got %+v", err)
+		}
+		if !strings.Contains(err.Error(), old) {
+			t.Errorf("Expected the old message to be named: %+v", err)
+		}
+	})
+
+	t.Run("invalid message ID", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		if err := adapter.BulkDeleteMessages(context.Background(), ChannelID("ch-1"), []string{"msg-1"}); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("failed batch stops the deletion", func(t *testing.T) {
+		calls := 0
+		mock := &mockSession{
+			channelMessagesBulkDeleteFunc: func(_ string, _ []string, _ ...discordgo.RequestOption) error {
+				calls++
+				return errors.New("missing permission")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		ids := make([]string, 150)
+		for i := range ids {
+			ids[i] = snowflakeAt(time.Now(), i)
+		}
+
+		if err := adapter.BulkDeleteMessages(context.Background(), ChannelID("ch-1"), ids); err == nil {
+			t.Error("Expected an error")
+		}
+		if calls != 1 {
+			t.Errorf("Expected the deletion to stop at the first failure, got %d calls", calls)
+		}
+	})
+}
+
+func TestAdapter_ChannelMessages(t *testing.T) {
+	// history serves 250 messages with IDs from "249" to "0", newest first.
+	history := func(requested *[]int) func(string, int, string, string, string, ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+		return func(channelID string, limit int, beforeID string, _ string, _ string, _ ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+			*requested = append(*requested, limit)
+			if limit > 100 {
+				t.Errorf("Limit should be clamped to 100, got %d", limit)
+			}
+
+			next := 249
+			if beforeID != "" {
+				id, err := strconv.Atoi(beforeID)
+				if err != nil {
+					t.Fatalf("Unexpected beforeID: %s", beforeID)
+				}
+				next = id - 1
+			}
+
+			var page []*discordgo.Message
+			for id := next; id >= 0 && len(page) < limit; id-- {
+				page = append(page, &discordgo.Message{ID: strconv.Itoa(id), ChannelID: channelID})
+			}
+			return page, nil
+		}
+	}
+
+	tests := []struct {
+		name      string
+		limit     int
+		beforeID  string
+		expected  int
+		requested []int
+		lastID    string
+	}{
+		{name: "single page", limit: 30, expected: 30, requested: []int{30}, lastID: "220"},
+		{name: "clamped and paginated", limit: 150, expected: 150, requested: []int{100, 50}, lastID: "100"},
+		{name: "history exhausted", limit: 300, expected: 250, requested: []int{100, 100, 100}, lastID: "0"},
+		{name: "before the given message", limit: 10, beforeID: "50", expected: 10, requested: []int{10}, lastID: "40"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []int
+			mock := &mockSession{channelMessagesFunc: history(&requested)}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			messages, err := adapter.ChannelMessages(context.Background(), ChannelID("ch-1"), tt.limit, tt.beforeID)
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if len(messages) != tt.expected {
+				t.Fatalf("Expected %d messages, got %d", tt.expected, len(messages))
+			}
+			if messages[len(messages)-1].ID != tt.lastID {
+				t.Errorf("Expected the last message %s, got %s", tt.lastID, messages[len(messages)-1].ID)
+			}
+			if !slices.Equal(requested, tt.requested) {
+				t.Errorf("Expected requests with limits %v, got %v", tt.requested, requested)
+			}
+		})
+	}
+
+	t.Run("invalid limit", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+		if _, err := adapter.ChannelMessages(context.Background(), ChannelID("ch-1"), 0, ""); err == nil {
+			t.Error("Expected an error for a non-positive limit")
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		expected := errors.New("timeout")
+		mock := &mockSession{
+			channelMessagesFunc: func(_ string, _ int, _ string, _ string, _ string, _ ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+				return nil, expected
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if _, err := adapter.ChannelMessages(context.Background(), ChannelID("ch-1"), 10, ""); !errors.Is(err, expected) {
+			t.Errorf("Expected the original error to be wrapped, got %+v", err)
+		}
+	})
+}
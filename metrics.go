@@ -0,0 +1,76 @@
+package discord
+
+import (
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// MetricsHook observes the messages handled by the Adapter, e.g., to export them as counters to a monitoring system.
+// The methods are called from discordgo's event handlers and senders concurrently, so they must be safe for concurrent use and must not block.
+type MetricsHook interface {
+	// MessageReceived is called when a message is received from Discord.
+	MessageReceived()
+
+	// MessageEnqueued is called when a received message is passed to go-sarah.
+	MessageEnqueued()
+
+	// MessageDropped is called when a received message is not passed to go-sarah.
+	// The error is nil when the message is ignored on purpose, e.g., a message from the bot itself or from a blocked user,
+	// and non-nil when the message could not be handled, e.g., go-sarah's queue is full.
+	MessageDropped(err error)
+
+	// MessageSent is called when a message is sent to the destination, with the error when the send failed.
+	MessageSent(destination sarah.OutputDestination, err error)
+}
+
+// WithMetricsHook creates an AdapterOption with the given MetricsHook.
+// Unlike Adapter.Stats, which is polled, the hook is notified as each message is handled.
+func WithMetricsHook(hook MetricsHook) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.metricsHook = hook
+	}
+}
+
+// nopMetricsHook is the MetricsHook used when none is given via WithMetricsHook.
+type nopMetricsHook struct{}
+
+var _ MetricsHook = nopMetricsHook{}
+
+func (nopMetricsHook) MessageReceived() {}
+
+func (nopMetricsHook) MessageEnqueued() {}
+
+func (nopMetricsHook) MessageDropped(error) {}
+
+func (nopMetricsHook) MessageSent(sarah.OutputDestination, error) {}
+
+// metrics returns the MetricsHook given via WithMetricsHook, or the no-op one.
+func (a *Adapter) metrics() MetricsHook {
+	if a.metricsHook == nil {
+		return nopMetricsHook{}
+	}
+	return a.metricsHook
+}
+
+// messageReceived records a message received from Discord.
+func (a *Adapter) messageReceived() {
+	a.stats.received.increment()
+	a.metrics().MessageReceived()
+}
+
+// messageEnqueued records a received message passed to go-sarah.
+func (a *Adapter) messageEnqueued() {
+	a.stats.enqueued.increment()
+	a.metrics().MessageEnqueued()
+}
+
+// messageDropped records a received message that was not passed to go-sarah.
+func (a *Adapter) messageDropped(err error) {
+	a.stats.dropped.increment()
+	a.metrics().MessageDropped(err)
+}
+
+// messageSent records a message sent to the destination.
+func (a *Adapter) messageSent(destination sarah.OutputDestination, err error) {
+	a.stats.recordSend(err)
+	a.metrics().MessageSent(destination, err)
+}
@@ -0,0 +1,148 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+type countingMetricsHook struct {
+	mutex    sync.Mutex
+	received int
+	enqueued int
+	dropped  []error
+	sent     map[sarah.OutputDestination][]error
+}
+
+func (h *countingMetricsHook) MessageReceived() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.received++
+}
+
+func (h *countingMetricsHook) MessageEnqueued() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.enqueued++
+}
+
+func (h *countingMetricsHook) MessageDropped(err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.dropped = append(h.dropped, err)
+}
+
+func (h *countingMetricsHook) MessageSent(destination sarah.OutputDestination, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.sent == nil {
+		h.sent = map[sarah.OutputDestination][]error{}
+	}
+	h.sent[destination] = append(h.sent[destination], err)
+}
+
+func TestWithMetricsHook(t *testing.T) {
+	botID := "bot-1"
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: botID}
+
+	newMessage := func(authorID string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "ch-1",
+				Content:   "hello",
+				Timestamp: time.Now(),
+				Author:    &discordgo.User{ID: authorID},
+			},
+		}
+	}
+
+	sendErr := errors.New("send failed")
+	mock := &mockSession{
+		channelMessageSendFunc: func(channelID string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			if channelID == "ch-2" {
+				return nil, sendErr
+			}
+			return &discordgo.Message{}, nil
+		},
+	}
+	hook := &countingMetricsHook{}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+	WithMetricsHook(hook)(adapter)
+
+	queueFull := errors.New("queue full")
+	enqueue := func(sarah.Input) error { return nil }
+	adapter.handleMessage(context.Background(), s, newMessage("user-1"), enqueue)
+	adapter.handleMessage(context.Background(), s, newMessage("user-2"), enqueue)
+	adapter.handleMessage(context.Background(), s, newMessage(botID), enqueue)
+	adapter.handleMessage(context.Background(), s, newMessage("user-3"), func(sarah.Input) error { return queueFull })
+
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "ok"))
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-2"), "ng"))
+
+	if hook.received != 4 {
+		t.Errorf("Expected 4 received messages, got %d", hook.received)
+	}
+	if hook.enqueued != 2 {
+		t.Errorf("Expected 2 enqueued messages, got %d", hook.enqueued)
+	}
+	if len(hook.dropped) != 2 {
+		t.Fatalf("Expected 2 dropped messages, got %d", len(hook.dropped))
+	}
+	if hook.dropped[0] != nil {
+		t.Errorf("Expected no error for the ignored message, got %+v", hook.dropped[0])
+	}
+	if !errors.Is(hook.dropped[1], queueFull) {
+		t.Errorf("Expected the enqueue error, got %+v", hook.dropped[1])
+	}
+
+	if errs := hook.sent[ChannelID("ch-1")]; len(errs) != 1 || errs[0] != nil {
+		t.Errorf("Expected a successful send to ch-1, got %+v", errs)
+	}
+	if errs := hook.sent[ChannelID("ch-2")]; len(errs) != 1 || !errors.Is(errs[0], sendErr) {
+		t.Errorf("Expected a failed send to ch-2, got %+v", errs)
+	}
+
+	// The hook complements Stats rather than replacing it.
+	if adapter.Stats().Total.Received != 4 {
+		t.Errorf("Expected Stats to be recorded as well: %+v", adapter.Stats().Total)
+	}
+}
+
+func TestWithMetricsHook_Reaction(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	hook := &countingMetricsHook{}
+	config := NewConfig()
+	config.HandleReactions = true
+	adapter := &Adapter{config: config, session: &mockSession{}}
+	WithMetricsHook(hook)(adapter)
+
+	queueFull := errors.New("queue full")
+	adapter.handleReactionAdd(context.Background(), s, newReactionAdd("user-1", discordgo.Emoji{Name: "👍"}), func(sarah.Input) error { return nil })
+	adapter.handleReactionAdd(context.Background(), s, newReactionAdd("bot-1", discordgo.Emoji{Name: "👍"}), func(sarah.Input) error { return nil })
+	adapter.handleReactionAdd(context.Background(), s, newReactionAdd("user-2", discordgo.Emoji{Name: "👍"}), func(sarah.Input) error { return queueFull })
+
+	if hook.received != 3 {
+		t.Errorf("Expected 3 received reactions, got %d", hook.received)
+	}
+	if hook.enqueued != 1 {
+		t.Errorf("Expected 1 enqueued reaction, got %d", hook.enqueued)
+	}
+	if len(hook.dropped) != 2 || hook.dropped[0] != nil || !errors.Is(hook.dropped[1], queueFull) {
+		t.Errorf("Expected the reaction of the bot and the failed enqueue to be dropped, got %+v", hook.dropped)
+	}
+}
+
+func TestAdapter_metrics(t *testing.T) {
+	adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+	if _, ok := adapter.metrics().(nopMetricsHook); !ok {
+		t.Errorf("Expected the no-op hook by default, got %T", adapter.metrics())
+	}
+}
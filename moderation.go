@@ -0,0 +1,67 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ModerationOption customizes a moderation action such as deleting or pinning a message.
+type ModerationOption func(*moderationOptions)
+
+type moderationOptions struct {
+	reason string
+}
+
+// ModerationReason records the given reason in the guild's audit log so the action can be traced later.
+// Discord shows the reason with the bot as the actor, so include who requested the action, e.g., "Purged by @alice: spam".
+func ModerationReason(reason string) ModerationOption {
+	return func(options *moderationOptions) {
+		options.reason = reason
+	}
+}
+
+// maxBanDeleteMessageDays is the maximum number of days of messages Discord deletes on a ban.
+const maxBanDeleteMessageDays = 7
+
+// KickMember removes the given user from the guild. The user can join again with an invite.
+// The reason is recorded in the guild's audit log unless empty.
+// The error is returned so the caller can report a lack of the Kick Members permission.
+func (a *Adapter) KickMember(ctx context.Context, guildID string, userID string, reason string) error {
+	err := a.session.GuildMemberDeleteWithReason(guildID, userID, reason, a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return fmt.Errorf("failed to kick %s from guild %s: %w", userID, guildID, err)
+	}
+	return nil
+}
+
+// BanMember bans the given user from the guild, and deletes the user's messages sent in the last deleteMessageDays days.
+// deleteMessageDays must be between 0 and 7, where 0 keeps every message.
+// The reason is recorded in the guild's audit log unless empty.
+// The error is returned so the caller can report a lack of the Ban Members permission.
+func (a *Adapter) BanMember(ctx context.Context, guildID string, userID string, reason string, deleteMessageDays int) error {
+	if deleteMessageDays < 0 || deleteMessageDays > maxBanDeleteMessageDays {
+		return fmt.Errorf("days of messages to delete must be between 0 and %d: %d", maxBanDeleteMessageDays, deleteMessageDays)
+	}
+
+	err := a.session.GuildBanCreateWithReason(guildID, userID, reason, deleteMessageDays, a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return fmt.Errorf("failed to ban %s from guild %s: %w", userID, guildID, err)
+	}
+	return nil
+}
+
+// moderationRequestOptions returns the request options for a moderation action with the given options applied.
+func (a *Adapter) moderationRequestOptions(ctx context.Context, options []ModerationOption) []discordgo.RequestOption {
+	stash := &moderationOptions{}
+	for _, opt := range options {
+		opt(stash)
+	}
+
+	requestOptions := []discordgo.RequestOption{discordgo.WithContext(ctx)}
+	if stash.reason != "" {
+		requestOptions = append(requestOptions, discordgo.WithAuditLogReason(stash.reason))
+	}
+	return a.requestOptions(requestOptions...)
+}
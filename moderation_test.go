@@ -0,0 +1,163 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestModerationReason(t *testing.T) {
+	var received [][]discordgo.RequestOption
+	mock := &mockSession{
+		channelMessageDeleteFunc: func(_ string, _ string, options ...discordgo.RequestOption) error {
+			received = append(received, options)
+			return nil
+		},
+		channelMessagesBulkDeleteFunc: func(_ string, _ []string, options ...discordgo.RequestOption) error {
+			received = append(received, options)
+			return nil
+		},
+		channelMessagePinFunc: func(_ string, _ string, options ...discordgo.RequestOption) error {
+			received = append(received, options)
+			return nil
+		},
+		channelMessageUnpinFunc: func(_ string, _ string, options ...discordgo.RequestOption) error {
+			received = append(received, options)
+			return nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+	ctx := context.Background()
+	reason := ModerationReason("Requested by alice: spam")
+
+	if err := adapter.DeleteMessage(ctx, ChannelID("ch-1"), "msg-1", reason); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if err := adapter.BulkDeleteMessages(ctx, ChannelID("ch-1"), []string{snowflakeAt(time.Now(), 0), snowflakeAt(time.Now(), 1)}, reason); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if err := adapter.PinMessage(ctx, ChannelID("ch-1"), "msg-1", reason); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+	if err := adapter.UnpinMessage(ctx, ChannelID("ch-1"), "msg-1", reason); err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	if len(received) != 4 {
+		t.Fatalf("Expected 4 calls, got %d", len(received))
+	}
+	for i, options := range received {
+		if got := applyRequestOptions(options).Request.Header.Get("X-Audit-Log-Reason"); got != "Requested by alice: spam" {
+			t.Errorf("Expected the reason to be forwarded on call %d, got %q", i, got)
+		}
+	}
+
+	t.Run("without reason", func(t *testing.T) {
+		var options []discordgo.RequestOption
+		mock.channelMessageDeleteFunc = func(_ string, _ string, given ...discordgo.RequestOption) error {
+			options = given
+			return nil
+		}
+
+		if err := adapter.DeleteMessage(ctx, ChannelID("ch-1"), "msg-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if got := applyRequestOptions(options).Request.Header.Get("X-Audit-Log-Reason"); got != "" {
+			t.Errorf("Expected no reason, got %q", got)
+		}
+	})
+}
+
+func TestAdapter_KickMember(t *testing.T) {
+	t.Run("kicked", func(t *testing.T) {
+		var kicked []string
+		mock := &mockSession{
+			guildMemberDeleteWithReasonFunc: func(guildID string, userID string, reason string, _ ...discordgo.RequestOption) error {
+				kicked = []string{guildID, userID, reason}
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.KickMember(context.Background(), "guild-1", "user-1", "spam"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if len(kicked) != 3 || kicked[0] != "guild-1" || kicked[1] != "user-1" || kicked[2] != "spam" {
+			t.Errorf("Unexpected kick: %v", kicked)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		forbidden := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+		mock := &mockSession{
+			guildMemberDeleteWithReasonFunc: func(_ string, _ string, _ string, _ ...discordgo.RequestOption) error {
+				return forbidden
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		err := adapter.KickMember(context.Background(), "guild-1", "user-1", "")
+		if !errors.Is(err, forbidden) {
+			t.Errorf("Expected the original error to be wrapped, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_BanMember(t *testing.T) {
+	tests := []struct {
+		name   string
+		days   int
+		called bool
+	}{
+		{name: "keep messages", days: 0, called: true},
+		{name: "delete a week of messages", days: 7, called: true},
+		{name: "negative days", days: -1, called: false},
+		{name: "too many days", days: 8, called: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			mock := &mockSession{
+				guildBanCreateWithReasonFunc: func(guildID string, userID string, reason string, days int, _ ...discordgo.RequestOption) error {
+					called = true
+					if guildID != "guild-1" || userID != "user-1" || reason != "raid" || days != tt.days {
+						t.Errorf("Unexpected ban: %s/%s %q %d", guildID, userID, reason, days)
+					}
+					return nil
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			err := adapter.BanMember(context.Background(), "guild-1", "user-1", "raid", tt.days)
+			if called != tt.called {
+				t.Errorf("Expected the ban to be called: %t", tt.called)
+			}
+			if tt.called && err != nil {
+				t.Errorf("Unexpected error: %+v", err)
+			}
+			if !tt.called && err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+
+	t.Run("failure", func(t *testing.T) {
+		forbidden := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+		mock := &mockSession{
+			guildBanCreateWithReasonFunc: func(_ string, _ string, _ string, _ int, _ ...discordgo.RequestOption) error {
+				return forbidden
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		err := adapter.BanMember(context.Background(), "guild-1", "user-1", "", 0)
+		if !errors.Is(err, forbidden) {
+			t.Errorf("Expected the original error to be wrapped, got %+v", err)
+		}
+	})
+}
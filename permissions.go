@@ -0,0 +1,169 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// AuthorPermissions returns the effective permissions of the message author in the channel where the message was sent.
+// The returned value is a bit set of discordgo.Permission* constants.
+//
+// The permissions are computed from the cached state when available.
+// Otherwise, the channel, guild, and member are fetched via Discord's REST API.
+// For a message sent in a thread, the parent channel's permission overwrites are applied.
+// ErrDirectMessage is returned when the message was not sent in a guild.
+func (i *Input) AuthorPermissions(a *Adapter) (int64, error) {
+	if i.Event == nil || i.Event.Author == nil {
+		return 0, ErrNoAuthor
+	}
+
+	if i.Event.GuildID == "" {
+		return 0, ErrDirectMessage
+	}
+
+	userID := i.Event.Author.ID
+	channelID := i.Event.ChannelID
+
+	channel, err := a.channel(channelID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch channel %s: %w", channelID, err)
+	}
+
+	// Threads do not have their own permission overwrites and inherit the parent channel's ones.
+	if channel.IsThread() && channel.ParentID != "" {
+		parent, err := a.channel(channel.ParentID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch parent channel %s: %w", channel.ParentID, err)
+		}
+		channel = parent
+	}
+
+	if state := a.state(); state != nil {
+		permissions, err := state.UserChannelPermissions(userID, channel.ID)
+		if err == nil {
+			return permissions, nil
+		}
+	}
+
+	guild, err := a.guild(i.Event.GuildID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch guild %s: %w", i.Event.GuildID, err)
+	}
+
+	// MessageCreate events sent in a guild include a partial member object with roles.
+	var roles []string
+	if i.Event.Member != nil {
+		roles = i.Event.Member.Roles
+	} else {
+		member, err := a.member(guild.ID, userID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch member %s: %w", userID, err)
+		}
+		roles = member.Roles
+	}
+
+	return computePermissions(guild, channel, userID, roles), nil
+}
+
+// AuthorCan tells if the message author has all the given permissions in the channel where the message was sent.
+// See AuthorPermissions for how the permissions are computed.
+func (i *Input) AuthorCan(a *Adapter, permission int64) (bool, error) {
+	permissions, err := i.AuthorPermissions(a)
+	if err != nil {
+		return false, err
+	}
+
+	return permissions&permission == permission, nil
+}
+
+// computePermissions computes the member's effective permissions in the given channel.
+// https://discord.com/developers/docs/topics/permissions#permission-overwrites
+func computePermissions(guild *discordgo.Guild, channel *discordgo.Channel, userID string, roles []string) int64 {
+	if userID == guild.OwnerID {
+		return discordgo.PermissionAll
+	}
+
+	// Base permissions come from @everyone, whose role ID equals the guild ID, and the member's roles.
+	var permissions int64
+	for _, role := range guild.Roles {
+		if role.ID == guild.ID || slices.Contains(roles, role.ID) {
+			permissions |= role.Permissions
+		}
+	}
+
+	// Administrators bypass channel overwrites.
+	if permissions&discordgo.PermissionAdministrator == discordgo.PermissionAdministrator {
+		return discordgo.PermissionAll
+	}
+
+	// Overwrites are applied in the order of @everyone, roles, and then the member.
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.ID == guild.ID {
+			permissions &^= overwrite.Deny
+			permissions |= overwrite.Allow
+			break
+		}
+	}
+
+	var deny, allow int64
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeRole && slices.Contains(roles, overwrite.ID) {
+			deny |= overwrite.Deny
+			allow |= overwrite.Allow
+		}
+	}
+	permissions &^= deny
+	permissions |= allow
+
+	for _, overwrite := range channel.PermissionOverwrites {
+		if overwrite.Type == discordgo.PermissionOverwriteTypeMember && overwrite.ID == userID {
+			permissions &^= overwrite.Deny
+			permissions |= overwrite.Allow
+			break
+		}
+	}
+
+	return permissions
+}
+
+// state returns the session's state cache if the underlying session is *discordgo.Session.
+func (a *Adapter) state() *discordgo.State {
+	s := a.Session()
+	if s == nil {
+		return nil
+	}
+	return s.State
+}
+
+// channel returns the channel from the state cache or, if not cached, as Adapter.Channel does.
+func (a *Adapter) channel(channelID string) (*discordgo.Channel, error) {
+	if state := a.state(); state != nil {
+		if channel, err := state.Channel(channelID); err == nil {
+			return channel, nil
+		}
+	}
+	return a.Channel(context.Background(), ChannelID(channelID))
+}
+
+// guild returns the guild from the state cache or, if not cached, from the REST API.
+func (a *Adapter) guild(guildID string) (*discordgo.Guild, error) {
+	if state := a.state(); state != nil {
+		if guild, err := state.Guild(guildID); err == nil {
+			return guild, nil
+		}
+	}
+	return a.session.Guild(guildID, a.requestOptions()...)
+}
+
+// member returns the guild member from the state cache or, if not cached, from the REST API.
+func (a *Adapter) member(guildID string, userID string) (*discordgo.Member, error) {
+	if state := a.state(); state != nil {
+		if member, err := state.Member(guildID, userID); err == nil {
+			return member, nil
+		}
+	}
+	return a.session.GuildMember(guildID, userID, a.requestOptions()...)
+}
@@ -0,0 +1,277 @@
+package discord
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestComputePermissions(t *testing.T) {
+	guildID := "guild-1"
+	userID := "user-1"
+	guild := &discordgo.Guild{
+		ID:      guildID,
+		OwnerID: "owner-1",
+		Roles: []*discordgo.Role{
+			{ID: guildID, Permissions: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages},
+			{ID: "role-mod", Permissions: discordgo.PermissionManageMessages},
+			{ID: "role-admin", Permissions: discordgo.PermissionAdministrator},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		roles      []string
+		overwrites []*discordgo.PermissionOverwrite
+		has        int64
+		lacks      int64
+	}{
+		{
+			name:   "owner has every permission",
+			userID: "owner-1",
+			has:    discordgo.PermissionAll,
+		},
+		{
+			name:   "@everyone permissions are the base",
+			userID: userID,
+			has:    discordgo.PermissionViewChannel | discordgo.PermissionSendMessages,
+			lacks:  discordgo.PermissionManageMessages,
+		},
+		{
+			name:   "role permissions are added",
+			userID: userID,
+			roles:  []string{"role-mod"},
+			has:    discordgo.PermissionSendMessages | discordgo.PermissionManageMessages,
+		},
+		{
+			name:   "administrator bypasses overwrites",
+			userID: userID,
+			roles:  []string{"role-admin"},
+			overwrites: []*discordgo.PermissionOverwrite{
+				{ID: userID, Type: discordgo.PermissionOverwriteTypeMember, Deny: discordgo.PermissionSendMessages},
+			},
+			has: discordgo.PermissionAll,
+		},
+		{
+			name:   "@everyone overwrite denies",
+			userID: userID,
+			overwrites: []*discordgo.PermissionOverwrite{
+				{ID: guildID, Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionSendMessages},
+			},
+			has:   discordgo.PermissionViewChannel,
+			lacks: discordgo.PermissionSendMessages,
+		},
+		{
+			name:   "role overwrite takes precedence over @everyone overwrite",
+			userID: userID,
+			roles:  []string{"role-mod"},
+			overwrites: []*discordgo.PermissionOverwrite{
+				{ID: guildID, Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionSendMessages},
+				{ID: "role-mod", Type: discordgo.PermissionOverwriteTypeRole, Allow: discordgo.PermissionSendMessages},
+			},
+			has: discordgo.PermissionSendMessages,
+		},
+		{
+			name:   "member overwrite takes precedence over role overwrite",
+			userID: userID,
+			roles:  []string{"role-mod"},
+			overwrites: []*discordgo.PermissionOverwrite{
+				{ID: "role-mod", Type: discordgo.PermissionOverwriteTypeRole, Allow: discordgo.PermissionAttachFiles},
+				{ID: userID, Type: discordgo.PermissionOverwriteTypeMember, Deny: discordgo.PermissionAttachFiles},
+			},
+			lacks: discordgo.PermissionAttachFiles,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := &discordgo.Channel{ID: "ch-1", GuildID: guildID, PermissionOverwrites: tt.overwrites}
+
+			permissions := computePermissions(guild, channel, tt.userID, tt.roles)
+
+			if permissions&tt.has != tt.has {
+				t.Errorf("Expected permissions %d to include %d", permissions, tt.has)
+			}
+			if tt.lacks != 0 && permissions&tt.lacks != 0 {
+				t.Errorf("Expected permissions %d not to include %d", permissions, tt.lacks)
+			}
+		})
+	}
+}
+
+func TestInput_AuthorPermissions(t *testing.T) {
+	newInput := func(guildID string, member *discordgo.Member) *Input {
+		input, _ := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "ch-1",
+				GuildID:   guildID,
+				Content:   "hello",
+				Timestamp: time.Now(),
+				Author:    &discordgo.User{ID: "user-1"},
+				Member:    member,
+			},
+		})
+		return input
+	}
+
+	guildFunc := func(guildID string, options ...discordgo.RequestOption) (*discordgo.Guild, error) {
+		return &discordgo.Guild{
+			ID: guildID,
+			Roles: []*discordgo.Role{
+				{ID: guildID, Permissions: discordgo.PermissionSendMessages},
+				{ID: "role-mod", Permissions: discordgo.PermissionManageMessages},
+			},
+		}, nil
+	}
+
+	t.Run("direct message", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		_, err := newInput("", nil).AuthorPermissions(adapter)
+		if !errors.Is(err, ErrDirectMessage) {
+			t.Errorf("Expected ErrDirectMessage, got %+v", err)
+		}
+	})
+
+	t.Run("input without event", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		_, err := (&Input{}).AuthorPermissions(adapter)
+		if !errors.Is(err, ErrNoAuthor) {
+			t.Errorf("Expected ErrNoAuthor, got %+v", err)
+		}
+	})
+
+	t.Run("roles from the event's member", func(t *testing.T) {
+		mock := &mockSession{
+			guildFunc: guildFunc,
+			guildMemberFunc: func(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error) {
+				t.Error("GuildMember should not be called when the event contains the member")
+				return nil, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		permissions, err := newInput("guild-1", &discordgo.Member{Roles: []string{"role-mod"}}).AuthorPermissions(adapter)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		var expected int64 = discordgo.PermissionSendMessages | discordgo.PermissionManageMessages
+		if permissions != expected {
+			t.Errorf("Expected %d, got %d", expected, permissions)
+		}
+	})
+
+	t.Run("roles from the fetched member", func(t *testing.T) {
+		mock := &mockSession{
+			guildFunc: guildFunc,
+			guildMemberFunc: func(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error) {
+				return &discordgo.Member{Roles: []string{"role-mod"}}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		can, err := newInput("guild-1", nil).AuthorCan(adapter, discordgo.PermissionManageMessages)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if !can {
+			t.Error("Expected the author to be able to manage messages")
+		}
+	})
+
+	t.Run("thread inherits the parent channel's overwrites", func(t *testing.T) {
+		mock := &mockSession{
+			guildFunc: guildFunc,
+			channelFunc: func(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				if channelID == "ch-1" {
+					return &discordgo.Channel{ID: channelID, Type: discordgo.ChannelTypeGuildPublicThread, ParentID: "parent-1"}, nil
+				}
+				return &discordgo.Channel{
+					ID: channelID,
+					PermissionOverwrites: []*discordgo.PermissionOverwrite{
+						{ID: "guild-1", Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionSendMessages},
+					},
+				}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		can, err := newInput("guild-1", &discordgo.Member{}).AuthorCan(adapter, discordgo.PermissionSendMessages)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if can {
+			t.Error("Expected the parent channel's overwrite to deny sending messages")
+		}
+	})
+
+	t.Run("state is preferred", func(t *testing.T) {
+		s := &discordgo.Session{State: discordgo.NewState()}
+		_ = s.State.GuildAdd(&discordgo.Guild{ID: "guild-1", OwnerID: "user-1"})
+		_ = s.State.ChannelAdd(&discordgo.Channel{ID: "ch-1", GuildID: "guild-1"})
+		_ = s.State.MemberAdd(&discordgo.Member{GuildID: "guild-1", User: &discordgo.User{ID: "user-1"}})
+		adapter := &Adapter{config: NewConfig(), session: s}
+
+		permissions, err := newInput("guild-1", nil).AuthorPermissions(adapter)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if permissions != discordgo.PermissionAll {
+			t.Errorf("Expected the owner to have every permission, got %d", permissions)
+		}
+	})
+
+	t.Run("fetch errors", func(t *testing.T) {
+		fetchErr := errors.New("fetch failed")
+		tests := []struct {
+			name string
+			mock *mockSession
+		}{
+			{
+				name: "channel",
+				mock: &mockSession{
+					channelFunc: func(string, ...discordgo.RequestOption) (*discordgo.Channel, error) { return nil, fetchErr },
+				},
+			},
+			{
+				name: "parent channel",
+				mock: &mockSession{
+					channelFunc: func(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+						if channelID == "ch-1" {
+							return &discordgo.Channel{ID: channelID, Type: discordgo.ChannelTypeGuildPrivateThread, ParentID: "parent-1"}, nil
+						}
+						return nil, fetchErr
+					},
+				},
+			},
+			{
+				name: "guild",
+				mock: &mockSession{
+					guildFunc: func(string, ...discordgo.RequestOption) (*discordgo.Guild, error) { return nil, fetchErr },
+				},
+			},
+			{
+				name: "member",
+				mock: &mockSession{
+					guildMemberFunc: func(string, string, ...discordgo.RequestOption) (*discordgo.Member, error) { return nil, fetchErr },
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				adapter := &Adapter{config: NewConfig(), session: tt.mock}
+
+				_, err := newInput("guild-1", nil).AuthorCan(adapter, discordgo.PermissionSendMessages)
+				if !errors.Is(err, fetchErr) {
+					t.Errorf("Expected the fetch error to be wrapped, got %+v", err)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package discord
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// presenceStatuses are the statuses that Discord accepts for a bot.
+var presenceStatuses = []string{
+	string(discordgo.StatusOnline),
+	string(discordgo.StatusIdle),
+	string(discordgo.StatusDoNotDisturb),
+	string(discordgo.StatusInvisible),
+}
+
+// applyPresence sets Config.Status and the current activity as the bot's presence.
+// Discord resets the presence when the session is reopened, so this is called on every successful open.
+func (a *Adapter) applyPresence() {
+	activity := a.currentActivity()
+	if a.config.Status == "" && activity == "" {
+		return
+	}
+
+	data := discordgo.UpdateStatusData{
+		Status: a.config.Status,
+	}
+	if data.Status == "" {
+		data.Status = string(discordgo.StatusOnline)
+	}
+	if activity != "" {
+		data.Activities = []*discordgo.Activity{{
+			Name: activity,
+			Type: discordgo.ActivityTypeGame,
+		}}
+	}
+
+	if err := a.session.UpdateStatusComplex(data); err != nil {
+		a.log().Warnf("Failed to update presence: %+v", err)
+	}
+}
+
+// currentActivity returns the activity of the current turn in Config.ActivityRotation, or Config.Activity when the rotation is not configured.
+func (a *Adapter) currentActivity() string {
+	if n := len(a.config.ActivityRotation); n > 0 {
+		return a.config.ActivityRotation[a.activityTurn.Load()%uint64(n)]
+	}
+	return a.config.Activity
+}
+
+// rotateActivity starts a goroutine that switches the activity to the next one in Config.ActivityRotation every Config.ActivityRotationInterval.
+// The goroutine stops when the given context is canceled or the returned function is called, which waits for the goroutine to exit.
+func (a *Adapter) rotateActivity(ctx context.Context) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(a.config.ActivityRotationInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				a.activityTurn.Add(1)
+				a.applyPresence()
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
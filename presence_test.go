@@ -0,0 +1,197 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_applyPresence(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		activity   string
+		called     bool
+		expected   string
+		activities int
+	}{
+		{name: "nothing configured", called: false},
+		{name: "activity only", activity: ".help", called: true, expected: "online", activities: 1},
+		{name: "status only", status: "dnd", called: true, expected: "dnd", activities: 0},
+		{name: "both", status: "idle", activity: "with sarah", called: true, expected: "idle", activities: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var given *discordgo.UpdateStatusData
+			mock := &mockSession{
+				updateStatusComplexFunc: func(usd discordgo.UpdateStatusData) error {
+					given = &usd
+					return nil
+				},
+			}
+			config := NewConfig()
+			config.Status = tt.status
+			config.Activity = tt.activity
+			adapter := &Adapter{config: config, session: mock}
+
+			adapter.applyPresence()
+
+			if !tt.called {
+				if given != nil {
+					t.Fatalf("Expected presence not to be updated: %#v", given)
+				}
+				return
+			}
+			if given == nil {
+				t.Fatal("Expected presence to be updated")
+			}
+			if given.Status != tt.expected {
+				t.Errorf("Expected status %q, got %q", tt.expected, given.Status)
+			}
+			if len(given.Activities) != tt.activities {
+				t.Fatalf("Expected %d activities, got %d", tt.activities, len(given.Activities))
+			}
+			if tt.activities > 0 && (given.Activities[0].Name != tt.activity || given.Activities[0].Type != discordgo.ActivityTypeGame) {
+				t.Errorf("Unexpected activity: %#v", given.Activities[0])
+			}
+		})
+	}
+
+	t.Run("failure is not fatal", func(t *testing.T) {
+		logger := &capturingLogger{}
+		mock := &mockSession{
+			updateStatusComplexFunc: func(_ discordgo.UpdateStatusData) error {
+				return errors.New("gateway is not ready")
+			},
+		}
+		config := NewConfig()
+		config.Activity = ".help"
+		adapter := &Adapter{config: config, session: mock, logger: logger}
+
+		adapter.applyPresence()
+
+		if len(logger.entries) != 1 || !strings.HasPrefix(logger.entries[0], "WARN") {
+			t.Errorf("Expected a warning, got %v", logger.entries)
+		}
+	})
+}
+
+func TestAdapter_Run_AppliesPresence(t *testing.T) {
+	applied := make(chan discordgo.UpdateStatusData, 1)
+	mock := &mockSession{
+		updateStatusComplexFunc: func(usd discordgo.UpdateStatusData) error {
+			applied <- usd
+			return nil
+		},
+	}
+	config := NewConfig()
+	config.Activity = ".help"
+	adapter := &Adapter{config: config, session: mock}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go adapter.Run(ctx, func(sarah.Input) error { return nil }, func(error) {})
+
+	select {
+	case usd := <-applied:
+		if len(usd.Activities) != 1 || usd.Activities[0].Name != ".help" {
+			t.Errorf("Unexpected activities: %#v", usd.Activities)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected presence to be applied after the session is opened")
+	}
+}
+
+func TestAdapter_rotateActivity(t *testing.T) {
+	applied := make(chan string, 10)
+	mock := &mockSession{
+		updateStatusComplexFunc: func(usd discordgo.UpdateStatusData) error {
+			applied <- usd.Activities[0].Name
+			return nil
+		},
+	}
+	config := NewConfig()
+	config.Activity = "ignored"
+	config.ActivityRotation = []string{"first", "second", "third"}
+	config.ActivityRotationInterval = 5 * time.Millisecond
+	adapter := &Adapter{config: config, session: mock}
+
+	adapter.applyPresence()
+	stop := adapter.rotateActivity(context.Background())
+
+	var names []string
+	for len(names) < 4 {
+		select {
+		case name := <-applied:
+			names = append(names, name)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected the activity to rotate, got %v", names)
+		}
+	}
+	stop()
+
+	expected := []string{"first", "second", "third", "first"}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, names)
+		}
+	}
+
+	// No update happens once stopped.
+	for len(applied) > 0 {
+		<-applied
+	}
+	select {
+	case name := <-applied:
+		t.Errorf("Unexpected update after stop: %s", name)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestAdapter_Run_RotatesActivity(t *testing.T) {
+	applied := make(chan string, 10)
+	mock := &mockSession{
+		updateStatusComplexFunc: func(usd discordgo.UpdateStatusData) error {
+			select {
+			case applied <- usd.Activities[0].Name:
+			default:
+			}
+			return nil
+		},
+	}
+	config := NewConfig()
+	config.ActivityRotation = []string{"first", "second"}
+	config.ActivityRotationInterval = 5 * time.Millisecond
+	adapter := &Adapter{config: config, session: mock}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	go func() {
+		adapter.Run(ctx, func(sarah.Input) error { return nil }, func(error) {})
+		close(finished)
+	}()
+
+	for _, expected := range []string{"first", "second"} {
+		select {
+		case name := <-applied:
+			if name != expected {
+				t.Errorf("Expected %s, got %s", expected, name)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected the activity to rotate while running")
+		}
+	}
+
+	cancel()
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to return after the rotation stops")
+	}
+}
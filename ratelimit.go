@@ -0,0 +1,51 @@
+package discord
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sendLimiter spaces sends out so that they do not exceed the given rate.
+// This works as a token bucket holding a single token, and each caller reserves the next token so callers are served in order.
+// The zero value is ready to use.
+type sendLimiter struct {
+	mutex sync.Mutex
+	next  time.Time
+}
+
+// reserve reserves the next send at the given rate in messages per second, and returns how long to wait until the send.
+func (l *sendLimiter) reserve(rate float64, now time.Time) time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(time.Second) / rate))
+	return delay
+}
+
+// waitSendRate blocks until the next send is allowed by Config.SendRateLimit or the context is canceled.
+func (a *Adapter) waitSendRate(ctx context.Context) error {
+	if a.config.SendRateLimit <= 0 {
+		return nil
+	}
+
+	delay := a.sendLimiter.reserve(a.config.SendRateLimit, time.Now())
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-timer.C:
+		return nil
+	}
+}
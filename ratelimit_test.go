@@ -0,0 +1,115 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestSendLimiter_reserve(t *testing.T) {
+	t.Run("burst is spaced out at the rate", func(t *testing.T) {
+		limiter := &sendLimiter{}
+		now := time.Unix(0, 0)
+
+		for i, want := range []time.Duration{0, 500 * time.Millisecond, time.Second, 1500 * time.Millisecond} {
+			if delay := limiter.reserve(2, now); delay != want {
+				t.Errorf("Expected send %d to wait %s, got %s", i, want, delay)
+			}
+		}
+	})
+
+	t.Run("idle time does not accumulate", func(t *testing.T) {
+		limiter := &sendLimiter{}
+		now := time.Unix(0, 0)
+		limiter.reserve(2, now)
+
+		later := now.Add(time.Minute)
+		if delay := limiter.reserve(2, later); delay != 0 {
+			t.Errorf("Expected no wait after idle time, got %s", delay)
+		}
+		if delay := limiter.reserve(2, later); delay != 500*time.Millisecond {
+			t.Errorf("Expected the following send to wait, got %s", delay)
+		}
+	})
+
+	t.Run("sends in time are not delayed", func(t *testing.T) {
+		limiter := &sendLimiter{}
+		now := time.Unix(0, 0)
+
+		for i := range 3 {
+			if delay := limiter.reserve(10, now.Add(time.Duration(i)*100*time.Millisecond)); delay != 0 {
+				t.Errorf("Expected send %d not to wait, got %s", i, delay)
+			}
+		}
+	})
+}
+
+func TestAdapter_SendMessage_SendRateLimit(t *testing.T) {
+	t.Run("sends wait for their turn", func(t *testing.T) {
+		config := NewConfig()
+		config.SendRateLimit = 50
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		started := time.Now()
+		for range 3 {
+			if err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello")); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+		}
+
+		if elapsed := time.Since(started); elapsed < 40*time.Millisecond {
+			t.Errorf("Expected sends to be spaced out, took %s", elapsed)
+		}
+		if adapter.Stats().Total.SendSucceeded != 3 {
+			t.Errorf("Expected all sends to succeed: %#v", adapter.Stats().Total)
+		}
+	})
+
+	t.Run("each chunk waits for its turn", func(t *testing.T) {
+		var sent int
+		mock := &mockSession{
+			channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sent++
+				return &discordgo.Message{}, nil
+			},
+		}
+		config := NewConfig()
+		config.SendRateLimit = 20
+		config.MaxMessageLength = 10
+		adapter := &Adapter{config: config, session: mock}
+
+		text := strings.Repeat("a", 30)
+		chunks := len(chunkMessage(text, config.MaxMessageLength))
+		started := time.Now()
+		err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), text))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if chunks < 3 || sent != chunks {
+			t.Fatalf("Expected every chunk to be sent, got %d of %d", sent, chunks)
+		}
+		if elapsed := time.Since(started); elapsed < time.Duration(chunks-1)*45*time.Millisecond {
+			t.Errorf("Expected chunks to be spaced out, took %s", elapsed)
+		}
+	})
+
+	t.Run("canceled wait fails the send", func(t *testing.T) {
+		config := NewConfig()
+		config.SendRateLimit = 0.1
+		adapter := &Adapter{config: config, session: &mockSession{}}
+		adapter.sendLimiter.reserve(config.SendRateLimit, time.Now())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := adapter.SendMessageWithError(ctx, sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %+v", err)
+		}
+	})
+}
@@ -0,0 +1,11 @@
+package discord
+
+// WithRawHandler creates an AdapterOption that registers the given discordgo event handler alongside the Adapter's own ones,
+// e.g., func(*discordgo.Session, *discordgo.VoiceStateUpdate) to handle gateway events that the Adapter does not pass to go-sarah.
+// The handler must be in a form that discordgo.Session.AddHandler accepts, and is registered when Run starts.
+// This can be given multiple times to register multiple handlers.
+func WithRawHandler(handler interface{}) AdapterOption {
+	return func(adapter *Adapter) {
+		adapter.rawHandlers = append(adapter.rawHandlers, handler)
+	}
+}
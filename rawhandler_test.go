@@ -0,0 +1,61 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestWithRawHandler(t *testing.T) {
+	var registered []interface{}
+	mock := &mockSession{
+		addHandlerFunc: func(handler interface{}) func() {
+			registered = append(registered, handler)
+			return func() {}
+		},
+		openFunc: func() error {
+			return errors.New("stop here")
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+
+	var voiceStates int
+	var guilds int
+	WithRawHandler(func(_ *discordgo.Session, _ *discordgo.VoiceStateUpdate) { voiceStates++ })(adapter)
+	WithRawHandler(func(_ *discordgo.Session, _ *discordgo.GuildCreate) { guilds++ })(adapter)
+
+	adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+	var found int
+	for _, handler := range registered {
+		switch h := handler.(type) {
+		case func(*discordgo.Session, *discordgo.VoiceStateUpdate):
+			h(nil, &discordgo.VoiceStateUpdate{})
+			found++
+
+		case func(*discordgo.Session, *discordgo.GuildCreate):
+			h(nil, &discordgo.GuildCreate{})
+			found++
+		}
+	}
+	if found != 2 {
+		t.Fatalf("Expected both raw handlers to be registered, got %d", found)
+	}
+	if voiceStates != 1 || guilds != 1 {
+		t.Errorf("Expected the registered handlers to be the given ones: %d, %d", voiceStates, guilds)
+	}
+
+	// The Adapter's own handlers are kept.
+	var ownHandler bool
+	for _, handler := range registered {
+		if _, ok := handler.(func(*discordgo.Session, *discordgo.MessageCreate)); ok {
+			ownHandler = true
+		}
+	}
+	if !ownHandler {
+		t.Error("Expected the Adapter's message handler to be registered as well")
+	}
+}
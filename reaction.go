@@ -0,0 +1,133 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxReactionRetries is the maximum number of times a single reaction is retried when rate limited.
+const maxReactionRetries = 5
+
+// AddReactions adds the given emojis to the message in the given order.
+// Each emoji is either a unicode emoji, a custom emoji in the form of "name:id" or "<:name:id>",
+// or the name of a custom emoji that Adapter.ResolveEmoji resolves.
+//
+// Discord allows roughly one reaction per 250 milliseconds per message, so the additions are spaced by Config.ReactionInterval.
+// When Discord still responds with a rate limit error, the addition is retried after the duration Discord specifies,
+// and the spacing of the following additions is widened accordingly.
+func (a *Adapter) AddReactions(ctx context.Context, channelID ChannelID, messageID string, emojis ...string) error {
+	pacer := newReactionPacer(a.config.ReactionInterval)
+
+	for _, emoji := range emojis {
+		emoji, err := a.reactionEmoji(string(channelID), emoji)
+		if err != nil {
+			return fmt.Errorf("failed to resolve emoji for %s: %w", messageID, err)
+		}
+
+		if err := a.addReaction(ctx, pacer, string(channelID), messageID, emoji); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *Adapter) addReaction(ctx context.Context, pacer *reactionPacer, channelID string, messageID string, emoji string) error {
+	for attempt := 0; ; attempt++ {
+		if err := pacer.wait(ctx); err != nil {
+			return err
+		}
+
+		// Let the pacer handle rate limits instead of discordgo blocking the goroutine.
+		err := a.session.MessageReactionAdd(channelID, messageID, emoji, a.requestOptions(discordgo.WithContext(ctx), discordgo.WithRetryOnRatelimit(false))...)
+		if err == nil {
+			pacer.succeeded()
+			return nil
+		}
+
+		var rateLimitErr *discordgo.RateLimitError
+		if !errors.As(err, &rateLimitErr) || attempt >= maxReactionRetries {
+			return fmt.Errorf("failed to add reaction %s to %s: %w", emoji, messageID, err)
+		}
+
+		var retryAfter time.Duration
+		if rateLimitErr.RateLimit != nil && rateLimitErr.TooManyRequests != nil {
+			retryAfter = rateLimitErr.RetryAfter
+		}
+		pacer.rateLimited(retryAfter)
+	}
+}
+
+// reactionResponse is a response content that reacts to the input message, optionally followed by a reply.
+// The fields are exported so the content can be hashed for Config.SuppressDuplicateSends.
+type reactionResponse struct {
+	MessageID string
+	Emoji     string
+	Content   any
+}
+
+// RespWithReaction reacts to the input message with the given emoji when the response is sent.
+// The emoji is either a unicode emoji, a custom emoji in the form of "name:id", or the name of a custom emoji.
+// The response content is sent as a reply after the reaction unless it is an empty string,
+// so NewResponse(input, "", RespWithReaction("👍")) only acknowledges the input.
+func RespWithReaction(emoji string) RespOption {
+	return func(options *respOptions) {
+		options.reaction = emoji
+	}
+}
+
+// reactionPacer spaces reaction additions and adapts the spacing to the observed rate limit.
+type reactionPacer struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newReactionPacer(interval time.Duration) *reactionPacer {
+	if interval <= 0 {
+		interval = NewConfig().ReactionInterval
+	}
+	return &reactionPacer{
+		interval: interval,
+	}
+}
+
+// wait blocks until the next addition is allowed or the context is canceled.
+func (p *reactionPacer) wait(ctx context.Context) error {
+	delay := time.Until(p.next)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-timer.C:
+		return nil
+	}
+}
+
+// succeeded schedules the next addition after the current interval.
+func (p *reactionPacer) succeeded() {
+	p.next = time.Now().Add(p.interval)
+}
+
+// rateLimited schedules the retry after the duration Discord specified.
+// The interval is widened so the following additions are less likely to be rate limited again.
+func (p *reactionPacer) rateLimited(retryAfter time.Duration) {
+	if retryAfter <= 0 {
+		retryAfter = p.interval
+	}
+
+	if retryAfter > p.interval {
+		p.interval = retryAfter
+	}
+	p.next = time.Now().Add(retryAfter)
+}
@@ -0,0 +1,276 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newRateLimitError(retryAfter time.Duration) error {
+	return &discordgo.RateLimitError{
+		RateLimit: &discordgo.RateLimit{
+			TooManyRequests: &discordgo.TooManyRequests{RetryAfter: retryAfter},
+			URL:             "https://discord.com/api/v9/channels/ch-1/messages/msg-1/reactions",
+		},
+	}
+}
+
+func TestAdapter_AddReactions(t *testing.T) {
+	t.Run("reactions are added in order with spacing", func(t *testing.T) {
+		var added []string
+		var times []time.Time
+		mock := &mockSession{
+			messageReactionAddFunc: func(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error {
+				if channelID != "ch-1" || messageID != "msg-1" {
+					t.Errorf("Unexpected target: %s/%s", channelID, messageID)
+				}
+				added = append(added, emojiID)
+				times = append(times, time.Now())
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ReactionInterval = 20 * time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		err := adapter.AddReactions(context.Background(), ChannelID("ch-1"), "msg-1", "1️⃣", "2️⃣", "3️⃣")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if len(added) != 3 || added[0] != "1️⃣" || added[2] != "3️⃣" {
+			t.Fatalf("Unexpected reactions: %v", added)
+		}
+		for i := 1; i < len(times); i++ {
+			if gap := times[i].Sub(times[i-1]); gap < config.ReactionInterval {
+				t.Errorf("Expected additions to be spaced by %s, got %s", config.ReactionInterval, gap)
+			}
+		}
+	})
+
+	t.Run("rate limited reaction is retried after retry-after", func(t *testing.T) {
+		retryAfter := 30 * time.Millisecond
+		var attempts int
+		var times []time.Time
+		mock := &mockSession{
+			messageReactionAddFunc: func(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error {
+				attempts++
+				times = append(times, time.Now())
+				if attempts == 1 {
+					return newRateLimitError(retryAfter)
+				}
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ReactionInterval = time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		err := adapter.AddReactions(context.Background(), ChannelID("ch-1"), "msg-1", "👍", "👎")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if attempts != 3 {
+			t.Fatalf("Expected 3 attempts, got %d", attempts)
+		}
+		if gap := times[1].Sub(times[0]); gap < retryAfter {
+			t.Errorf("Expected the retry to wait %s, got %s", retryAfter, gap)
+		}
+		if gap := times[2].Sub(times[1]); gap < retryAfter {
+			t.Errorf("Expected the interval to be widened to %s, got %s", retryAfter, gap)
+		}
+	})
+
+	t.Run("gives up after too many rate limits", func(t *testing.T) {
+		var attempts int
+		mock := &mockSession{
+			messageReactionAddFunc: func(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error {
+				attempts++
+				return newRateLimitError(time.Millisecond)
+			},
+		}
+		config := NewConfig()
+		config.ReactionInterval = time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		err := adapter.AddReactions(context.Background(), ChannelID("ch-1"), "msg-1", "👍")
+
+		var rateLimitErr *discordgo.RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			t.Errorf("Expected a rate limit error, got %+v", err)
+		}
+		if attempts != maxReactionRetries+1 {
+			t.Errorf("Expected %d attempts, got %d", maxReactionRetries+1, attempts)
+		}
+	})
+
+	t.Run("other errors are returned immediately", func(t *testing.T) {
+		var attempts int
+		mock := &mockSession{
+			messageReactionAddFunc: func(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error {
+				attempts++
+				return errors.New("unknown emoji")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		err := adapter.AddReactions(context.Background(), ChannelID("ch-1"), "msg-1", "👍", "👎")
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("context cancellation stops the batch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		mock := &mockSession{
+			messageReactionAddFunc: func(channelID, messageID, emojiID string, options ...discordgo.RequestOption) error {
+				cancel()
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ReactionInterval = time.Hour
+		adapter := &Adapter{config: config, session: mock}
+
+		err := adapter.AddReactions(ctx, ChannelID("ch-1"), "msg-1", "👍", "👎")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %+v", err)
+		}
+	})
+}
+
+func TestRespWithReaction(t *testing.T) {
+	newInput := func(guildID string) *Input {
+		input, _ := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ID:        "msg-1",
+				ChannelID: "ch-1",
+				GuildID:   guildID,
+				Author:    &discordgo.User{ID: "user-1"},
+			},
+		})
+		return input
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		emoji     string
+		sentReply bool
+	}{
+		{name: "unicode emoji only", content: "", emoji: "👍", sentReply: false},
+		{name: "custom emoji with reply", content: "Done.", emoji: "sarah:123456789", sentReply: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reactedMessageID, reactedEmoji string
+			var replies []string
+			mock := &mockSession{
+				messageReactionAddFunc: func(channelID string, messageID string, emojiID string, _ ...discordgo.RequestOption) error {
+					if channelID != "ch-1" {
+						t.Errorf("Unexpected channel: %s", channelID)
+					}
+					reactedMessageID = messageID
+					reactedEmoji = emojiID
+					return nil
+				},
+				channelMessageSendFunc: func(_ string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+					replies = append(replies, content)
+					return &discordgo.Message{}, nil
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			input := newInput("guild-1")
+			response, err := NewResponse(input, tt.content, RespWithReaction(tt.emoji))
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+			if reactedMessageID != "msg-1" {
+				t.Errorf("Expected reaction to msg-1, got %q", reactedMessageID)
+			}
+			if reactedEmoji != tt.emoji {
+				t.Errorf("Expected emoji %q, got %q", tt.emoji, reactedEmoji)
+			}
+			if tt.sentReply != (len(replies) == 1) {
+				t.Errorf("Unexpected replies: %#v", replies)
+			}
+		})
+	}
+
+	t.Run("failed reaction skips the reply", func(t *testing.T) {
+		replied := false
+		mock := &mockSession{
+			messageReactionAddFunc: func(_ string, _ string, _ string, _ ...discordgo.RequestOption) error {
+				return errors.New("unknown emoji")
+			},
+			channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				replied = true
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		input := newInput("guild-1")
+		response, _ := NewResponse(input, "Done.", RespWithReaction("👍"))
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+		if replied {
+			t.Error("Reply should not be sent when the reaction fails")
+		}
+		if adapter.Stats().Total.SendFailed != 1 {
+			t.Errorf("Expected a send failure to be recorded: %#v", adapter.Stats())
+		}
+	})
+
+	t.Run("rejection is not reacted", func(t *testing.T) {
+		response, err := NewResponse(newInput(""), "Done.", RespGuildOnly(""), RespWithReaction("👍"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if response.Content != defaultGuildOnlyMessage {
+			t.Errorf("Expected rejection message, got %#v", response.Content)
+		}
+	})
+}
+
+func TestReactionPacer(t *testing.T) {
+	t.Run("non-positive interval falls back to default", func(t *testing.T) {
+		pacer := newReactionPacer(0)
+		if pacer.interval != NewConfig().ReactionInterval {
+			t.Errorf("Expected default interval, got %s", pacer.interval)
+		}
+	})
+
+	t.Run("rate limit without retry-after uses current interval", func(t *testing.T) {
+		pacer := newReactionPacer(10 * time.Millisecond)
+		pacer.rateLimited(0)
+		if pacer.interval != 10*time.Millisecond {
+			t.Errorf("Expected interval to stay, got %s", pacer.interval)
+		}
+		if time.Until(pacer.next) <= 0 {
+			t.Error("Expected the next addition to be delayed")
+		}
+	})
+
+	t.Run("shorter retry-after does not narrow the interval", func(t *testing.T) {
+		pacer := newReactionPacer(10 * time.Millisecond)
+		pacer.rateLimited(time.Millisecond)
+		if pacer.interval != 10*time.Millisecond {
+			t.Errorf("Expected interval to stay, got %s", pacer.interval)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package discord
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// ReactionInput is a sarah.Input implementation that represents a reaction added to a message.
+// This is passed to go-sarah only when Config.HandleReactions is true.
+// The message is the emoji in the form accepted by Adapter.AddReactions, i.e., a unicode emoji or "name:id" for a custom emoji,
+// so a command matching the emoji handles the reaction.
+// When the emoji is mapped in Config.ReactionCommands, the message is the mapped command text instead.
+type ReactionInput struct {
+	Event     *discordgo.MessageReactionAdd
+	senderKey string
+	text      string
+	sentAt    time.Time
+	channelID ChannelID
+
+	correlationID string
+}
+
+var _ sarah.Input = (*ReactionInput)(nil)
+
+// SenderKey returns a unique key representing the user who reacted in the channel.
+func (i *ReactionInput) SenderKey() string {
+	return i.senderKey
+}
+
+// Message returns the emoji, or the command text that the emoji is mapped to in Config.ReactionCommands.
+func (i *ReactionInput) Message() string {
+	return i.text
+}
+
+// SentAt returns when the reaction was received.
+func (i *ReactionInput) SentAt() time.Time {
+	return i.sentAt
+}
+
+// ReplyTo returns the channel where the reacted message is.
+func (i *ReactionInput) ReplyTo() sarah.OutputDestination {
+	return i.channelID
+}
+
+// Emoji returns the added emoji.
+func (i *ReactionInput) Emoji() discordgo.Emoji {
+	return i.Event.Emoji
+}
+
+// MessageID returns the ID of the reacted message.
+func (i *ReactionInput) MessageID() string {
+	return i.Event.MessageID
+}
+
+// ChannelID returns the ID of the channel where the reacted message is.
+func (i *ReactionInput) ChannelID() string {
+	return i.Event.ChannelID
+}
+
+// UserID returns the ID of the user who reacted.
+func (i *ReactionInput) UserID() string {
+	return i.Event.UserID
+}
+
+// GuildID returns the ID of the guild where the reacted message is, or an empty string in a direct message.
+func (i *ReactionInput) GuildID() string {
+	return i.Event.GuildID
+}
+
+// CorrelationID returns the ID that correlates the logs and outgoing messages caused by this input.
+func (i *ReactionInput) CorrelationID() string {
+	return i.correlationID
+}
+
+// MessageReactionAddToInput converts the given reaction addition to *ReactionInput.
+func MessageReactionAddToInput(r *discordgo.MessageReactionAdd) (*ReactionInput, error) {
+	if r.MessageReaction == nil || r.UserID == "" {
+		return nil, ErrNoAuthor
+	}
+
+	return &ReactionInput{
+		Event:     r,
+		senderKey: senderKeyOf(r.ChannelID, r.UserID),
+		text:      r.Emoji.APIName(),
+		sentAt:    time.Now(),
+		channelID: ChannelID(r.ChannelID),
+
+		correlationID: newCorrelationID(),
+	}, nil
+}
+
+// handleReactionAdd passes the added reaction to go-sarah.
+// Reactions added by the bot itself, e.g., by RespWithReaction, are ignored.
+// A reaction mapped in Config.ReactionCommands is passed with the command text, and others are passed only when Config.HandleReactions is true.
+// Reactions received after the given context is canceled are dropped.
+func (a *Adapter) handleReactionAdd(ctx context.Context, s *discordgo.Session, r *discordgo.MessageReactionAdd, enqueueInput func(sarah.Input) error) {
+	a.messageReceived()
+
+	if ctx.Err() != nil {
+		a.log().Debugf("Skipping reaction to %s received during shutdown", r.MessageID)
+		a.messageDropped(ctx.Err())
+		return
+	}
+
+	input, err := MessageReactionAddToInput(r)
+	if err != nil {
+		a.log().Debugf("Skipping reaction: %+v", err)
+		a.messageDropped(err)
+		return
+	}
+
+	if command, ok := a.config.ReactionCommands[input.text]; ok {
+		input.text = command
+	} else if !a.config.HandleReactions {
+		a.messageDropped(nil)
+		return
+	}
+
+	if botID := a.selfID(s); botID != "" && r.UserID == botID {
+		a.messageDropped(nil)
+		return
+	}
+
+	if a.userBlocked(r.UserID) {
+		a.log().Debugf("[%s] Ignoring reaction to %s from blocked user %s", input.correlationID, r.MessageID, r.UserID)
+		a.messageDropped(nil)
+		return
+	}
+
+	if a.config.IgnoreBots && r.Member != nil && r.Member.User != nil && r.Member.User.Bot {
+		a.messageDropped(nil)
+		return
+	}
+
+	if !a.channelAllowed(r.ChannelID) {
+		a.messageDropped(nil)
+		return
+	}
+
+	if !a.guildIDEnabled(r.GuildID) {
+		a.messageDropped(nil)
+		return
+	}
+
+	if err := enqueueInput(input); err != nil {
+		a.enqueueFailed(input, input.correlationID, "reaction", err)
+		a.messageDropped(err)
+		return
+	}
+	a.log().Debugf("[%s] Enqueued reaction to %s from %s", input.correlationID, r.MessageID, input.senderKey)
+	a.messageEnqueued()
+}
@@ -0,0 +1,238 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newReactionAdd(userID string, emoji discordgo.Emoji) *discordgo.MessageReactionAdd {
+	return &discordgo.MessageReactionAdd{
+		MessageReaction: &discordgo.MessageReaction{
+			UserID:    userID,
+			MessageID: "msg-1",
+			ChannelID: "ch-1",
+			GuildID:   "guild-1",
+			Emoji:     emoji,
+		},
+	}
+}
+
+func TestMessageReactionAddToInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		emoji discordgo.Emoji
+		want  string
+	}{
+		{name: "unicode emoji", emoji: discordgo.Emoji{Name: "👍"}, want: "👍"},
+		{name: "custom emoji", emoji: discordgo.Emoji{ID: "123", Name: "sarah"}, want: "sarah:123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := MessageReactionAddToInput(newReactionAdd("user-1", tt.emoji))
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+			if input.Message() != tt.want || input.Emoji().Name != tt.emoji.Name {
+				t.Errorf("Unexpected emoji: %s", input.Message())
+			}
+			if input.MessageID() != "msg-1" || input.ChannelID() != "ch-1" || input.UserID() != "user-1" || input.GuildID() != "guild-1" {
+				t.Errorf("Unexpected identifiers: %#v", input.Event.MessageReaction)
+			}
+			if input.SenderKey() != senderKeyOf("ch-1", "user-1") || input.ReplyTo() != ChannelID("ch-1") {
+				t.Errorf("Unexpected sender or destination: %s, %#v", input.SenderKey(), input.ReplyTo())
+			}
+		})
+	}
+
+	t.Run("without user", func(t *testing.T) {
+		if _, err := MessageReactionAddToInput(newReactionAdd("", discordgo.Emoji{Name: "👍"})); !errors.Is(err, ErrNoAuthor) {
+			t.Errorf("Expected ErrNoAuthor, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_handleReactionAdd(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	otherBot := newReactionAdd("bot-2", discordgo.Emoji{Name: "👍"})
+	otherBot.Member = &discordgo.Member{User: &discordgo.User{ID: "bot-2", Bot: true}}
+
+	tests := []struct {
+		name     string
+		reaction *discordgo.MessageReactionAdd
+		enqueued bool
+	}{
+		{name: "reaction by user", reaction: newReactionAdd("user-1", discordgo.Emoji{Name: "👍"}), enqueued: true},
+		{name: "reaction by the bot itself", reaction: newReactionAdd("bot-1", discordgo.Emoji{Name: "👍"}), enqueued: false},
+		{name: "reaction by another bot", reaction: otherBot, enqueued: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.HandleReactions = true
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			var enqueued sarah.Input
+			adapter.handleReactionAdd(context.Background(), s, tt.reaction, func(input sarah.Input) error {
+				enqueued = input
+				return nil
+			})
+
+			if (enqueued != nil) != tt.enqueued {
+				t.Fatalf("Expected enqueued to be %t", tt.enqueued)
+			}
+			if enqueued != nil {
+				if _, ok := enqueued.(*ReactionInput); !ok {
+					t.Errorf("Expected *ReactionInput, got %T", enqueued)
+				}
+			}
+		})
+	}
+
+	t.Run("reaction by a blocked user", func(t *testing.T) {
+		config := NewConfig()
+		config.HandleReactions = true
+		config.BlockedUsers = []string{"user-1"}
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		adapter.handleReactionAdd(context.Background(), s, newReactionAdd("user-1", discordgo.Emoji{Name: "👍"}), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+	})
+
+	t.Run("reaction in a disabled guild", func(t *testing.T) {
+		config := NewConfig()
+		config.HandleReactions = true
+		store := NewInMemoryGuildEnabledStore()
+		_ = store.SetEnabled("guild-1", false)
+		adapter := &Adapter{config: config, session: &mockSession{}, guildEnabledStore: store}
+
+		adapter.handleReactionAdd(context.Background(), s, newReactionAdd("user-1", discordgo.Emoji{Name: "👍"}), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+	})
+
+	t.Run("reaction during shutdown", func(t *testing.T) {
+		config := NewConfig()
+		config.HandleReactions = true
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		adapter.handleReactionAdd(ctx, s, newReactionAdd("user-1", discordgo.Emoji{Name: "👍"}), func(sarah.Input) error {
+			t.Error("Unexpected enqueue")
+			return nil
+		})
+		if adapter.Stats().Total.Dropped != 1 {
+			t.Errorf("Expected the reaction to be counted as dropped: %#v", adapter.Stats().Total)
+		}
+	})
+
+	t.Run("handler is registered on Run only when enabled", func(t *testing.T) {
+		for _, handleReactions := range []bool{false, true} {
+			var registered bool
+			mock := &mockSession{
+				addHandlerFunc: func(handler interface{}) func() {
+					if _, ok := handler.(func(*discordgo.Session, *discordgo.MessageReactionAdd)); ok {
+						registered = true
+					}
+					return func() {}
+				},
+				openFunc: func() error {
+					return errors.New("stop here")
+				},
+			}
+			config := NewConfig()
+			config.HandleReactions = handleReactions
+			adapter := &Adapter{config: config, session: mock}
+
+			adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+			if registered != handleReactions {
+				t.Errorf("Expected registration to be %t", handleReactions)
+			}
+		}
+	})
+}
+
+func TestAdapter_handleReactionAdd_ReactionCommands(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name            string
+		handleReactions bool
+		emoji           discordgo.Emoji
+		message         string
+	}{
+		{name: "mapped emoji", emoji: discordgo.Emoji{Name: "🔁"}, message: ".retry"},
+		{name: "mapped custom emoji", emoji: discordgo.Emoji{ID: "123", Name: "sarah"}, message: ".hello"},
+		{name: "unmapped emoji", emoji: discordgo.Emoji{Name: "👍"}, message: ""},
+		{name: "unmapped emoji with HandleReactions", handleReactions: true, emoji: discordgo.Emoji{Name: "👍"}, message: "👍"},
+		{name: "mapped emoji with HandleReactions", handleReactions: true, emoji: discordgo.Emoji{Name: "🔁"}, message: ".retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.HandleReactions = tt.handleReactions
+			config.ReactionCommands = map[string]string{"🔁": ".retry", "sarah:123": ".hello"}
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			var enqueued sarah.Input
+			adapter.handleReactionAdd(context.Background(), s, newReactionAdd("user-1", tt.emoji), func(input sarah.Input) error {
+				enqueued = input
+				return nil
+			})
+
+			if tt.message == "" {
+				if enqueued != nil {
+					t.Errorf("Expected the reaction to be dropped, got %#v", enqueued)
+				}
+				return
+			}
+			if enqueued == nil {
+				t.Fatal("Expected the reaction to be enqueued")
+			}
+			if enqueued.Message() != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, enqueued.Message())
+			}
+			if emoji := enqueued.(*ReactionInput).Emoji(); emoji.Name != tt.emoji.Name {
+				t.Errorf("Expected the original emoji to be kept, got %#v", emoji)
+			}
+		})
+	}
+
+	t.Run("handler is registered on Run with ReactionCommands", func(t *testing.T) {
+		var registered bool
+		mock := &mockSession{
+			addHandlerFunc: func(handler interface{}) func() {
+				if _, ok := handler.(func(*discordgo.Session, *discordgo.MessageReactionAdd)); ok {
+					registered = true
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				return errors.New("stop here")
+			},
+		}
+		config := NewConfig()
+		config.ReactionCommands = map[string]string{"🔁": ".retry"}
+		adapter := &Adapter{config: config, session: mock}
+
+		adapter.Run(context.Background(), func(sarah.Input) error { return nil }, func(error) {})
+
+		if !registered {
+			t.Error("Expected the reaction handler to be registered")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+)
+
+// maxOpenRetryInterval is the upper limit of the interval between retries to open the session.
+const maxOpenRetryInterval = 1 * time.Minute
+
+// fatalCloseCodes lists the gateway close codes that reconnecting does not recover from,
+// such as an invalid token or disallowed intents.
+// Other close codes are recoverable by resuming the session with the next Open.
+var fatalCloseCodes = []int{4004, 4010, 4011, 4012, 4013, 4014}
+
+// isFatalGatewayError tells if the given error is returned for a gateway close code that reconnecting does not recover from.
+func isFatalGatewayError(err error) bool {
+	var closeErr *websocket.CloseError
+	return errors.As(err, &closeErr) && slices.Contains(fatalCloseCodes, closeErr.Code)
+}
+
+// open opens the session and retries with exponential backoff up to Config.OpenRetryLimit times.
+// An error with a fatal close code is returned immediately.
+func (a *Adapter) open(ctx context.Context) error {
+	return a.openWithRetry(ctx, a.config.OpenRetryLimit)
+}
+
+// reconnect closes the dropped session and opens it again with exponential backoff up to Config.MaxReconnectAttempts times in total.
+// When Config.MaxReconnectAttempts is not positive, the retries are limited by Config.OpenRetryLimit as on startup.
+func (a *Adapter) reconnect(ctx context.Context) error {
+	// The session is usually closed already when it is dropped, so failing to close it again is not a problem.
+	if err := a.closeForReconnection(); err != nil {
+		a.log().Debugf("Failed to close Discord session before reconnection: %+v", err)
+	}
+
+	retryLimit := a.config.OpenRetryLimit
+	if a.config.MaxReconnectAttempts > 0 {
+		retryLimit = a.config.MaxReconnectAttempts - 1
+	}
+	if err := a.openWithRetry(ctx, retryLimit); err != nil {
+		return err
+	}
+
+	a.applyPresence()
+	return nil
+}
+
+// closeForReconnection closes the session before it is opened again.
+// The session is closed with a non-normal close code so that the gateway keeps it resumable
+// and the next Open resumes it instead of identifying as a new session.
+// discordgo emits a Disconnect event on every close, so the event is marked as the Adapter's own
+// and is not regarded as another drop that requires a reconnection.
+func (a *Adapter) closeForReconnection() error {
+	a.ownDisconnects.Add(1)
+	return a.session.CloseWithCode(websocket.CloseServiceRestart)
+}
+
+// consumeOwnDisconnect tells if a Disconnect event is caused by closeForReconnection and marks it as handled.
+func (a *Adapter) consumeOwnDisconnect() bool {
+	for {
+		n := a.ownDisconnects.Load()
+		if n <= 0 {
+			return false
+		}
+		if a.ownDisconnects.CompareAndSwap(n, n-1) {
+			return true
+		}
+	}
+}
+
+// openWithRetry opens the session and retries with exponential backoff up to the given times.
+// An error with a fatal close code is returned immediately.
+func (a *Adapter) openWithRetry(ctx context.Context, retryLimit int) error {
+	interval := a.config.OpenRetryInterval
+	if interval <= 0 {
+		interval = NewConfig().OpenRetryInterval
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := a.session.Open()
+		if err == nil || errors.Is(err, discordgo.ErrWSAlreadyOpen) {
+			return nil
+		}
+
+		if isFatalGatewayError(err) || attempt >= retryLimit {
+			return err
+		}
+
+		a.log().Warnf("Failed to open Discord session. Retrying in %s: %+v", interval, err)
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+
+		case <-timer.C:
+		}
+
+		interval = min(interval*2, maxOpenRetryInterval)
+	}
+}
+
+// watchDisconnection registers a handler that notifies the returned channel when the session is disconnected.
+// discordgo's own reconnection is disabled so the Adapter is the only one that reopens the session.
+// An invalid session (opcode 9) does not disconnect the session; discordgo handles it by identifying again.
+func (a *Adapter) watchDisconnection() <-chan struct{} {
+	if s, ok := a.session.(*discordgo.Session); ok {
+		s.ShouldReconnectOnError = false
+	}
+
+	disconnected := make(chan struct{}, 1)
+	a.session.AddHandler(func(_ *discordgo.Session, _ *discordgo.Disconnect) {
+		if a.consumeOwnDisconnect() {
+			// Closed by the Adapter itself while reconnecting.
+			return
+		}
+
+		select {
+		case disconnected <- struct{}{}:
+		default:
+			// A reconnection is already pending.
+		}
+	})
+
+	return disconnected
+}
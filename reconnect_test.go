@@ -0,0 +1,406 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/gorilla/websocket"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_Run_OpenRetry(t *testing.T) {
+	t.Run("open is retried until it succeeds", func(t *testing.T) {
+		var attempts atomic.Int32
+		opened := make(chan struct{})
+		mock := &mockSession{
+			openFunc: func() error {
+				if attempts.Add(1) < 3 {
+					return errors.New("connection refused")
+				}
+				close(opened)
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.OpenRetryLimit = 3
+		config.OpenRetryInterval = time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			adapter.Run(ctx, func(input sarah.Input) error { return nil }, func(err error) {
+				t.Errorf("Unexpected error: %+v", err)
+			})
+			close(done)
+		}()
+
+		select {
+		case <-opened:
+		case <-time.After(time.Second):
+			t.Fatal("Session was not opened")
+		}
+		cancel()
+		<-done
+
+		if attempts.Load() != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts.Load())
+		}
+	})
+
+	t.Run("gives up after the retry limit", func(t *testing.T) {
+		var attempts int
+		mock := &mockSession{
+			openFunc: func() error {
+				attempts++
+				return errors.New("connection refused")
+			},
+		}
+		config := NewConfig()
+		config.OpenRetryLimit = 2
+		config.OpenRetryInterval = time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		var notifiedErr error
+		adapter.Run(context.Background(), func(input sarah.Input) error { return nil }, func(err error) {
+			notifiedErr = err
+		})
+
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+		var nonContinuable *sarah.BotNonContinuableError
+		if !errors.As(notifiedErr, &nonContinuable) {
+			t.Errorf("Expected BotNonContinuableError, got %#v", notifiedErr)
+		}
+	})
+
+	t.Run("fatal close code is not retried", func(t *testing.T) {
+		var attempts int
+		mock := &mockSession{
+			openFunc: func() error {
+				attempts++
+				return &websocket.CloseError{Code: 4004, Text: "Authentication failed."}
+			},
+		}
+		config := NewConfig()
+		config.OpenRetryLimit = 5
+		config.OpenRetryInterval = time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		var notifiedErr error
+		adapter.Run(context.Background(), func(input sarah.Input) error { return nil }, func(err error) {
+			notifiedErr = err
+		})
+
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+		if notifiedErr == nil || !strings.Contains(notifiedErr.Error(), "4004") {
+			t.Errorf("Expected the close code to be notified, got %+v", notifiedErr)
+		}
+	})
+}
+
+func TestAdapter_Run_ReconnectOnInvalidSession(t *testing.T) {
+	t.Run("session is reopened on disconnection", func(t *testing.T) {
+		var disconnectHandler func(*discordgo.Session, *discordgo.Disconnect)
+		opens := make(chan struct{}, 10)
+		mock := &mockSession{
+			addHandlerFunc: func(handler interface{}) func() {
+				if h, ok := handler.(func(*discordgo.Session, *discordgo.Disconnect)); ok {
+					disconnectHandler = h
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				opens <- struct{}{}
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ReconnectOnInvalidSession = true
+		adapter := &Adapter{config: config, session: mock}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			adapter.Run(ctx, func(input sarah.Input) error { return nil }, func(err error) {
+				t.Errorf("Unexpected error: %+v", err)
+			})
+			close(done)
+		}()
+
+		<-opens
+		if disconnectHandler == nil {
+			t.Fatal("Expected a Disconnect handler to be registered")
+		}
+		disconnectHandler(nil, &discordgo.Disconnect{})
+
+		select {
+		case <-opens:
+		case <-time.After(time.Second):
+			t.Fatal("Session was not reopened")
+		}
+		cancel()
+		<-done
+	})
+
+	t.Run("single drop leads to a single reconnection", func(t *testing.T) {
+		var opens atomic.Int32
+		opened := make(chan struct{}, 10)
+		mock := &mockSession{
+			openFunc: func() error {
+				opens.Add(1)
+				select {
+				case opened <- struct{}{}:
+				default:
+				}
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ReconnectOnInvalidSession = true
+		adapter := &Adapter{config: config, session: mock}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			adapter.Run(ctx, func(input sarah.Input) error { return nil }, func(err error) {
+				t.Errorf("Unexpected error: %+v", err)
+			})
+			close(done)
+		}()
+
+		<-opened
+		// Simulate the drop. The mock emits another Disconnect event when the Adapter closes the session to reconnect.
+		mock.emitDisconnect()
+
+		select {
+		case <-opened:
+		case <-time.After(time.Second):
+			t.Fatal("Session was not reopened")
+		}
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		<-done
+
+		if opens.Load() != 2 {
+			t.Errorf("Expected 1 open and 1 reconnection, got %d opens", opens.Load())
+		}
+	})
+
+	t.Run("dropped session is closed to be resumed", func(t *testing.T) {
+		var closeCodes []int
+		mock := &mockSession{
+			closeWithCodeFunc: func(closeCode int) error {
+				closeCodes = append(closeCodes, closeCode)
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ReconnectOnInvalidSession = true
+		adapter := &Adapter{config: config, session: mock}
+
+		if err := adapter.reconnect(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if len(closeCodes) != 1 || closeCodes[0] != websocket.CloseServiceRestart {
+			t.Errorf("Expected the session to be closed with a resumable close code, got %v", closeCodes)
+		}
+	})
+
+	t.Run("fatal close code on reconnection stops the bot", func(t *testing.T) {
+		var disconnectHandler func(*discordgo.Session, *discordgo.Disconnect)
+		var attempts int
+		mock := &mockSession{
+			addHandlerFunc: func(handler interface{}) func() {
+				if h, ok := handler.(func(*discordgo.Session, *discordgo.Disconnect)); ok {
+					disconnectHandler = h
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				attempts++
+				if attempts == 1 {
+					// Trigger the reconnection right after the first successful open.
+					disconnectHandler(nil, &discordgo.Disconnect{})
+					return nil
+				}
+				return &websocket.CloseError{Code: 4014, Text: "Disallowed intent(s)."}
+			},
+		}
+		config := NewConfig()
+		config.ReconnectOnInvalidSession = true
+		config.OpenRetryLimit = 5
+		config.OpenRetryInterval = time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		var notifiedErr error
+		adapter.Run(context.Background(), func(input sarah.Input) error { return nil }, func(err error) {
+			notifiedErr = err
+		})
+
+		if attempts != 2 {
+			t.Errorf("Expected 2 attempts, got %d", attempts)
+		}
+		var nonContinuable *sarah.BotNonContinuableError
+		if !errors.As(notifiedErr, &nonContinuable) {
+			t.Errorf("Expected BotNonContinuableError, got %#v", notifiedErr)
+		}
+	})
+
+	t.Run("discordgo's own reconnection is disabled", func(t *testing.T) {
+		s, _ := discordgo.New("Bot token")
+		adapter := &Adapter{config: NewConfig(), session: s}
+
+		adapter.watchDisconnection()
+
+		if s.ShouldReconnectOnError {
+			t.Error("Expected ShouldReconnectOnError to be false")
+		}
+	})
+}
+
+func TestIsFatalGatewayError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{err: &websocket.CloseError{Code: 4004}, expected: true},
+		{err: &websocket.CloseError{Code: 4013}, expected: true},
+		{err: &websocket.CloseError{Code: 4007}, expected: false},
+		{err: &websocket.CloseError{Code: 4009}, expected: false},
+		{err: errors.New("connection refused"), expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := isFatalGatewayError(tt.err); got != tt.expected {
+			t.Errorf("Expected %t for %v, got %t", tt.expected, tt.err, got)
+		}
+	}
+}
+
+func TestAdapter_Run_MaxReconnectAttempts(t *testing.T) {
+	t.Run("transient failures are retried until success", func(t *testing.T) {
+		var disconnectHandler func(*discordgo.Session, *discordgo.Disconnect)
+		var mutex sync.Mutex
+		attempts := 0
+		closes := 0
+		reopened := make(chan struct{})
+		mock := &mockSession{
+			addHandlerFunc: func(handler interface{}) func() {
+				if h, ok := handler.(func(*discordgo.Session, *discordgo.Disconnect)); ok {
+					disconnectHandler = h
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				attempts++
+				switch {
+				case attempts == 1:
+					return nil
+				case attempts < 4:
+					return errors.New("temporary failure")
+				default:
+					close(reopened)
+					return nil
+				}
+			},
+			closeFunc: func() error {
+				mutex.Lock()
+				defer mutex.Unlock()
+				closes++
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ReconnectOnInvalidSession = true
+		config.MaxReconnectAttempts = 3
+		config.OpenRetryInterval = time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			adapter.Run(ctx, func(input sarah.Input) error { return nil }, func(err error) {
+				t.Errorf("Unexpected error: %+v", err)
+			})
+			close(done)
+		}()
+
+		// Wait for the initial open before simulating the drop.
+		for {
+			mutex.Lock()
+			opened := attempts > 0
+			mutex.Unlock()
+			if opened {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		disconnectHandler(nil, &discordgo.Disconnect{})
+
+		select {
+		case <-reopened:
+		case <-time.After(time.Second):
+			t.Fatal("Session was not reopened")
+		}
+		cancel()
+		<-done
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if closes < 1 {
+			t.Error("Expected the dropped session to be closed before reopening")
+		}
+	})
+
+	t.Run("exhausted attempts stop the bot", func(t *testing.T) {
+		var disconnectHandler func(*discordgo.Session, *discordgo.Disconnect)
+		attempts := 0
+		mock := &mockSession{
+			addHandlerFunc: func(handler interface{}) func() {
+				if h, ok := handler.(func(*discordgo.Session, *discordgo.Disconnect)); ok {
+					disconnectHandler = h
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				attempts++
+				if attempts == 1 {
+					disconnectHandler(nil, &discordgo.Disconnect{})
+					return nil
+				}
+				return errors.New("temporary failure")
+			},
+		}
+		config := NewConfig()
+		config.ReconnectOnInvalidSession = true
+		config.MaxReconnectAttempts = 3
+		config.OpenRetryInterval = time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		var notifiedErr error
+		adapter.Run(context.Background(), func(input sarah.Input) error { return nil }, func(err error) {
+			notifiedErr = err
+		})
+
+		if attempts != 4 {
+			t.Errorf("Expected 1 open and 3 reconnection attempts, got %d", attempts)
+		}
+		var nonContinuable *sarah.BotNonContinuableError
+		if !errors.As(notifiedErr, &nonContinuable) {
+			t.Errorf("Expected BotNonContinuableError, got %#v", notifiedErr)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// RespAsReply sends the response as a Discord reply to the input message.
+// When the input message is deleted before the response is sent, Discord rejects the reply and the failure is logged.
+func RespAsReply() RespOption {
+	return func(options *respOptions) {
+		options.reply = true
+	}
+}
+
+// replyTo converts the given response content to a *discordgo.MessageSend that references the input message.
+// The given *discordgo.MessageSend is copied so the caller's value is not modified.
+func replyTo(input *Input, content any) any {
+	reference := &discordgo.MessageReference{
+		MessageID: input.messageID,
+		ChannelID: string(input.channelID),
+		GuildID:   input.guildID,
+	}
+
+	switch typed := content.(type) {
+	case string:
+		// An empty string means no reply, e.g., when only a reaction is sent.
+		if typed == "" {
+			return content
+		}
+		return &discordgo.MessageSend{
+			Content:   typed,
+			Reference: reference,
+		}
+
+	case *discordgo.MessageSend:
+		copied := *typed
+		copied.Reference = reference
+		return &copied
+
+	default:
+		return content
+	}
+}
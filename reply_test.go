@@ -0,0 +1,123 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newReplyInput(guildID string) *Input {
+	input, _ := MessageToInput(&discordgo.MessageCreate{
+		Message: &discordgo.Message{
+			ID:        "msg-1",
+			ChannelID: "ch-1",
+			GuildID:   guildID,
+			Author:    &discordgo.User{ID: "user-1"},
+		},
+	})
+	return input
+}
+
+func TestRespAsReply(t *testing.T) {
+	t.Run("string content", func(t *testing.T) {
+		response, err := NewResponse(newReplyInput("guild-1"), "pong", RespAsReply())
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		data, ok := response.Content.(*discordgo.MessageSend)
+		if !ok {
+			t.Fatalf("Expected *discordgo.MessageSend, got %T", response.Content)
+		}
+		if data.Content != "pong" {
+			t.Errorf("Expected content %q, got %q", "pong", data.Content)
+		}
+		if data.Reference == nil || data.Reference.MessageID != "msg-1" || data.Reference.ChannelID != "ch-1" || data.Reference.GuildID != "guild-1" {
+			t.Errorf("Unexpected reference: %#v", data.Reference)
+		}
+	})
+
+	t.Run("rich content is copied", func(t *testing.T) {
+		original := &discordgo.MessageSend{Content: "pong"}
+		response, err := NewResponse(newReplyInput(""), original, RespAsReply())
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		data := response.Content.(*discordgo.MessageSend)
+		if data.Reference == nil || data.Reference.MessageID != "msg-1" || data.Reference.ChannelID != "ch-1" {
+			t.Errorf("Unexpected reference: %#v", data.Reference)
+		}
+		if original.Reference != nil {
+			t.Error("Original content should not be modified")
+		}
+	})
+
+	t.Run("rejection is also a reply", func(t *testing.T) {
+		response, err := NewResponse(newReplyInput(""), "pong", RespGuildOnly(""), RespAsReply())
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		data, ok := response.Content.(*discordgo.MessageSend)
+		if !ok || data.Content != defaultGuildOnlyMessage || data.Reference == nil {
+			t.Errorf("Unexpected content: %#v", response.Content)
+		}
+	})
+
+	t.Run("reaction without reply", func(t *testing.T) {
+		response, err := NewResponse(newReplyInput("guild-1"), "", RespAsReply(), RespWithReaction("👍"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		reaction, ok := response.Content.(*reactionResponse)
+		if !ok || reaction.Content != "" {
+			t.Errorf("Unexpected content: %#v", response.Content)
+		}
+	})
+}
+
+func TestAdapter_SendMessage_Reply(t *testing.T) {
+	t.Run("reply is sent with the reference", func(t *testing.T) {
+		var sent *discordgo.MessageSend
+		mock := &mockSession{
+			channelMessageSendComplexFunc: func(channelID string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				if channelID != "ch-1" {
+					t.Errorf("Unexpected channel: %s", channelID)
+				}
+				sent = data
+				return &discordgo.Message{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		input := newReplyInput("guild-1")
+		response, _ := NewResponse(input, "pong", RespAsReply())
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+		if sent == nil || sent.Reference == nil || sent.Reference.MessageID != "msg-1" || sent.Reference.ChannelID != "ch-1" {
+			t.Errorf("Unexpected message: %#v", sent)
+		}
+	})
+
+	t.Run("deleted message", func(t *testing.T) {
+		mock := &mockSession{
+			channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return nil, errors.New("HTTP 400 Bad Request, {\"message\": \"Invalid Form Body\", \"code\": 50035}")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		input := newReplyInput("guild-1")
+		response, _ := NewResponse(input, "pong", RespAsReply())
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), response.Content))
+
+		if adapter.Stats().Total.SendFailed != 1 {
+			t.Errorf("Expected the failure to be recorded: %#v", adapter.Stats())
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// RespWithRequestOptions applies the given discordgo.RequestOption values to the API calls that send the response,
+// e.g., discordgo.WithRetryOnRatelimit or discordgo.WithHeader.
+// The options are applied after Config.DefaultRequestOptions, so they take precedence.
+func RespWithRequestOptions(options ...discordgo.RequestOption) RespOption {
+	return func(stash *respOptions) {
+		stash.requestOptions = append(stash.requestOptions, options...)
+	}
+}
+
+// requestOptionsResponse is a response content that carries the request options to send the content with.
+type requestOptionsResponse struct {
+	Content any
+	Options []discordgo.RequestOption
+}
+
+// unwrapRequestOptions returns the content wrapped by RespWithRequestOptions and the options to send it with.
+// Other contents are returned as-is without options.
+func unwrapRequestOptions(content any) (any, []discordgo.RequestOption) {
+	if typed, ok := content.(*requestOptionsResponse); ok {
+		return typed.Content, typed.Options
+	}
+	return content, nil
+}
@@ -0,0 +1,108 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestRespWithRequestOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []RespOption
+	}{
+		{name: "string content", options: nil},
+		{name: "rich content", options: []RespOption{RespWithEmbeds(&discordgo.MessageEmbed{Title: "OK"})}},
+		{name: "reply with a reaction", options: []RespOption{RespWithReaction("👍")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received [][]discordgo.RequestOption
+			mock := &mockSession{
+				channelMessageSendFunc: func(_ string, _ string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+					received = append(received, options)
+					return &discordgo.Message{}, nil
+				},
+				channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+					received = append(received, options)
+					return &discordgo.Message{}, nil
+				},
+			}
+			config := NewConfig()
+			config.DefaultRequestOptions = []discordgo.RequestOption{discordgo.WithRestRetries(1), discordgo.WithRetryOnRatelimit(true)}
+			adapter := &Adapter{config: config, session: mock}
+
+			options := append(tt.options, RespWithRequestOptions(discordgo.WithRetryOnRatelimit(false), discordgo.WithHeader("X-Test", "1")))
+			response, err := NewResponse(newReplyInput("guild-1"), "hello", options...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			if err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), response.Content)); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			if len(received) != 1 {
+				t.Fatalf("Expected a single send, got %d", len(received))
+			}
+			cfg := applyRequestOptions(received[0])
+			if cfg.Request.Header.Get("X-Test") != "1" {
+				t.Error("Expected the given header to be set")
+			}
+			if cfg.ShouldRetryOnRateLimit {
+				t.Error("Expected the given option to take precedence over the default")
+			}
+			if cfg.MaxRestRetries != 1 {
+				t.Error("Expected the default option to be kept")
+			}
+		})
+	}
+}
+
+func TestAdapter_SendMessage_HelpRequestOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(*Config)
+	}{
+		{name: "plain text", config: func(*Config) {}},
+		{name: "embeds", config: func(c *Config) { c.HelpAsEmbed = true }},
+		{name: "select menus", config: func(c *Config) { c.HelpAsSelectMenu = true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received [][]discordgo.RequestOption
+			mock := &mockSession{
+				channelMessageSendFunc: func(_ string, _ string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+					received = append(received, options)
+					return &discordgo.Message{}, nil
+				},
+				channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+					received = append(received, options)
+					return &discordgo.Message{}, nil
+				},
+			}
+			config := NewConfig()
+			tt.config(config)
+			adapter := &Adapter{config: config, session: mock}
+
+			helps := newHelps(2)
+			content := &requestOptionsResponse{Content: &helps, Options: []discordgo.RequestOption{discordgo.WithHeader("X-Test", "1")}}
+			if err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), content)); err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			if len(received) == 0 {
+				t.Fatal("Expected the help to be sent")
+			}
+			for _, options := range received {
+				if applyRequestOptions(options).Request.Header.Get("X-Test") != "1" {
+					t.Error("Expected the given header to be set")
+				}
+			}
+		})
+	}
+}
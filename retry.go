@@ -0,0 +1,83 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxSendRetryInterval is the upper limit of the interval between retries to send a message.
+const maxSendRetryInterval = 30 * time.Second
+
+// sendRetryAfter tells if the failed send is worth retrying, and how long Discord asks to wait before the retry.
+// Server errors and rate limits are transient, while other client errors such as 403 and 404 fail again on retry.
+func sendRetryAfter(err error) (time.Duration, bool) {
+	var rateLimitErr *discordgo.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter, true
+	}
+
+	var restErr *discordgo.RESTError
+	if !errors.As(err, &restErr) || restErr.Response == nil {
+		return 0, false
+	}
+
+	switch status := restErr.Response.StatusCode; {
+	case status == http.StatusTooManyRequests:
+		seconds, _ := strconv.ParseFloat(restErr.Response.Header.Get("Retry-After"), 64)
+		return time.Duration(seconds * float64(time.Second)), true
+
+	case status >= http.StatusInternalServerError:
+		return 0, true
+
+	default:
+		return 0, false
+	}
+}
+
+// retrySend calls the given send function and retries on a transient failure up to Config.SendMaxRetries times.
+// The retries are spaced with exponential backoff starting from Config.SendRetryInterval, or by Retry-After when Discord specifies it.
+// Every call, including a retry, waits for its turn under Config.SendRateLimit since each one is a request to Discord.
+func (a *Adapter) retrySend(ctx context.Context, send func() error) error {
+	interval := a.config.SendRetryInterval
+	if interval <= 0 {
+		interval = NewConfig().SendRetryInterval
+	}
+
+	for attempt := 0; ; attempt++ {
+		// Wait for the turn rather than dropping the send when the rate is exceeded.
+		if err := a.waitSendRate(ctx); err != nil {
+			return fmt.Errorf("failed to wait for send rate limit: %w", err)
+		}
+
+		err := send()
+		if err == nil {
+			return nil
+		}
+
+		wait, retryable := sendRetryAfter(err)
+		if !retryable || attempt >= a.config.SendMaxRetries {
+			return err
+		}
+		if wait <= 0 {
+			wait = interval
+		}
+
+		a.log().Warnf("Failed to send message. Retrying in %s: %+v", wait, err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+
+		case <-timer.C:
+		}
+
+		interval = min(interval*2, maxSendRetryInterval)
+	}
+}
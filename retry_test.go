@@ -0,0 +1,125 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newStatusError(status int) error {
+	return &discordgo.RESTError{Response: &http.Response{StatusCode: status, Status: http.StatusText(status), Header: http.Header{}}}
+}
+
+func TestSendRetryAfter(t *testing.T) {
+	rateLimited := newStatusError(http.StatusTooManyRequests).(*discordgo.RESTError)
+	rateLimited.Response.Header.Set("Retry-After", "1.5")
+
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+		wait      time.Duration
+	}{
+		{name: "internal server error", err: newStatusError(http.StatusInternalServerError), retryable: true},
+		{name: "service unavailable", err: newStatusError(http.StatusServiceUnavailable), retryable: true},
+		{name: "too many requests", err: rateLimited, retryable: true, wait: 1500 * time.Millisecond},
+		{name: "rate limit", err: &discordgo.RateLimitError{RateLimit: &discordgo.RateLimit{TooManyRequests: &discordgo.TooManyRequests{RetryAfter: time.Second}}}, retryable: true, wait: time.Second},
+		{name: "forbidden", err: newStatusError(http.StatusForbidden), retryable: false},
+		{name: "not found", err: newStatusError(http.StatusNotFound), retryable: false},
+		{name: "other error", err: errors.New("connection refused"), retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, retryable := sendRetryAfter(tt.err)
+			if retryable != tt.retryable || wait != tt.wait {
+				t.Errorf("Expected %t and %s, got %t and %s", tt.retryable, tt.wait, retryable, wait)
+			}
+		})
+	}
+}
+
+func TestAdapter_SendMessage_Retry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		errs       []error
+		attempts   int
+		succeeded  bool
+	}{
+		{name: "transient failure", maxRetries: 2, errs: []error{newStatusError(http.StatusServiceUnavailable)}, attempts: 2, succeeded: true},
+		{name: "client error", maxRetries: 2, errs: []error{newStatusError(http.StatusForbidden)}, attempts: 1, succeeded: false},
+		{
+			name:       "retries exhausted",
+			maxRetries: 1,
+			errs:       []error{newStatusError(http.StatusBadGateway), newStatusError(http.StatusBadGateway)},
+			attempts:   2,
+			succeeded:  false,
+		},
+		{name: "retry disabled", maxRetries: 0, errs: []error{newStatusError(http.StatusInternalServerError)}, attempts: 1, succeeded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			send := func() error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			}
+			mock := &mockSession{
+				channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+					return &discordgo.Message{}, send()
+				},
+				channelMessageSendComplexFunc: func(_ string, _ *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+					return &discordgo.Message{}, send()
+				},
+			}
+			config := NewConfig()
+			config.SendMaxRetries = tt.maxRetries
+			config.SendRetryInterval = time.Millisecond
+			adapter := &Adapter{config: config, session: mock}
+
+			for _, content := range []any{"hello", &discordgo.MessageSend{Content: "hello"}} {
+				attempts = 0
+				err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), content))
+
+				if attempts != tt.attempts {
+					t.Errorf("Expected %d attempts for %T, got %d", tt.attempts, content, attempts)
+				}
+				if (err == nil) != tt.succeeded {
+					t.Errorf("Unexpected result for %T: %+v", content, err)
+				}
+			}
+		})
+	}
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		attempts := 0
+		mock := &mockSession{
+			channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+				attempts++
+				return nil, newStatusError(http.StatusServiceUnavailable)
+			},
+		}
+		config := NewConfig()
+		config.SendMaxRetries = 3
+		config.SendRetryInterval = time.Hour
+		adapter := &Adapter{config: config, session: mock}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := adapter.SendMessageWithError(ctx, sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+
+		if err == nil || attempts != 1 {
+			t.Errorf("Expected the retry to stop on cancellation, got %d attempts and %+v", attempts, err)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+)
+
+// AddRole assigns the given role to the member, e.g., for reaction roles or leveling.
+// The bot needs the Manage Roles permission, and its highest role must be above the given role.
+// The error is returned so the caller can report a missing permission.
+// Pass ModerationReason to record why the role was assigned in the audit log.
+func (a *Adapter) AddRole(ctx context.Context, guildID string, userID string, roleID string, options ...ModerationOption) error {
+	err := a.session.GuildMemberRoleAdd(guildID, userID, roleID, a.moderationRequestOptions(ctx, options)...)
+	if err != nil {
+		return fmt.Errorf("failed to add role %s to %s in guild %s: %w", roleID, userID, guildID, err)
+	}
+	return nil
+}
+
+// RemoveRole removes the given role from the member.
+// The same permission as AddRole is required, and the error is returned in the same manner.
+// Pass ModerationReason to record why the role was removed in the audit log.
+func (a *Adapter) RemoveRole(ctx context.Context, guildID string, userID string, roleID string, options ...ModerationOption) error {
+	err := a.session.GuildMemberRoleRemove(guildID, userID, roleID, a.moderationRequestOptions(ctx, options)...)
+	if err != nil {
+		return fmt.Errorf("failed to remove role %s from %s in guild %s: %w", roleID, userID, guildID, err)
+	}
+	return nil
+}
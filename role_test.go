@@ -0,0 +1,85 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAdapter_AddRole(t *testing.T) {
+	t.Run("added", func(t *testing.T) {
+		var given []string
+		var options []discordgo.RequestOption
+		mock := &mockSession{
+			guildMemberRoleAddFunc: func(guildID string, userID string, roleID string, opts ...discordgo.RequestOption) error {
+				given = []string{guildID, userID, roleID}
+				options = opts
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.AddRole(context.Background(), "guild-1", "user-1", "role-1", ModerationReason("Reached level 10")); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if len(given) != 3 || given[0] != "guild-1" || given[1] != "user-1" || given[2] != "role-1" {
+			t.Errorf("Unexpected IDs: %v", given)
+		}
+		if got := applyRequestOptions(options).Request.Header.Get("X-Audit-Log-Reason"); got != "Reached level 10" {
+			t.Errorf("Expected the reason to be forwarded, got %q", got)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		forbidden := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+		mock := &mockSession{
+			guildMemberRoleAddFunc: func(_ string, _ string, _ string, _ ...discordgo.RequestOption) error {
+				return forbidden
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		err := adapter.AddRole(context.Background(), "guild-1", "user-1", "role-1")
+		if !errors.Is(err, forbidden) {
+			t.Errorf("Expected the original error to be wrapped, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_RemoveRole(t *testing.T) {
+	t.Run("removed", func(t *testing.T) {
+		var given []string
+		mock := &mockSession{
+			guildMemberRoleRemoveFunc: func(guildID string, userID string, roleID string, _ ...discordgo.RequestOption) error {
+				given = []string{guildID, userID, roleID}
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.RemoveRole(context.Background(), "guild-1", "user-1", "role-1"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if len(given) != 3 || given[0] != "guild-1" || given[1] != "user-1" || given[2] != "role-1" {
+			t.Errorf("Unexpected IDs: %v", given)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		forbidden := &discordgo.RESTError{Response: &http.Response{StatusCode: http.StatusForbidden}}
+		mock := &mockSession{
+			guildMemberRoleRemoveFunc: func(_ string, _ string, _ string, _ ...discordgo.RequestOption) error {
+				return forbidden
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		err := adapter.RemoveRole(context.Background(), "guild-1", "user-1", "role-1")
+		if !errors.Is(err, forbidden) {
+			t.Errorf("Expected the original error to be wrapped, got %+v", err)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package discord
+
+import (
+	"fmt"
+	"sync"
+)
+
+// maxPendingPerSender is the maximum number of messages waiting for a sender's preceding message to be handled.
+// Further messages are dropped so a flooding sender cannot grow the queue without bound.
+const maxPendingPerSender = 32
+
+// senderQueues runs tasks of the same sender one by one in the order they were dispatched,
+// while tasks of different senders run concurrently.
+// A queue exists only while its sender has a running task, so idle senders do not consume memory.
+// The zero value is ready to use.
+type senderQueues struct {
+	mutex  sync.Mutex
+	queues map[string][]func() // Sender key to pending tasks; a key is present while its task is running
+}
+
+// dispatch runs the task in the calling goroutine once the sender's preceding tasks finish.
+// When the sender has a running task, the task is queued and run by the goroutine running the preceding task, so dispatch returns immediately.
+// When limit or more senders have running tasks, a new sender's task runs right away without being tracked.
+// false is returned when the task is dropped because the sender has too many pending tasks.
+func (q *senderQueues) dispatch(key string, limit int, task func()) bool {
+	q.mutex.Lock()
+
+	if q.queues == nil {
+		q.queues = map[string][]func(){}
+	}
+
+	if pending, running := q.queues[key]; running {
+		if len(pending) >= maxPendingPerSender {
+			q.mutex.Unlock()
+			return false
+		}
+		q.queues[key] = append(pending, task)
+		q.mutex.Unlock()
+		return true
+	}
+
+	if limit > 0 && len(q.queues) >= limit {
+		// Give up ordering rather than blocking or dropping the message.
+		q.mutex.Unlock()
+		task()
+		return true
+	}
+
+	q.queues[key] = nil
+	q.mutex.Unlock()
+
+	q.drain(key, task)
+	return true
+}
+
+// drain runs the given task and then the sender's pending tasks until none is left.
+func (q *senderQueues) drain(key string, task func()) {
+	for task != nil {
+		task()
+
+		q.mutex.Lock()
+		pending := q.queues[key]
+		if len(pending) == 0 {
+			delete(q.queues, key)
+			task = nil
+		} else {
+			task = pending[0]
+			q.queues[key] = pending[1:]
+		}
+		q.mutex.Unlock()
+	}
+}
+
+// senderKeyOf returns the same key as Input.SenderKey for the given message.
+func senderKeyOf(channelID string, authorID string) string {
+	return fmt.Sprintf("%s_%s", channelID, authorID)
+}
@@ -0,0 +1,131 @@
+package discord
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSenderQueues(t *testing.T) {
+	t.Run("tasks of the same sender run in order", func(t *testing.T) {
+		q := &senderQueues{}
+		release := make(chan struct{})
+		started := make(chan struct{})
+		var mutex sync.Mutex
+		var order []int
+		record := func(i int) func() {
+			return func() {
+				mutex.Lock()
+				defer mutex.Unlock()
+				order = append(order, i)
+			}
+		}
+
+		done := make(chan struct{})
+		go func() {
+			q.dispatch("sender", 0, func() {
+				close(started)
+				<-release
+				record(1)()
+			})
+			close(done)
+		}()
+		<-started
+
+		for i := 2; i <= 5; i++ {
+			if !q.dispatch("sender", 0, record(i)) {
+				t.Fatalf("Task %d was dropped", i)
+			}
+		}
+		close(release)
+		<-done
+
+		if len(order) != 5 {
+			t.Fatalf("Expected 5 tasks, got %v", order)
+		}
+		for i, v := range order {
+			if v != i+1 {
+				t.Errorf("Expected tasks in order, got %v", order)
+				break
+			}
+		}
+		if len(q.queues) != 0 {
+			t.Errorf("Expected the idle queue to be removed, got %d", len(q.queues))
+		}
+	})
+
+	t.Run("different senders run concurrently", func(t *testing.T) {
+		q := &senderQueues{}
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go q.dispatch("sender-1", 0, func() {
+			close(started)
+			<-release
+		})
+		<-started
+		defer close(release)
+
+		ran := make(chan struct{})
+		go q.dispatch("sender-2", 0, func() {
+			close(ran)
+		})
+
+		select {
+		case <-ran:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the other sender's task to run while the first one is blocked")
+		}
+	})
+
+	t.Run("too many pending tasks are dropped", func(t *testing.T) {
+		q := &senderQueues{}
+		release := make(chan struct{})
+		started := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			q.dispatch("sender", 0, func() {
+				close(started)
+				<-release
+			})
+			close(done)
+		}()
+		<-started
+
+		for i := 0; i < maxPendingPerSender; i++ {
+			if !q.dispatch("sender", 0, func() {}) {
+				t.Fatalf("Task %d was dropped", i)
+			}
+		}
+		if q.dispatch("sender", 0, func() {}) {
+			t.Error("Expected the task to be dropped")
+		}
+		close(release)
+		<-done
+	})
+
+	t.Run("new sender runs right away over the limit", func(t *testing.T) {
+		q := &senderQueues{}
+		release := make(chan struct{})
+		started := make(chan struct{})
+		go q.dispatch("sender-1", 1, func() {
+			close(started)
+			<-release
+		})
+		<-started
+		defer close(release)
+
+		var ran bool
+		q.dispatch("sender-2", 1, func() {
+			ran = true
+		})
+
+		if !ran {
+			t.Error("Expected the task to run")
+		}
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		if _, ok := q.queues["sender-2"]; ok {
+			t.Error("Expected the sender over the limit not to be tracked")
+		}
+	})
+}
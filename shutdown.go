@@ -0,0 +1,74 @@
+package discord
+
+import (
+	"sync"
+	"time"
+)
+
+// drainSends stops accepting new sends and waits for the in-flight ones to finish so closing the session does not cut them off.
+// The wait is bounded by Config.ShutdownGracePeriod, and nothing is waited for when the period is not positive.
+func (a *Adapter) drainSends() {
+	idle := a.sends.close()
+
+	grace := a.config.ShutdownGracePeriod
+	if grace <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-idle:
+
+	case <-timer.C:
+		a.log().Warnf("In-flight sends did not finish within %s. Closing Discord session anyway.", grace)
+	}
+}
+
+// inFlightSends counts the sends that are running or queued, and rejects new ones once closed.
+// The zero value is ready to use.
+type inFlightSends struct {
+	mutex   sync.Mutex
+	count   int
+	closing bool
+	idle    chan struct{} // Closed when no send is counted after close
+}
+
+// begin counts a new send. false is returned when the sends are closed.
+func (s *inFlightSends) begin() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closing {
+		return false
+	}
+	s.count++
+	return true
+}
+
+// end marks a send counted by begin as finished.
+func (s *inFlightSends) end() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.count--
+	if s.count == 0 && s.closing {
+		close(s.idle)
+	}
+}
+
+// close rejects new sends and returns a channel that is closed when every counted send finishes.
+func (s *inFlightSends) close() <-chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.closing {
+		s.closing = true
+		s.idle = make(chan struct{})
+		if s.count == 0 {
+			close(s.idle)
+		}
+	}
+	return s.idle
+}
@@ -0,0 +1,154 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_Run_DrainSends(t *testing.T) {
+	tests := []struct {
+		name      string
+		grace     time.Duration
+		sendTakes time.Duration
+		sendDone  bool
+	}{
+		{name: "close waits for the in-flight send", grace: time.Second, sendTakes: 50 * time.Millisecond, sendDone: true},
+		{name: "close waits up to the grace period", grace: 50 * time.Millisecond, sendTakes: time.Second, sendDone: false},
+		{name: "zero grace period closes right away", grace: 0, sendTakes: time.Second, sendDone: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sending := make(chan struct{})
+			sent := make(chan struct{})
+			release := make(chan struct{})
+			defer close(release)
+
+			closed := make(chan bool, 1)
+			mock := &mockSession{
+				channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+					close(sending)
+					select {
+					case <-time.After(tt.sendTakes):
+					case <-release:
+					}
+					close(sent)
+					return &discordgo.Message{}, nil
+				},
+				closeFunc: func() error {
+					select {
+					case <-sent:
+						closed <- true
+					default:
+						closed <- false
+					}
+					return nil
+				},
+			}
+			config := NewConfig()
+			config.ShutdownGracePeriod = tt.grace
+			adapter := &Adapter{config: config, session: mock}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			finished := make(chan struct{})
+			go func() {
+				adapter.Run(ctx, func(sarah.Input) error { return nil }, func(error) {})
+				close(finished)
+			}()
+
+			go adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+			<-sending
+			started := time.Now()
+			cancel()
+
+			select {
+			case <-finished:
+			case <-time.After(3 * time.Second):
+				t.Fatal("Run did not return")
+			}
+
+			if sendDone := <-closed; sendDone != tt.sendDone {
+				t.Errorf("Expected the send to be done on close: %t", tt.sendDone)
+			}
+			if elapsed := time.Since(started); !tt.sendDone && elapsed > tt.grace+500*time.Millisecond {
+				t.Errorf("Expected close within the grace period, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestAdapter_Run_DrainQueuedSends(t *testing.T) {
+	sending := make(chan struct{}, 2)
+	var mutex sync.Mutex
+	var sent []string
+	closedAfter := make(chan int, 1)
+	mock := &mockSession{
+		channelMessageSendFunc: func(_ string, content string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			sending <- struct{}{}
+			time.Sleep(50 * time.Millisecond)
+			mutex.Lock()
+			defer mutex.Unlock()
+			sent = append(sent, content)
+			return &discordgo.Message{}, nil
+		},
+		closeFunc: func() error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			closedAfter <- len(sent)
+			return nil
+		},
+	}
+	config := NewConfig()
+	config.ShutdownGracePeriod = time.Second
+	config.SerializePerChannel = true
+	adapter := &Adapter{config: config, session: mock}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	go func() {
+		adapter.Run(ctx, func(sarah.Input) error { return nil }, func(error) {})
+		close(finished)
+	}()
+
+	go adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "first"))
+	<-sending
+	// The second message waits in the channel's queue while the first one is sent.
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "second"))
+	cancel()
+
+	select {
+	case <-finished:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Run did not return")
+	}
+
+	if n := <-closedAfter; n != 2 {
+		t.Errorf("Expected the queued send to finish before close, got %d sends", n)
+	}
+}
+
+func TestAdapter_SendMessageWithError_ShuttingDown(t *testing.T) {
+	mock := &mockSession{
+		channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+			t.Error("ChannelMessageSend should not be called after shutdown")
+			return &discordgo.Message{}, nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+
+	adapter.drainSends()
+
+	err := adapter.SendMessageWithError(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "hello"))
+	if !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown, got %#v", err)
+	}
+	if adapter.Stats().Total.SendFailed != 1 {
+		t.Errorf("Expected the rejected send to be counted as failed: %+v", adapter.Stats().Total)
+	}
+}
@@ -0,0 +1,134 @@
+package discord
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// statsBucketCount is the number of one-second buckets that constitute the rolling window.
+	statsBucketCount = 60
+
+	// StatsWindow is the duration of the rolling window that Stats.Recent covers.
+	StatsWindow = statsBucketCount * time.Second
+)
+
+// Stats is a snapshot of the message throughput handled by the Adapter.
+type Stats struct {
+	// Total contains the counts since the Adapter was created.
+	Total StatsCounts
+
+	// Recent contains the counts within the last StatsWindow.
+	Recent StatsCounts
+}
+
+// StatsCounts contains the counts of messages handled by the Adapter.
+type StatsCounts struct {
+	// Received is the number of messages received from Discord.
+	Received uint64
+
+	// Enqueued is the number of received messages passed to go-sarah.
+	Enqueued uint64
+
+	// Dropped is the number of received messages that were not passed to go-sarah.
+	// This includes messages from the bot itself, other bots, and blocked users, messages from disabled guilds, and messages that failed to be enqueued.
+	Dropped uint64
+
+	// SendSucceeded is the number of messages successfully sent via SendMessage.
+	SendSucceeded uint64
+
+	// SendFailed is the number of messages that SendMessage failed to send.
+	SendFailed uint64
+}
+
+// Stats returns a snapshot of the message throughput.
+// This is cheap enough to be called on every request from a command or a metrics exporter.
+func (a *Adapter) Stats() Stats {
+	return a.stats.snapshot(time.Now())
+}
+
+// statsRecorder records the message throughput. The zero value is ready to use.
+type statsRecorder struct {
+	received      rollingCounter
+	enqueued      rollingCounter
+	dropped       rollingCounter
+	sendSucceeded rollingCounter
+	sendFailed    rollingCounter
+}
+
+func (r *statsRecorder) recordSend(err error) {
+	if err != nil {
+		r.sendFailed.increment()
+	} else {
+		r.sendSucceeded.increment()
+	}
+}
+
+func (r *statsRecorder) snapshot(now time.Time) Stats {
+	return Stats{
+		Total: StatsCounts{
+			Received:      r.received.total.Load(),
+			Enqueued:      r.enqueued.total.Load(),
+			Dropped:       r.dropped.total.Load(),
+			SendSucceeded: r.sendSucceeded.total.Load(),
+			SendFailed:    r.sendFailed.total.Load(),
+		},
+		Recent: StatsCounts{
+			Received:      r.received.recent(now),
+			Enqueued:      r.enqueued.recent(now),
+			Dropped:       r.dropped.recent(now),
+			SendSucceeded: r.sendSucceeded.recent(now),
+			SendFailed:    r.sendFailed.recent(now),
+		},
+	}
+}
+
+// rollingCounter is a lock-free counter that also counts the occurrences within the last StatsWindow.
+//
+// Each bucket packs the second it belongs to in the upper 32 bits and the count in the lower 32 bits,
+// so a stale bucket is reset and incremented in a single compare-and-swap operation.
+type rollingCounter struct {
+	total   atomic.Uint64
+	buckets [statsBucketCount]atomic.Uint64
+}
+
+func (c *rollingCounter) increment() {
+	c.incrementAt(time.Now())
+}
+
+func (c *rollingCounter) incrementAt(now time.Time) {
+	c.total.Add(1)
+
+	sec := uint64(uint32(now.Unix()))
+	bucket := &c.buckets[sec%statsBucketCount]
+	for {
+		old := bucket.Load()
+
+		var next uint64
+		if old>>32 == sec {
+			next = old + 1
+		} else {
+			next = sec<<32 | 1
+		}
+
+		if bucket.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+// recent returns the sum of the counts within the last StatsWindow.
+func (c *rollingCounter) recent(now time.Time) uint64 {
+	current := uint32(now.Unix())
+
+	var sum uint64
+	for i := range c.buckets {
+		value := c.buckets[i].Load()
+
+		// The subtraction wraps around along with the packed second.
+		if age := current - uint32(value>>32); age < statsBucketCount {
+			sum += value & 0xFFFFFFFF
+		}
+	}
+	return sum
+}
@@ -0,0 +1,129 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestRollingCounter(t *testing.T) {
+	t.Run("counts within the window", func(t *testing.T) {
+		c := &rollingCounter{}
+		base := time.Unix(1_700_000_000, 0)
+
+		c.incrementAt(base)
+		c.incrementAt(base)
+		c.incrementAt(base.Add(30 * time.Second))
+
+		if got := c.recent(base.Add(30 * time.Second)); got != 3 {
+			t.Errorf("Expected 3, got %d", got)
+		}
+
+		// The first two fall out of the window.
+		if got := c.recent(base.Add(StatsWindow)); got != 1 {
+			t.Errorf("Expected 1, got %d", got)
+		}
+
+		if got := c.recent(base.Add(30*time.Second + StatsWindow)); got != 0 {
+			t.Errorf("Expected 0, got %d", got)
+		}
+
+		if got := c.total.Load(); got != 3 {
+			t.Errorf("Expected total 3, got %d", got)
+		}
+	})
+
+	t.Run("stale bucket is reset", func(t *testing.T) {
+		c := &rollingCounter{}
+		base := time.Unix(1_700_000_000, 0)
+
+		c.incrementAt(base)
+		c.incrementAt(base.Add(StatsWindow)) // Same bucket, next lap
+
+		if got := c.recent(base.Add(StatsWindow)); got != 1 {
+			t.Errorf("Expected 1, got %d", got)
+		}
+	})
+
+	t.Run("concurrent increments", func(t *testing.T) {
+		c := &rollingCounter{}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					c.increment()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if got := c.total.Load(); got != 5000 {
+			t.Errorf("Expected total 5000, got %d", got)
+		}
+		if got := c.recent(time.Now()); got != 5000 {
+			t.Errorf("Expected recent 5000, got %d", got)
+		}
+	})
+}
+
+func TestAdapter_Stats(t *testing.T) {
+	botID := "bot-user-123"
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: botID}
+
+	newMessage := func(authorID string) *discordgo.MessageCreate {
+		return &discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "ch-1",
+				Content:   "hello",
+				Timestamp: time.Now(),
+				Author:    &discordgo.User{ID: authorID},
+			},
+		}
+	}
+
+	var failSend bool
+	mock := &mockSession{
+		channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+			if failSend {
+				return nil, fmt.Errorf("send failed")
+			}
+			return &discordgo.Message{}, nil
+		},
+	}
+	adapter := &Adapter{config: NewConfig(), session: mock}
+
+	enqueue := func(input sarah.Input) error { return nil }
+	adapter.handleMessage(context.Background(), s, newMessage("user-1"), enqueue)
+	adapter.handleMessage(context.Background(), s, newMessage("user-2"), enqueue)
+	adapter.handleMessage(context.Background(), s, newMessage(botID), enqueue)
+	adapter.handleMessage(context.Background(), s, newMessage("user-3"), func(input sarah.Input) error { return fmt.Errorf("queue full") })
+
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "ok"))
+	failSend = true
+	adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), "ng"))
+
+	expected := StatsCounts{
+		Received:      4,
+		Enqueued:      2,
+		Dropped:       2,
+		SendSucceeded: 1,
+		SendFailed:    1,
+	}
+
+	stats := adapter.Stats()
+	if stats.Total != expected {
+		t.Errorf("Expected total %+v, got %+v", expected, stats.Total)
+	}
+	if stats.Recent != expected {
+		t.Errorf("Expected recent %+v, got %+v", expected, stats.Recent)
+	}
+}
@@ -0,0 +1,231 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// MaxMessageLength is the maximum number of characters Discord accepts in a single message.
+const MaxMessageLength = 2000
+
+// StreamWriter progressively publishes written text to a Discord channel.
+// The first flush sends a new message and the subsequent flushes edit that message.
+// When the content exceeds MaxMessageLength, the message is finalized and the rest rolls over to a new message.
+//
+// StreamWriter satisfies io.WriteCloser and is safe for concurrent use.
+// Close must be called to flush the final state and to stop the background flusher.
+//
+// The messages go through the same pipeline as Adapter.SendMessage: Config.DryRun, Config.SendRateLimit, the retries,
+// and Config.DefaultAllowedMentions apply, and the whole stream counts as a single in-flight send on shutdown.
+type StreamWriter struct {
+	ctx       context.Context
+	adapter   *Adapter
+	channelID string
+	interval  time.Duration
+
+	mutex     sync.Mutex
+	message   *discordgo.Message
+	published string
+	pending   string
+	lastFlush time.Time
+	closed    bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StreamResponse creates a *StreamWriter that publishes written text to the given channel.
+// Edits are throttled by Config.StreamEditInterval, and the final state is flushed by StreamWriter.Close.
+// ErrShuttingDown is returned once the adapter started shutting down.
+func (a *Adapter) StreamResponse(ctx context.Context, dest ChannelID) (*StreamWriter, error) {
+	if dest == "" {
+		return nil, errors.New("destination channel ID is empty")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The stream is in flight until Close so the shutdown waits for its final flush.
+	if !a.sends.begin() {
+		return nil, ErrShuttingDown
+	}
+
+	interval := a.config.StreamEditInterval
+	if interval <= 0 {
+		interval = NewConfig().StreamEditInterval
+	}
+
+	w := &StreamWriter{
+		ctx:       ctx,
+		adapter:   a,
+		channelID: string(dest),
+		interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go w.run()
+
+	return w, nil
+}
+
+// run periodically flushes the buffered text until the writer is closed or the context is canceled.
+func (w *StreamWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+
+		case <-w.ctx.Done():
+			return
+
+		case <-ticker.C:
+			w.mutex.Lock()
+			err := w.flush()
+			w.mutex.Unlock()
+			if err != nil {
+				w.adapter.log().Errorf("Failed to flush streamed message to %s: %+v", w.channelID, err)
+			}
+		}
+	}
+}
+
+// Write appends the given text to the message being streamed.
+// The text is published immediately when the edit interval has passed since the last flush; otherwise, the background flusher publishes it later.
+// Text beyond MaxMessageLength is also held until then, so rolling over to a new message does not bypass the interval.
+func (w *StreamWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return 0, ErrStreamClosed
+	}
+
+	w.pending += string(p)
+
+	if time.Since(w.lastFlush) >= w.interval {
+		if err := w.flush(); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close flushes the final state and stops the background flusher.
+// Calling Close more than once is a no-op.
+func (w *StreamWriter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mutex.Unlock()
+
+	close(w.stop)
+	<-w.done
+	defer w.adapter.sends.end()
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.flush()
+}
+
+// rollover finalizes the current message while the buffered text exceeds MaxMessageLength,
+// and carries the rest over to a new message.
+func (w *StreamWriter) rollover() error {
+	for utf8.RuneCountInString(w.pending) > MaxMessageLength {
+		head, rest := splitMessage(w.pending, MaxMessageLength)
+		if head != w.published {
+			if err := w.publish(head); err != nil {
+				return err
+			}
+		}
+
+		w.message = nil
+		w.published = ""
+		w.pending = rest
+	}
+
+	return nil
+}
+
+// flush publishes the buffered text if it differs from what is currently displayed.
+func (w *StreamWriter) flush() error {
+	if err := w.rollover(); err != nil {
+		return err
+	}
+
+	if w.pending == "" || w.pending == w.published {
+		return nil
+	}
+
+	return w.publish(w.pending)
+}
+
+// publish sends a new message or edits the current one so that it displays the given content.
+func (w *StreamWriter) publish(content string) error {
+	a := w.adapter
+	switch {
+	case a.config.DryRun:
+		a.log().Infof("[dry run] Streamed message to %s: %s", w.channelID, content)
+		if w.message == nil {
+			w.message = &discordgo.Message{ChannelID: w.channelID}
+			a.messageSent(ChannelID(w.channelID), nil)
+		}
+
+	case w.message == nil:
+		message, err := a.sendChunk(w.ctx, w.channelID, content)
+		a.messageSent(ChannelID(w.channelID), err)
+		if err != nil {
+			return err
+		}
+		w.message = message
+
+	default:
+		if err := a.EditMessage(w.ctx, ChannelID(w.channelID), w.message.ID, content); err != nil {
+			return err
+		}
+	}
+
+	w.published = content
+	w.lastFlush = time.Now()
+	return nil
+}
+
+// splitMessage splits the given text so that the head contains at most limit characters.
+// The split prefers the last line break, then the last space, within the limit so words are not cut in half.
+func splitMessage(text string, limit int) (string, string) {
+	if utf8.RuneCountInString(text) <= limit {
+		return text, ""
+	}
+
+	// Find the byte offset of the limit-th rune.
+	offset := 0
+	for i := 0; i < limit; i++ {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+
+	head := text[:offset]
+	if i := strings.LastIndex(head, "\n"); i > 0 {
+		return text[:i], text[i+1:]
+	}
+	if i := strings.LastIndex(head, " "); i > 0 {
+		return text[:i], text[i+1:]
+	}
+
+	return head, text[offset:]
+}
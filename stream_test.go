@@ -0,0 +1,399 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestAdapter_StreamResponse(t *testing.T) {
+	t.Run("empty destination", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		_, err := adapter.StreamResponse(context.Background(), "")
+		if err == nil {
+			t.Fatal("Expected an error for empty destination")
+		}
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := adapter.StreamResponse(ctx, ChannelID("ch-1"))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %+v", err)
+		}
+	})
+
+	t.Run("non-positive interval falls back to default", func(t *testing.T) {
+		config := NewConfig()
+		config.StreamEditInterval = 0
+		adapter := &Adapter{config: config, session: &mockSession{}}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		defer func() { _ = w.Close() }()
+
+		if w.interval != NewConfig().StreamEditInterval {
+			t.Errorf("Expected default interval, got %s", w.interval)
+		}
+	})
+
+	t.Run("default request options are used", func(t *testing.T) {
+		var received []discordgo.RequestOption
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				received = options
+				return &discordgo.Message{ID: "msg-1"}, nil
+			},
+		}
+		config := NewConfig()
+		config.DefaultRequestOptions = []discordgo.RequestOption{discordgo.WithHeader("X-Trace-Id", "trace-1")}
+		adapter := &Adapter{config: config, session: mock}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		_, _ = w.Write([]byte("hello"))
+		_ = w.Close()
+
+		if applyRequestOptions(received).Request.Header.Get("X-Trace-Id") != "trace-1" {
+			t.Error("Expected the default request options to be used")
+		}
+	})
+}
+
+func TestStreamWriter(t *testing.T) {
+	t.Run("first write sends and later writes edit", func(t *testing.T) {
+		var mutex sync.Mutex
+		var sent []string
+		var edits []string
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				sent = append(sent, content)
+				return &discordgo.Message{ID: "msg-1", ChannelID: channelID}, nil
+			},
+			channelMessageEditFunc: func(channelID, messageID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				if messageID != "msg-1" {
+					t.Errorf("Expected message ID %q, got %q", "msg-1", messageID)
+				}
+				edits = append(edits, content)
+				return &discordgo.Message{ID: messageID, ChannelID: channelID}, nil
+			},
+		}
+		config := NewConfig()
+		config.StreamEditInterval = time.Hour // Only explicit flushes
+		adapter := &Adapter{config: config, session: mock}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		_, _ = fmt.Fprint(w, "Hello")
+		_, _ = fmt.Fprint(w, ", World")
+		_, _ = fmt.Fprint(w, "!")
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Unexpected error on Close: %+v", err)
+		}
+
+		if len(sent) != 1 || sent[0] != "Hello" {
+			t.Errorf("Expected a single initial send of %q, got %v", "Hello", sent)
+		}
+		if len(edits) != 1 || edits[0] != "Hello, World!" {
+			t.Errorf("Expected final edit of %q, got %v", "Hello, World!", edits)
+		}
+	})
+
+	t.Run("background flusher publishes buffered text", func(t *testing.T) {
+		edited := make(chan string, 10)
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return &discordgo.Message{ID: "msg-1"}, nil
+			},
+			channelMessageEditFunc: func(channelID, messageID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				edited <- content
+				return &discordgo.Message{ID: messageID}, nil
+			},
+		}
+		config := NewConfig()
+		config.StreamEditInterval = 10 * time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		defer func() { _ = w.Close() }()
+
+		_, _ = w.Write([]byte("a"))
+		_, _ = w.Write([]byte("b"))
+
+		select {
+		case content := <-edited:
+			if content != "ab" {
+				t.Errorf("Expected %q, got %q", "ab", content)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected the background flusher to edit the message")
+		}
+	})
+
+	t.Run("long content rolls over to a new message", func(t *testing.T) {
+		var sent []string
+		var edits []string
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sent = append(sent, content)
+				return &discordgo.Message{ID: fmt.Sprintf("msg-%d", len(sent))}, nil
+			},
+			channelMessageEditFunc: func(channelID, messageID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				edits = append(edits, content)
+				return &discordgo.Message{ID: messageID}, nil
+			},
+		}
+		config := NewConfig()
+		config.StreamEditInterval = time.Hour
+		adapter := &Adapter{config: config, session: mock}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		first := strings.Repeat("a", MaxMessageLength-10)
+		second := strings.Repeat("b", 100)
+		_, _ = w.Write([]byte(first + " " + second))
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Unexpected error on Close: %+v", err)
+		}
+
+		if len(sent) != 2 {
+			t.Fatalf("Expected 2 messages to be sent, got %d", len(sent))
+		}
+		if sent[0] != first {
+			t.Errorf("Expected the first message to be split at the space")
+		}
+		if sent[1] != second {
+			t.Errorf("Expected the second message to contain the rest, got %q", sent[1])
+		}
+		if len(edits) != 0 {
+			t.Errorf("Expected no edits, got %d", len(edits))
+		}
+	})
+
+	t.Run("write after close", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		_ = w.Close()
+		if err := w.Close(); err != nil {
+			t.Errorf("Expected second Close to be a no-op, got %+v", err)
+		}
+
+		_, err = w.Write([]byte("late"))
+		if !errors.Is(err, ErrStreamClosed) {
+			t.Errorf("Expected ErrStreamClosed, got %+v", err)
+		}
+	})
+
+	t.Run("rollover waits for the edit interval", func(t *testing.T) {
+		var sent []string
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				sent = append(sent, content)
+				return &discordgo.Message{ID: fmt.Sprintf("msg-%d", len(sent))}, nil
+			},
+			channelMessageEditFunc: func(channelID, messageID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return &discordgo.Message{ID: messageID}, nil
+			},
+		}
+		config := NewConfig()
+		config.StreamEditInterval = time.Hour
+		adapter := &Adapter{config: config, session: mock}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		_, _ = w.Write([]byte("hello"))
+		_, _ = w.Write([]byte(strings.Repeat("a", MaxMessageLength)))
+		if len(sent) != 1 {
+			t.Errorf("Expected the rollover to wait for the interval, got %d messages", len(sent))
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatalf("Unexpected error on Close: %+v", err)
+		}
+		if len(sent) != 2 {
+			t.Errorf("Expected the rest to be sent on Close, got %d messages", len(sent))
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				t.Error("Expected nothing to be sent")
+				return nil, nil
+			},
+			channelMessageEditFunc: func(channelID, messageID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				t.Error("Expected nothing to be edited")
+				return nil, nil
+			},
+		}
+		logger := &capturingLogger{}
+		config := NewConfig()
+		config.DryRun = true
+		config.StreamEditInterval = time.Hour
+		adapter := &Adapter{config: config, session: mock, logger: logger}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		_, _ = w.Write([]byte("hello"))
+		_, _ = w.Write([]byte(", world"))
+		if err := w.Close(); err != nil {
+			t.Fatalf("Unexpected error on Close: %+v", err)
+		}
+
+		if !logger.has("INFO", "hello, world") {
+			t.Error("Expected the streamed content to be logged")
+		}
+		if sent := adapter.Stats().Total.SendSucceeded; sent != 1 {
+			t.Errorf("Expected the stream to count as 1 sent message, got %d", sent)
+		}
+	})
+
+	t.Run("send rate limit applies", func(t *testing.T) {
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				t.Error("Expected the send to wait for its turn")
+				return &discordgo.Message{ID: "msg-1"}, nil
+			},
+		}
+		config := NewConfig()
+		config.SendRateLimit = 0.1
+		adapter := &Adapter{config: config, session: mock}
+		adapter.sendLimiter.reserve(config.SendRateLimit, time.Now())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		w, err := adapter.StreamResponse(ctx, ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		defer func() { _ = w.Close() }()
+
+		_, err = w.Write([]byte("hello"))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the rate limit wait to time out, got %+v", err)
+		}
+		if failed := adapter.Stats().Total.SendFailed; failed != 1 {
+			t.Errorf("Expected 1 failed send, got %d", failed)
+		}
+	})
+
+	t.Run("stream is in flight until closed", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		idle := adapter.sends.close()
+		select {
+		case <-idle:
+			t.Fatal("Expected the open stream to be in flight")
+		default:
+		}
+
+		_ = w.Close()
+		select {
+		case <-idle:
+		case <-time.After(time.Second):
+			t.Fatal("Expected Close to end the in-flight send")
+		}
+
+		_, err = adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if !errors.Is(err, ErrShuttingDown) {
+			t.Errorf("Expected ErrShuttingDown, got %+v", err)
+		}
+	})
+
+	t.Run("send error is returned", func(t *testing.T) {
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID, content string, opts ...discordgo.RequestOption) (*discordgo.Message, error) {
+				return nil, fmt.Errorf("send failed")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		w, err := adapter.StreamResponse(context.Background(), ChannelID("ch-1"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		defer func() { _ = w.Close() }()
+
+		_, err = w.Write([]byte("hello"))
+		if err == nil {
+			t.Error("Expected an error when sending fails")
+		}
+	})
+}
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		head  string
+		rest  string
+	}{
+		{name: "within limit", text: "hello", limit: 10, head: "hello", rest: ""},
+		{name: "split at newline", text: "hello\nworld", limit: 8, head: "hello", rest: "world"},
+		{name: "split at space", text: "hello world", limit: 8, head: "hello", rest: "world"},
+		{name: "hard split", text: "helloworld", limit: 5, head: "hello", rest: "world"},
+		{name: "multibyte characters", text: "あいうえお", limit: 3, head: "あいう", rest: "えお"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, rest := splitMessage(tt.text, tt.limit)
+			if head != tt.head {
+				t.Errorf("Expected head %q, got %q", tt.head, head)
+			}
+			if rest != tt.rest {
+				t.Errorf("Expected rest %q, got %q", tt.rest, rest)
+			}
+			if utf8.RuneCountInString(head) > tt.limit {
+				t.Errorf("Head exceeds the limit: %d", utf8.RuneCountInString(head))
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package discord
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// TemplateContext is passed to the template executed by NewTemplateResponse.
+// Fields derived from the user's input are escaped with EscapeText so that the rendered text does not
+// unintentionally ping users or break formatting. Use Input to access the raw values.
+type TemplateContext struct {
+	// Input is the received input.
+	Input *Input
+
+	// AuthorID is the ID of the message author.
+	AuthorID string
+
+	// AuthorName is the escaped username of the message author.
+	AuthorName string
+
+	// ChannelID is the ID of the channel where the message was sent.
+	ChannelID string
+
+	// GuildID is the ID of the guild where the message was sent. This is empty for direct messages.
+	GuildID string
+
+	// Message is the escaped text of the received message.
+	Message string
+
+	// Data is the arbitrary value given to NewTemplateResponse.
+	Data interface{}
+}
+
+// TemplateFuncs returns a template.FuncMap that is useful to render Discord messages.
+// Register this before parsing a template passed to NewTemplateResponse:
+//
+//	tmpl := template.Must(template.New("greet").Funcs(discord.TemplateFuncs()).Parse(`Hello, {{mentionUser .AuthorID}}!`))
+//
+// The returned map contains the following functions:
+//
+//   - mentionUser: renders a user mention from a user ID.
+//   - mentionChannel: renders a channel mention from a channel ID.
+//   - mentionRole: renders a role mention from a role ID.
+//   - escape: escapes the given text with EscapeText.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"mentionUser":    MentionUser,
+		"mentionChannel": MentionChannel,
+		"mentionRole":    MentionRole,
+		"escape":         EscapeText,
+		"timestamp":      FormatTimestamp,
+	}
+}
+
+// NewTemplateResponse executes the given template with *TemplateContext and creates a *sarah.CommandResponse with the rendered text.
+// The given data is accessible as .Data in the template.
+func NewTemplateResponse(input sarah.Input, tmpl *template.Template, data interface{}, options ...RespOption) (*sarah.CommandResponse, error) {
+	typed, ok := input.(*Input)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a *discord.Input", input)
+	}
+
+	ctx := &TemplateContext{
+		Input:     typed,
+		ChannelID: string(typed.channelID),
+		Message:   EscapeText(typed.Message()),
+		Data:      data,
+	}
+	if typed.Event != nil {
+		ctx.GuildID = typed.Event.GuildID
+		if typed.Event.Author != nil {
+			ctx.AuthorID = typed.Event.Author.ID
+			ctx.AuthorName = EscapeText(typed.Event.Author.Username)
+		}
+	}
+
+	var builder strings.Builder
+	if err := tmpl.Execute(&builder, ctx); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", tmpl.Name(), err)
+	}
+
+	return NewResponse(input, builder.String(), options...)
+}
+
+// MentionUser returns a string that mentions the user with the given ID.
+func MentionUser(userID string) string {
+	return "<@" + userID + ">"
+}
+
+// MentionChannel returns a string that mentions the channel with the given ID.
+func MentionChannel(channelID string) string {
+	return "<#" + channelID + ">"
+}
+
+// MentionRole returns a string that mentions the role with the given ID.
+func MentionRole(roleID string) string {
+	return "<@&" + roleID + ">"
+}
+
+// TimestampStyle represents how Discord renders a timestamp token.
+type TimestampStyle string
+
+const (
+	// TimestampStyleDefault is rendered the same as TimestampStyleShortDateTime.
+	TimestampStyleDefault TimestampStyle = ""
+
+	// TimestampStyleShortTime is rendered like "16:20".
+	TimestampStyleShortTime TimestampStyle = "t"
+
+	// TimestampStyleLongTime is rendered like "16:20:30".
+	TimestampStyleLongTime TimestampStyle = "T"
+
+	// TimestampStyleShortDate is rendered like "20/04/2021".
+	TimestampStyleShortDate TimestampStyle = "d"
+
+	// TimestampStyleLongDate is rendered like "20 April 2021".
+	TimestampStyleLongDate TimestampStyle = "D"
+
+	// TimestampStyleShortDateTime is rendered like "20 April 2021 16:20".
+	TimestampStyleShortDateTime TimestampStyle = "f"
+
+	// TimestampStyleLongDateTime is rendered like "Tuesday, 20 April 2021 16:20".
+	TimestampStyleLongDateTime TimestampStyle = "F"
+
+	// TimestampStyleRelativeTime is rendered like "2 months ago".
+	TimestampStyleRelativeTime TimestampStyle = "R"
+)
+
+// FormatTimestamp returns a timestamp token that Discord renders in each user's timezone and locale.
+// Sub-second precision is truncated since Discord only supports seconds.
+func FormatTimestamp(t time.Time, style TimestampStyle) string {
+	unix := strconv.FormatInt(t.Unix(), 10)
+	if style == TimestampStyleDefault {
+		return "<t:" + unix + ">"
+	}
+	return "<t:" + unix + ":" + string(style) + ">"
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"`", "\\`",
+	"|", `\|`,
+	">", `\>`,
+	// A zero-width space after @ prevents @everyone, @here, and mention tokens from pinging anyone.
+	"@", "@\u200b",
+)
+
+// EscapeText escapes Discord's markdown syntax and neutralizes mentions in the given text
+// so the text is displayed as-is.
+func EscapeText(text string) string {
+	return markdownEscaper.Replace(text)
+}
+
+var mentionSanitizer = strings.NewReplacer(
+	"@everyone", "@\u200beveryone",
+	"@here", "@\u200bhere",
+)
+
+// SanitizeMentions neutralizes @everyone and @here in the given text with a zero-width space, e.g., for an echo command,
+// so the text does not ping everyone when it is sent back. Unlike EscapeText, other markdown and mentions are kept.
+// Role and user mentions are suppressed by Config.DefaultAllowedMentions instead.
+func SanitizeMentions(text string) string {
+	return mentionSanitizer.Replace(text)
+}
@@ -0,0 +1,227 @@
+package discord
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestNewTemplateResponse(t *testing.T) {
+	newInput := func(content string, username string) *Input {
+		input, _ := MessageToInput(&discordgo.MessageCreate{
+			Message: &discordgo.Message{
+				ChannelID: "ch-1",
+				GuildID:   "guild-1",
+				Content:   content,
+				Timestamp: time.Now(),
+				Author:    &discordgo.User{ID: "user-1", Username: username},
+			},
+		})
+		return input
+	}
+
+	t.Run("input variables and data", func(t *testing.T) {
+		tmpl := template.Must(template.New("greet").Funcs(TemplateFuncs()).Parse(
+			`Hi {{mentionUser .AuthorID}} in {{mentionChannel .ChannelID}} of {{.GuildID}}. {{.Data}}`,
+		))
+
+		resp, err := NewTemplateResponse(newInput("hello", "alice"), tmpl, "Welcome!")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		expected := "Hi <@user-1> in <#ch-1> of guild-1. Welcome!"
+		if resp.Content != expected {
+			t.Errorf("Expected %q, got %q", expected, resp.Content)
+		}
+	})
+
+	t.Run("input-derived values are escaped", func(t *testing.T) {
+		tmpl := template.Must(template.New("echo").Parse(`{{.AuthorName}} said {{.Message}}`))
+
+		resp, err := NewTemplateResponse(newInput("@everyone **look** <@123>", "*bob*"), tmpl, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		content := resp.Content.(string)
+		if strings.Contains(content, "@everyone") {
+			t.Errorf("Expected @everyone to be neutralized, got %q", content)
+		}
+		if strings.Contains(content, "<@123>") {
+			t.Errorf("Expected the mention to be neutralized, got %q", content)
+		}
+		if strings.Contains(content, "**look**") || strings.Contains(content, " *bob* ") {
+			t.Errorf("Expected markdown to be escaped, got %q", content)
+		}
+	})
+
+	t.Run("template directives in the input are not evaluated", func(t *testing.T) {
+		tmpl := template.Must(template.New("echo").Parse(`{{.Message}}`))
+
+		resp, err := NewTemplateResponse(newInput(`{{.AuthorID}}`, "alice"), tmpl, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if resp.Content != `{{.AuthorID}}` {
+			t.Errorf("Expected the input to be rendered literally, got %q", resp.Content)
+		}
+	})
+
+	t.Run("raw input is accessible", func(t *testing.T) {
+		tmpl := template.Must(template.New("raw").Parse(`{{.Input.Message}}`))
+
+		resp, err := NewTemplateResponse(newInput("**raw**", "alice"), tmpl, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if resp.Content != "**raw**" {
+			t.Errorf("Expected %q, got %q", "**raw**", resp.Content)
+		}
+	})
+
+	t.Run("options are applied", func(t *testing.T) {
+		tmpl := template.Must(template.New("next").Parse(`next`))
+		next := func(_ context.Context, _ sarah.Input) (*sarah.CommandResponse, error) { return nil, nil }
+
+		resp, err := NewTemplateResponse(newInput("hello", "alice"), tmpl, nil, RespWithNext(next))
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if resp.UserContext == nil {
+			t.Error("Expected UserContext to be set")
+		}
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+		tmpl := template.Must(template.New("broken").Parse(`{{.Data.Missing}}`))
+
+		_, err := NewTemplateResponse(newInput("hello", "alice"), tmpl, 1)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("non-discord input", func(t *testing.T) {
+		tmpl := template.Must(template.New("x").Parse(`x`))
+
+		_, err := NewTemplateResponse(sarah.NewHelpInput(newInput("hello", "alice")), tmpl, nil)
+		if err == nil {
+			t.Error("Expected an error for non-discord Input")
+		}
+	})
+}
+
+func TestMentions(t *testing.T) {
+	if MentionUser("1") != "<@1>" {
+		t.Errorf("Unexpected user mention: %q", MentionUser("1"))
+	}
+	if MentionChannel("2") != "<#2>" {
+		t.Errorf("Unexpected channel mention: %q", MentionChannel("2"))
+	}
+	if MentionRole("3") != "<@&3>" {
+		t.Errorf("Unexpected role mention: %q", MentionRole("3"))
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	moment := time.Date(2021, 4, 20, 16, 20, 30, 500_000_000, time.UTC)
+
+	tests := []struct {
+		style    TimestampStyle
+		expected string
+	}{
+		{style: TimestampStyleDefault, expected: "<t:1618935630>"},
+		{style: TimestampStyleShortTime, expected: "<t:1618935630:t>"},
+		{style: TimestampStyleLongTime, expected: "<t:1618935630:T>"},
+		{style: TimestampStyleShortDate, expected: "<t:1618935630:d>"},
+		{style: TimestampStyleLongDate, expected: "<t:1618935630:D>"},
+		{style: TimestampStyleShortDateTime, expected: "<t:1618935630:f>"},
+		{style: TimestampStyleLongDateTime, expected: "<t:1618935630:F>"},
+		{style: TimestampStyleRelativeTime, expected: "<t:1618935630:R>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := FormatTimestamp(moment, tt.style); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("timezone does not matter", func(t *testing.T) {
+		local := moment.In(time.FixedZone("JST", 9*60*60))
+		if got := FormatTimestamp(local, TimestampStyleRelativeTime); got != "<t:1618935630:R>" {
+			t.Errorf("Unexpected token: %q", got)
+		}
+	})
+
+	t.Run("before epoch", func(t *testing.T) {
+		if got := FormatTimestamp(time.Unix(-60, 0), TimestampStyleShortDate); got != "<t:-60:d>" {
+			t.Errorf("Unexpected token: %q", got)
+		}
+	})
+
+	t.Run("template func", func(t *testing.T) {
+		tmpl := template.Must(template.New("ts").Funcs(TemplateFuncs()).Parse(`{{ timestamp .Data "R" }}`))
+		input := &Input{channelID: "ch-1"}
+
+		res, err := NewTemplateResponse(input, tmpl, moment)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		if res.Content != "<t:1618935630:R>" {
+			t.Errorf("Unexpected content: %v", res.Content)
+		}
+	})
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "plain", expected: "plain"},
+		{input: "**bold**", expected: `\*\*bold\*\*`},
+		{input: "`code`", expected: "\\`code\\`"},
+		{input: "||spoiler||", expected: `\|\|spoiler\|\|`},
+		{input: `a\b`, expected: `a\\b`},
+		{input: "@here", expected: "@\u200bhere"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := EscapeText(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSanitizeMentions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "plain", expected: "plain"},
+		{input: "@everyone", expected: "@\u200beveryone"},
+		{input: "hi @here and @everyone", expected: "hi @\u200bhere and @\u200beveryone"},
+		{input: "**bold** <@123> <@&456>", expected: "**bold** <@123> <@&456>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := SanitizeMentions(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
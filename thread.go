@@ -0,0 +1,192 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+// StartConversationThread starts a thread from the given input's message so the conversation continues in the thread.
+// Inputs sent in the thread are replied to in the thread, and the conversation state is kept per thread.
+//
+// When Config.ArchiveThreadOnCompletion is true, the thread is archived once the conversation completes,
+// i.e., a response without the next step is sent to the thread, or the conversation is aborted in the thread.
+func (a *Adapter) StartConversationThread(ctx context.Context, input *Input, name string) (*discordgo.Channel, error) {
+	if input.Event == nil || input.Event.Message == nil {
+		return nil, errors.New("input has no message to start a thread from")
+	}
+
+	if input.Event.GuildID == "" {
+		return nil, ErrDirectMessage
+	}
+
+	data := &discordgo.ThreadStart{
+		Name:                name,
+		AutoArchiveDuration: a.config.ThreadAutoArchiveDuration,
+	}
+	thread, err := a.session.MessageThreadStartComplex(string(input.channelID), input.Event.ID, data, a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start thread from message %s: %w", input.Event.ID, err)
+	}
+
+	a.threads.add(thread.ID)
+	return thread, nil
+}
+
+// CreateThread creates a public thread in the given channel and returns its channel ID, which can be used as a destination to send messages to the thread.
+// Unlike StartConversationThread, the thread is not started from a message and its conversation is not tracked.
+// autoArchiveMinutes must be one of 60, 1440, 4320, or 10080; zero falls back to Config.ThreadAutoArchiveDuration.
+func (a *Adapter) CreateThread(ctx context.Context, channelID ChannelID, name string, autoArchiveMinutes int) (ChannelID, error) {
+	if channelID == "" {
+		return "", errors.New("channel ID is empty")
+	}
+
+	if autoArchiveMinutes == 0 {
+		autoArchiveMinutes = a.config.ThreadAutoArchiveDuration
+	} else if !slices.Contains(threadAutoArchiveDurations, autoArchiveMinutes) {
+		return "", fmt.Errorf("auto archive duration must be one of %v: %d", threadAutoArchiveDurations, autoArchiveMinutes)
+	}
+
+	data := &discordgo.ThreadStart{
+		Name:                name,
+		AutoArchiveDuration: autoArchiveMinutes,
+		Type:                discordgo.ChannelTypeGuildPublicThread,
+	}
+	thread, err := a.session.ThreadStartComplex(string(channelID), data, a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create thread in channel %s: %w", channelID, err)
+	}
+
+	return ChannelID(thread.ID), nil
+}
+
+// completeConversation marks the conversation in the input's channel as completed
+// so archiveCompletedThread archives the thread, e.g., when the conversation is aborted without any response.
+// This does nothing unless the input was sent in a thread started by StartConversationThread.
+func (a *Adapter) completeConversation(input *Input) {
+	if a.config.ArchiveThreadOnCompletion {
+		a.threads.complete(string(input.channelID))
+	}
+}
+
+// inConversationThread tells if the input was sent in a thread started by StartConversationThread whose completion is tracked.
+func (a *Adapter) inConversationThread(input *Input) bool {
+	return a.config.ArchiveThreadOnCompletion && a.threads.touch(string(input.channelID), time.Now())
+}
+
+// completingResponse is a response content that completes the conversation in a thread once it is sent.
+type completingResponse struct {
+	Content any
+}
+
+// unwrapCompletion returns the content wrapped by NewResponse for a completing response, and whether it completes the conversation.
+// Other contents are returned as-is.
+func unwrapCompletion(content any) (any, bool) {
+	if typed, ok := content.(*completingResponse); ok {
+		return typed.Content, true
+	}
+	return content, false
+}
+
+// archiveCompletedThread archives the given thread if its conversation is completed.
+func (a *Adapter) archiveCompletedThread(ctx context.Context, channelID string) {
+	if !a.config.ArchiveThreadOnCompletion || !a.threads.takeCompleted(channelID) {
+		return
+	}
+
+	if msg := a.config.ThreadClosingMessage; msg != "" {
+		err := a.sendMessage(ctx, sarah.NewOutputMessage(ChannelID(channelID), msg))
+		if err != nil {
+			a.log().Errorf("Failed to send closing message to %s: %+v", channelID, err)
+		}
+	}
+
+	if a.config.DryRun {
+		a.log().Infof("[dry run] Archive thread %s", channelID)
+		return
+	}
+
+	archived := true
+	_, err := a.session.ChannelEditComplex(channelID, &discordgo.ChannelEdit{Archived: &archived}, a.requestOptions()...)
+	if err != nil {
+		a.log().Errorf("Failed to archive thread %s: %+v", channelID, err)
+	}
+}
+
+// threadTrackingTTL is how long a thread is tracked without any response.
+// This is the longest auto archive duration, after which Discord archives the idle thread by itself.
+const threadTrackingTTL = 7 * 24 * time.Hour
+
+// threadRegistry keeps track of the threads started by the Adapter and whether their conversations are completed.
+// A thread without any response for threadTrackingTTL is forgotten so abandoned conversations do not pile up.
+// The zero value is ready to use.
+type threadRegistry struct {
+	mutex   sync.Mutex
+	threads map[string]*trackedThread
+}
+
+type trackedThread struct {
+	completed bool
+	lastSeen  time.Time
+}
+
+func (r *threadRegistry) add(threadID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	if r.threads == nil {
+		r.threads = map[string]*trackedThread{}
+	}
+
+	// Threads are started far less often than messages are sent, so sweep the expired ones here.
+	for id, thread := range r.threads {
+		if now.Sub(thread.lastSeen) > threadTrackingTTL {
+			delete(r.threads, id)
+		}
+	}
+
+	r.threads[threadID] = &trackedThread{lastSeen: now}
+}
+
+// touch tells if the thread is registered, and extends its tracking if so.
+func (r *threadRegistry) touch(threadID string, now time.Time) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	thread, ok := r.threads[threadID]
+	if !ok || now.Sub(thread.lastSeen) > threadTrackingTTL {
+		delete(r.threads, threadID)
+		return false
+	}
+	thread.lastSeen = now
+	return true
+}
+
+// complete marks the thread as completed if the thread is registered.
+func (r *threadRegistry) complete(threadID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if thread, ok := r.threads[threadID]; ok {
+		thread.completed = true
+	}
+}
+
+// takeCompleted tells if the thread is completed, and forgets the thread if so.
+func (r *threadRegistry) takeCompleted(threadID string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if thread, ok := r.threads[threadID]; !ok || !thread.completed {
+		return false
+	}
+	delete(r.threads, threadID)
+	return true
+}
@@ -0,0 +1,306 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func newThreadInput(adapter *Adapter, channelID string) *Input {
+	input, _ := MessageToInput(&discordgo.MessageCreate{
+		Message: &discordgo.Message{
+			ID:        "msg-1",
+			ChannelID: channelID,
+			GuildID:   "guild-1",
+			Author:    &discordgo.User{ID: "user-1"},
+			Content:   "help me",
+		},
+	})
+	input.adapter = adapter
+	return input
+}
+
+func TestAdapter_StartConversationThread(t *testing.T) {
+	t.Run("thread is started from the message", func(t *testing.T) {
+		var started *discordgo.ThreadStart
+		mock := &mockSession{
+			messageThreadStartComplexFunc: func(channelID string, messageID string, data *discordgo.ThreadStart, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				if channelID != "ch-1" || messageID != "msg-1" {
+					t.Errorf("Unexpected target: %s/%s", channelID, messageID)
+				}
+				started = data
+				return &discordgo.Channel{ID: messageID}, nil
+			},
+		}
+		config := NewConfig()
+		config.ThreadAutoArchiveDuration = 60
+		adapter := &Adapter{config: config, session: mock}
+
+		thread, err := adapter.StartConversationThread(context.Background(), newThreadInput(adapter, "ch-1"), "Support")
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if thread.ID != "msg-1" {
+			t.Errorf("Unexpected thread: %#v", thread)
+		}
+		if started.Name != "Support" || started.AutoArchiveDuration != 60 {
+			t.Errorf("Unexpected thread settings: %#v", started)
+		}
+	})
+
+	t.Run("direct message", func(t *testing.T) {
+		adapter := &Adapter{config: NewConfig(), session: &mockSession{}}
+		input := newThreadInput(adapter, "dm-1")
+		input.Event.GuildID = ""
+
+		_, err := adapter.StartConversationThread(context.Background(), input, "Support")
+		if !errors.Is(err, ErrDirectMessage) {
+			t.Errorf("Expected ErrDirectMessage, got %+v", err)
+		}
+	})
+}
+
+func TestAdapter_CreateThread(t *testing.T) {
+	t.Run("thread is created in the channel", func(t *testing.T) {
+		var started *discordgo.ThreadStart
+		mock := &mockSession{
+			threadStartComplexFunc: func(channelID string, data *discordgo.ThreadStart, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				if channelID != "ch-1" {
+					t.Errorf("Unexpected channel: %s", channelID)
+				}
+				started = data
+				return &discordgo.Channel{ID: "thread-1"}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		threadID, err := adapter.CreateThread(context.Background(), ChannelID("ch-1"), "Incident", 1440)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if threadID != "thread-1" {
+			t.Errorf("Unexpected thread ID: %s", threadID)
+		}
+		if started.Name != "Incident" || started.AutoArchiveDuration != 1440 || started.Type != discordgo.ChannelTypeGuildPublicThread {
+			t.Errorf("Unexpected thread settings: %#v", started)
+		}
+	})
+
+	t.Run("zero duration falls back to the config", func(t *testing.T) {
+		var started *discordgo.ThreadStart
+		mock := &mockSession{
+			threadStartComplexFunc: func(_ string, data *discordgo.ThreadStart, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				started = data
+				return &discordgo.Channel{ID: "thread-1"}, nil
+			},
+		}
+		config := NewConfig()
+		config.ThreadAutoArchiveDuration = 60
+		adapter := &Adapter{config: config, session: mock}
+
+		if _, err := adapter.CreateThread(context.Background(), ChannelID("ch-1"), "Incident", 0); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if started.AutoArchiveDuration != 60 {
+			t.Errorf("Expected the configured duration, got %d", started.AutoArchiveDuration)
+		}
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		mock := &mockSession{
+			threadStartComplexFunc: func(_ string, _ *discordgo.ThreadStart, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				t.Error("ThreadStartComplex should not be called")
+				return &discordgo.Channel{}, nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if _, err := adapter.CreateThread(context.Background(), ChannelID("ch-1"), "Incident", 30); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mock := &mockSession{
+			threadStartComplexFunc: func(_ string, _ *discordgo.ThreadStart, _ ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				return nil, errors.New("missing permission")
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if _, err := adapter.CreateThread(context.Background(), ChannelID("ch-1"), "Incident", 60); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestAdapter_ArchiveThreadOnCompletion(t *testing.T) {
+	type recorder struct {
+		sent     []string
+		archived []string
+	}
+	setup := func(enabled bool) (*Adapter, *recorder) {
+		rec := &recorder{}
+		mock := &mockSession{
+			channelMessageSendFunc: func(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+				rec.sent = append(rec.sent, content)
+				return &discordgo.Message{}, nil
+			},
+			channelEditComplexFunc: func(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+				if data.Archived == nil || !*data.Archived {
+					t.Errorf("Expected the thread to be archived: %#v", data)
+				}
+				rec.archived = append(rec.archived, channelID)
+				return &discordgo.Channel{ID: channelID}, nil
+			},
+		}
+		config := NewConfig()
+		config.ArchiveThreadOnCompletion = enabled
+		config.ThreadClosingMessage = "Closing this thread."
+		adapter := &Adapter{config: config, session: mock}
+
+		starter := newThreadInput(adapter, "ch-1")
+		if _, err := adapter.StartConversationThread(context.Background(), starter, "Support"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		return adapter, rec
+	}
+
+	respond := func(adapter *Adapter, input *Input, options ...RespOption) {
+		res, err := NewResponse(input, "done", options...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		adapter.SendMessage(context.Background(), sarah.NewOutputMessage(input.ReplyTo(), res.Content))
+	}
+
+	t.Run("continuing conversation keeps the thread", func(t *testing.T) {
+		adapter, rec := setup(true)
+
+		respond(adapter, newThreadInput(adapter, "msg-1"), RespWithNext(func(context.Context, sarah.Input) (*sarah.CommandResponse, error) {
+			return nil, nil
+		}))
+
+		if len(rec.archived) != 0 {
+			t.Errorf("Expected the thread to stay open, got %v", rec.archived)
+		}
+	})
+
+	t.Run("completed conversation archives the thread", func(t *testing.T) {
+		adapter, rec := setup(true)
+
+		respond(adapter, newThreadInput(adapter, "msg-1"))
+
+		if len(rec.archived) != 1 || rec.archived[0] != "msg-1" {
+			t.Fatalf("Expected the thread to be archived, got %v", rec.archived)
+		}
+		if len(rec.sent) != 2 || rec.sent[0] != "done" || rec.sent[1] != "Closing this thread." {
+			t.Errorf("Expected the response and the closing message, got %v", rec.sent)
+		}
+
+		// The thread is forgotten once archived.
+		respond(adapter, newThreadInput(adapter, "msg-1"))
+		if len(rec.archived) != 1 {
+			t.Errorf("Expected the thread to be archived only once, got %v", rec.archived)
+		}
+	})
+
+	t.Run("unsent response keeps the thread", func(t *testing.T) {
+		adapter, rec := setup(true)
+
+		if _, err := NewResponse(newThreadInput(adapter, "msg-1"), "done"); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		respond(adapter, newThreadInput(adapter, "msg-1"), RespWithNext(func(context.Context, sarah.Input) (*sarah.CommandResponse, error) {
+			return nil, nil
+		}))
+
+		if len(rec.archived) != 0 {
+			t.Errorf("Expected the thread to stay open until the completing response is sent, got %v", rec.archived)
+		}
+	})
+
+	t.Run("failed send keeps the thread", func(t *testing.T) {
+		adapter, rec := setup(true)
+		adapter.session.(*mockSession).channelMessageSendFunc = func(string, string, ...discordgo.RequestOption) (*discordgo.Message, error) {
+			return nil, errors.New("missing permission")
+		}
+
+		respond(adapter, newThreadInput(adapter, "msg-1"))
+
+		if len(rec.archived) != 0 {
+			t.Errorf("Expected the thread to stay open, got %v", rec.archived)
+		}
+	})
+
+	t.Run("responses outside started threads are ignored", func(t *testing.T) {
+		adapter, rec := setup(true)
+
+		respond(adapter, newThreadInput(adapter, "other-thread"))
+
+		if len(rec.archived) != 0 {
+			t.Errorf("Expected no archival, got %v", rec.archived)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		adapter, rec := setup(false)
+
+		respond(adapter, newThreadInput(adapter, "msg-1"))
+
+		if len(rec.archived) != 0 {
+			t.Errorf("Expected no archival, got %v", rec.archived)
+		}
+	})
+
+	t.Run("abort archives the thread", func(t *testing.T) {
+		adapter, rec := setup(true)
+
+		m := newThreadInput(adapter, "msg-1").Event
+		m.Content = adapter.config.AbortCommand
+		adapter.handleMessage(context.Background(), &discordgo.Session{State: discordgo.NewState()}, m, func(sarah.Input) error { return nil })
+
+		if len(rec.archived) != 1 {
+			t.Errorf("Expected the thread to be archived, got %v", rec.archived)
+		}
+	})
+}
+
+func TestThreadRegistry(t *testing.T) {
+	t.Run("idle thread expires", func(t *testing.T) {
+		registry := &threadRegistry{}
+		registry.add("thread-1")
+
+		if !registry.touch("thread-1", time.Now()) {
+			t.Fatal("Expected the thread to be tracked")
+		}
+		if registry.touch("thread-1", time.Now().Add(threadTrackingTTL+time.Minute)) {
+			t.Error("Expected the idle thread to expire")
+		}
+		if len(registry.threads) != 0 {
+			t.Errorf("Expected the expired thread to be forgotten, got %d", len(registry.threads))
+		}
+	})
+
+	t.Run("expired threads are swept on add", func(t *testing.T) {
+		registry := &threadRegistry{}
+		registry.add("thread-1")
+		registry.threads["thread-1"].lastSeen = time.Now().Add(-threadTrackingTTL - time.Minute)
+
+		registry.add("thread-2")
+
+		if _, ok := registry.threads["thread-1"]; ok {
+			t.Error("Expected the expired thread to be swept")
+		}
+		if _, ok := registry.threads["thread-2"]; !ok {
+			t.Error("Expected the new thread to be tracked")
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package discord
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// RespWithTTS sends the response as a text-to-speech message, which is read aloud to users viewing the channel.
+// This requires the bot to have the Send TTS Messages permission in the channel;
+// otherwise, Discord silently sends the message as a regular one.
+func RespWithTTS() RespOption {
+	return func(options *respOptions) {
+		options.tts = true
+	}
+}
+
+// withTTS converts the given response content to a *discordgo.MessageSend with the TTS flag set.
+// The given *discordgo.MessageSend is copied so the caller's value is not modified.
+func withTTS(content any) any {
+	switch typed := content.(type) {
+	case string:
+		// An empty string means no message, e.g., when only a reaction is sent.
+		if typed == "" {
+			return content
+		}
+		return &discordgo.MessageSend{
+			Content: typed,
+			TTS:     true,
+		}
+
+	case *discordgo.MessageSend:
+		copied := *typed
+		copied.TTS = true
+		return &copied
+
+	default:
+		return content
+	}
+}
@@ -0,0 +1,57 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestRespWithTTS(t *testing.T) {
+	tests := []struct {
+		name    string
+		content any
+	}{
+		{name: "string content", content: "alert"},
+		{name: "rich content", content: &discordgo.MessageSend{Content: "alert"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent *discordgo.MessageSend
+			mock := &mockSession{
+				channelMessageSendFunc: func(_ string, _ string, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+					t.Error("ChannelMessageSend should not be called")
+					return &discordgo.Message{}, nil
+				},
+				channelMessageSendComplexFunc: func(_ string, data *discordgo.MessageSend, _ ...discordgo.RequestOption) (*discordgo.Message, error) {
+					sent = data
+					return &discordgo.Message{}, nil
+				},
+			}
+			adapter := &Adapter{config: NewConfig(), session: mock}
+
+			var response *sarah.CommandResponse
+			var err error
+			switch content := tt.content.(type) {
+			case string:
+				response, err = NewResponse(newReplyInput("guild-1"), content, RespWithTTS())
+			case *discordgo.MessageSend:
+				response, err = NewResponse(newReplyInput("guild-1"), content, RespWithTTS())
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %+v", err)
+			}
+
+			adapter.SendMessage(context.Background(), sarah.NewOutputMessage(ChannelID("ch-1"), response.Content))
+
+			if sent == nil || !sent.TTS || sent.Content != "alert" {
+				t.Errorf("Unexpected message: %#v", sent)
+			}
+			if given, ok := tt.content.(*discordgo.MessageSend); ok && given.TTS {
+				t.Error("The given message should not be modified")
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// SendTyping shows the typing indicator in the given channel.
+// Discord shows the indicator for about 10 seconds or until the bot sends a message,
+// so a command that takes longer should call this periodically.
+func (a *Adapter) SendTyping(ctx context.Context, channelID ChannelID) error {
+	err := a.session.ChannelTyping(string(channelID), a.requestOptions(discordgo.WithContext(ctx))...)
+	if err != nil {
+		return fmt.Errorf("failed to send typing indicator to %s: %w", channelID, err)
+	}
+	return nil
+}
+
+// typingTimeout bounds the request to show the typing indicator.
+// Discord shows the indicator for about 10 seconds, so a later one is of no use.
+const typingTimeout = 10 * time.Second
+
+// showTyping shows the typing indicator for the given input when Config.ShowTypingIndicator is true.
+// The request is made in the background so the input is enqueued without waiting for Discord,
+// and is canceled when the Adapter stops.
+// A failure is only logged since the indicator is merely a hint for the user.
+func (a *Adapter) showTyping(input *Input) {
+	if !a.config.ShowTypingIndicator {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(input.Context(), typingTimeout)
+		defer cancel()
+
+		if err := a.SendTyping(ctx, input.channelID); err != nil {
+			a.log().Warnf("[%s] %+v", input.correlationID, err)
+		}
+	}()
+}
@@ -0,0 +1,145 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_SendTyping(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var typedChannelID string
+		mock := &mockSession{
+			channelTypingFunc: func(channelID string, _ ...discordgo.RequestOption) error {
+				typedChannelID = channelID
+				return nil
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.SendTyping(context.Background(), ChannelID("ch-1")); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if typedChannelID != "ch-1" {
+			t.Errorf("Expected typing in %q, got %q", "ch-1", typedChannelID)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		expected := errors.New("forbidden")
+		mock := &mockSession{
+			channelTypingFunc: func(_ string, _ ...discordgo.RequestOption) error {
+				return expected
+			},
+		}
+		adapter := &Adapter{config: NewConfig(), session: mock}
+
+		if err := adapter.SendTyping(context.Background(), ChannelID("ch-1")); !errors.Is(err, expected) {
+			t.Errorf("Expected %v, got %+v", expected, err)
+		}
+	})
+}
+
+func TestAdapter_handleMessage_ShowTypingIndicator(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		guildID string
+		content string
+		typed   bool
+	}{
+		{name: "prefixed message", enabled: true, guildID: "guild-1", content: "!ping", typed: true},
+		{name: "direct message", enabled: true, guildID: "", content: "ping", typed: true},
+		{name: "chat message", enabled: true, guildID: "guild-1", content: "hello", typed: false},
+		{name: "help command", enabled: true, guildID: "guild-1", content: "!.help", typed: false},
+		{name: "disabled", enabled: false, guildID: "guild-1", content: "!ping", typed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typed := make(chan string, 1)
+			mock := &mockSession{
+				channelTypingFunc: func(channelID string, _ ...discordgo.RequestOption) error {
+					typed <- channelID
+					return nil
+				},
+			}
+			config := NewConfig()
+			config.CommandPrefix = "!"
+			config.ShowTypingIndicator = tt.enabled
+			adapter := &Adapter{config: config, session: mock}
+
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "ch-1",
+					GuildID:   tt.guildID,
+					Content:   tt.content,
+					Author:    &discordgo.User{ID: "user-1"},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(sarah.Input) error { return nil })
+
+			if !tt.typed {
+				select {
+				case channelID := <-typed:
+					t.Errorf("Unexpected typing in %s", channelID)
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+			select {
+			case channelID := <-typed:
+				if channelID != "ch-1" {
+					t.Errorf("Expected typing in ch-1, got %s", channelID)
+				}
+			case <-time.After(time.Second):
+				t.Error("Expected typing in ch-1")
+			}
+		})
+	}
+}
+
+func TestAdapter_handleMessage_TypingDoesNotBlockEnqueue(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	release := make(chan struct{})
+	defer close(release)
+	mock := &mockSession{
+		channelTypingFunc: func(_ string, _ ...discordgo.RequestOption) error {
+			<-release
+			return nil
+		},
+	}
+	config := NewConfig()
+	config.CommandPrefix = "!"
+	config.ShowTypingIndicator = true
+	adapter := &Adapter{config: config, session: mock}
+
+	enqueued := make(chan struct{}, 1)
+	m := &discordgo.MessageCreate{
+		Message: &discordgo.Message{
+			ChannelID: "ch-1",
+			Content:   "!ping",
+			Author:    &discordgo.User{ID: "user-1"},
+		},
+	}
+	go adapter.handleMessage(context.Background(), s, m, func(sarah.Input) error {
+		enqueued <- struct{}{}
+		return nil
+	})
+
+	select {
+	case <-enqueued:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the input to be enqueued while the typing request is in progress")
+	}
+}
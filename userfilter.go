@@ -0,0 +1,39 @@
+package discord
+
+import (
+	"sync"
+)
+
+// idSet is the set of IDs listed in the Config, e.g., Config.BlockedUsers, built on the first lookup.
+type idSet struct {
+	once sync.Once
+	ids  map[string]struct{}
+}
+
+// build converts the given list to the set on the first call, so later changes to the list are not reflected.
+func (s *idSet) build(list []string) {
+	s.once.Do(func() {
+		s.ids = make(map[string]struct{}, len(list))
+		for _, id := range list {
+			s.ids[id] = struct{}{}
+		}
+	})
+}
+
+// contains tells if the given ID is in the given list.
+func (s *idSet) contains(list []string, id string) bool {
+	s.build(list)
+	_, ok := s.ids[id]
+	return ok
+}
+
+// empty tells if the given list is empty.
+func (s *idSet) empty(list []string) bool {
+	s.build(list)
+	return len(s.ids) == 0
+}
+
+// userBlocked tells if messages from the given user should be ignored in terms of Config.BlockedUsers.
+func (a *Adapter) userBlocked(userID string) bool {
+	return a.blockedUsers.contains(a.config.BlockedUsers, userID)
+}
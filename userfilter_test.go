@@ -0,0 +1,54 @@
+package discord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_handleMessage_BlockedUsers(t *testing.T) {
+	s := &discordgo.Session{State: discordgo.NewState()}
+	s.State.User = &discordgo.User{ID: "bot-1"}
+
+	tests := []struct {
+		name     string
+		blocked  []string
+		authorID string
+		enqueued bool
+	}{
+		{name: "no list", authorID: "user-1", enqueued: true},
+		{name: "blocked user", blocked: []string{"user-1", "user-2"}, authorID: "user-1", enqueued: false},
+		{name: "user not blocked", blocked: []string{"user-2"}, authorID: "user-1", enqueued: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.BlockedUsers = tt.blocked
+			adapter := &Adapter{config: config, session: &mockSession{}}
+
+			enqueued := false
+			m := &discordgo.MessageCreate{
+				Message: &discordgo.Message{
+					ChannelID: "ch-1",
+					Content:   "hello",
+					Author:    &discordgo.User{ID: tt.authorID},
+				},
+			}
+			adapter.handleMessage(context.Background(), s, m, func(sarah.Input) error {
+				enqueued = true
+				return nil
+			})
+
+			if enqueued != tt.enqueued {
+				t.Errorf("Expected enqueued to be %t, got %t", tt.enqueued, enqueued)
+			}
+
+			if !tt.enqueued && adapter.Stats().Total.Dropped != 1 {
+				t.Errorf("Expected the message to be counted as dropped: %#v", adapter.Stats().Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package discord
+
+import (
+	"context"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// watchActivity registers a handler that records the time of every received gateway event,
+// and returns a channel that periodically ticks to check the connection's liveness.
+// The returned function stops the ticker.
+func (a *Adapter) watchActivity() (<-chan time.Time, func()) {
+	a.session.AddHandler(func(_ *discordgo.Session, _ *discordgo.Event) {
+		a.touch()
+	})
+
+	ticker := time.NewTicker(max(a.config.ZombieTimeout/2, time.Millisecond))
+	return ticker.C, ticker.Stop
+}
+
+// touch records the current time as the last activity of the connection.
+func (a *Adapter) touch() {
+	a.lastActivity.Store(time.Now().UnixNano())
+}
+
+// lastActive returns the time of the last received event or heartbeat acknowledgement, whichever is later.
+func (a *Adapter) lastActive() time.Time {
+	last := time.Unix(0, a.lastActivity.Load())
+
+	if s, ok := a.session.(*discordgo.Session); ok {
+		s.RLock()
+		ack := s.LastHeartbeatAck
+		s.RUnlock()
+
+		if ack.After(last) {
+			last = ack
+		}
+	}
+
+	return last
+}
+
+// isZombie tells if the connection has received nothing for longer than Config.ZombieTimeout.
+func (a *Adapter) isZombie(now time.Time) bool {
+	return now.Sub(a.lastActive()) > a.config.ZombieTimeout
+}
+
+// restart forcibly closes the session and opens it again.
+// Event handlers stay registered to the session, so they are not registered again.
+func (a *Adapter) restart(ctx context.Context) error {
+	// Give the new connection a full timeout before it is regarded as a zombie again.
+	a.touch()
+	return a.reconnect(ctx)
+}
@@ -0,0 +1,171 @@
+package discord
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/oklahomer/go-sarah/v4"
+)
+
+func TestAdapter_Run_ZombieTimeout(t *testing.T) {
+	t.Run("silent session is restarted", func(t *testing.T) {
+		var messageHandlers, closes atomic.Int32
+		opens := make(chan struct{}, 10)
+		mock := &mockSession{
+			addHandlerFunc: func(handler interface{}) func() {
+				if _, ok := handler.(func(*discordgo.Session, *discordgo.MessageCreate)); ok {
+					messageHandlers.Add(1)
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				opens <- struct{}{}
+				return nil
+			},
+			closeFunc: func() error {
+				closes.Add(1)
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ZombieTimeout = 20 * time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			adapter.Run(ctx, func(input sarah.Input) error { return nil }, func(err error) {
+				t.Errorf("Unexpected error: %+v", err)
+			})
+			close(done)
+		}()
+
+		<-opens
+		select {
+		case <-opens:
+		case <-time.After(time.Second):
+			t.Fatal("Session was not restarted")
+		}
+		cancel()
+		<-done
+
+		if closes.Load() < 2 {
+			t.Errorf("Expected the session to be closed before the restart and on shutdown, got %d", closes.Load())
+		}
+		if messageHandlers.Load() != 1 {
+			t.Errorf("Expected the message handler to be registered once, got %d", messageHandlers.Load())
+		}
+	})
+
+	t.Run("active session is kept", func(t *testing.T) {
+		var eventHandler atomic.Value
+		var opens atomic.Int32
+		mock := &mockSession{
+			addHandlerFunc: func(handler interface{}) func() {
+				if h, ok := handler.(func(*discordgo.Session, *discordgo.Event)); ok {
+					eventHandler.Store(h)
+				}
+				return func() {}
+			},
+			openFunc: func() error {
+				opens.Add(1)
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ZombieTimeout = 30 * time.Millisecond
+		adapter := &Adapter{config: config, session: mock}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			adapter.Run(ctx, func(input sarah.Input) error { return nil }, func(err error) {})
+			close(done)
+		}()
+
+		deadline := time.Now().Add(150 * time.Millisecond)
+		for time.Now().Before(deadline) {
+			if h, ok := eventHandler.Load().(func(*discordgo.Session, *discordgo.Event)); ok {
+				h(nil, &discordgo.Event{Type: "MESSAGE_CREATE"})
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		cancel()
+		<-done
+
+		if opens.Load() != 1 {
+			t.Errorf("Expected the session not to be restarted, got %d opens", opens.Load())
+		}
+	})
+}
+
+func TestAdapter_restart(t *testing.T) {
+	t.Run("own close is not regarded as a drop", func(t *testing.T) {
+		var opens int
+		mock := &mockSession{
+			openFunc: func() error {
+				opens++
+				return nil
+			},
+		}
+		config := NewConfig()
+		config.ReconnectOnInvalidSession = true
+		adapter := &Adapter{config: config, session: mock}
+		disconnected := adapter.watchDisconnection()
+
+		if err := adapter.restart(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+
+		if opens != 1 {
+			t.Errorf("Expected the session to be opened once, got %d", opens)
+		}
+		select {
+		case <-disconnected:
+			t.Error("Expected the restart not to queue another reconnection")
+		default:
+		}
+	})
+
+	t.Run("drop is still notified after the restart", func(t *testing.T) {
+		mock := &mockSession{}
+		config := NewConfig()
+		config.ReconnectOnInvalidSession = true
+		adapter := &Adapter{config: config, session: mock}
+		disconnected := adapter.watchDisconnection()
+
+		if err := adapter.restart(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %+v", err)
+		}
+		mock.emitDisconnect()
+
+		select {
+		case <-disconnected:
+		default:
+			t.Error("Expected the drop to be notified")
+		}
+	})
+}
+
+func TestAdapter_isZombie(t *testing.T) {
+	t.Run("heartbeat acknowledgement counts as activity", func(t *testing.T) {
+		s := &discordgo.Session{}
+		config := NewConfig()
+		config.ZombieTimeout = time.Minute
+		adapter := &Adapter{config: config, session: s}
+		adapter.lastActivity.Store(time.Now().Add(-time.Hour).UnixNano())
+
+		s.LastHeartbeatAck = time.Now()
+		if adapter.isZombie(time.Now()) {
+			t.Error("Expected the recent heartbeat acknowledgement to keep the session alive")
+		}
+
+		s.LastHeartbeatAck = time.Now().Add(-2 * time.Minute)
+		if !adapter.isZombie(time.Now()) {
+			t.Error("Expected the session to be regarded as a zombie")
+		}
+	})
+}